@@ -20,6 +20,13 @@ func main() {
 	defer database.CloseDB()
 
 	jobs.RunCleanupJob()
+	jobs.RunSessionCleanupJob()
+	jobs.RunEmailChangeJob()
+	jobs.RunUnverifiedCleanupJob()
+	jobs.RunCharacterCleanupJob()
+	jobs.RunBazaarJob()
+	jobs.RunExperienceHistoryJob()
+	jobs.RunOnlineHistoryJob()
 
 	os.Exit(0)
 }
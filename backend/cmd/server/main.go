@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"codexaac-backend/internal/database"
 	"codexaac-backend/internal/handlers"
+	"codexaac-backend/internal/jobs"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/config"
 	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/utils"
@@ -52,15 +55,39 @@ func main() {
 		} else {
 			log.Println("✅ Stages configuration loaded successfully")
 		}
+
+		itemsPath := filepath.Join(serverPath, "data", "items", "items.xml")
+
+		if err := config.InitItemsConfig(itemsPath); err != nil {
+			log.Printf("⚠️  WARNING: Failed to load items.xml: %v", err)
+			log.Println("   Item names will not be available")
+		} else {
+			log.Println("✅ Item catalog loaded successfully")
+		}
 	} else {
 		log.Println("ℹ️  SERVER_PATH not set, server config will use defaults")
 	}
 
+	if progressPath := os.Getenv("PROGRESS_MAPPING_FILE"); progressPath != "" {
+		if err := config.InitProgressConfig(progressPath); err != nil {
+			log.Printf("⚠️  WARNING: Failed to load progress mapping: %v", err)
+			log.Println("   Achievements, bestiary and charms will not be shown")
+		} else {
+			log.Println("✅ Progress mapping loaded successfully")
+		}
+	}
+
 	if err := database.InitDB(); err != nil {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
 	defer database.CloseDB()
 
+	if interval := handlers.GetOnlinePollInterval(); interval > 0 {
+		go jobs.RunOnlinePoller(interval)
+	} else {
+		log.Println("ℹ️  Online poller disabled (ONLINE_POLL_INTERVAL_SECONDS=0)")
+	}
+
 	r := mux.NewRouter()
 
 	r.Use(middleware.SecurityHeadersMiddleware)
@@ -69,9 +96,40 @@ func main() {
 
 	r.HandleFunc("/api/health", healthHandler).Methods("GET")
 	r.HandleFunc("/api", homeHandler).Methods("GET")
-	r.HandleFunc("/api/login", handlers.LoginHandler).Methods("POST")
-	r.HandleFunc("/api/register", handlers.RegisterHandler).Methods("POST")
+	loginRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "login", Capacity: 10, Refill: 6 * time.Second})
+	registerRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "register", Capacity: 5, Refill: 10 * time.Minute})
+	emailChangeRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "email_change", Capacity: 5, Refill: 10 * time.Minute})
+	verificationRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "verification_resend", Capacity: 3, Refill: 10 * time.Minute})
+	passkeyRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "passkey_register", Capacity: 10, Refill: 10 * time.Minute})
+	recoveryRequestRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "recovery_request", Capacity: 3, Refill: 10 * time.Minute})
+	recoveryResetRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "recovery_reset", Capacity: 10, Refill: 10 * time.Minute})
+	passwordChangeRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "password_change", Capacity: 5, Refill: 10 * time.Minute})
+	// Ranking and highscore pages load dozens of outfits at once
+	outfitRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{Name: "outfit", Capacity: 150, Refill: 500 * time.Millisecond})
+	// Clients over these thresholds have to solve a challenge (proof of work by default) first
+	registerChallenge := middleware.ChallengeMiddleware(middleware.ChallengePolicy{Name: "register", Threshold: 2, Refill: 30 * time.Minute})
+	characterChallenge := middleware.ChallengeMiddleware(middleware.ChallengePolicy{Name: "character_create", Threshold: 3, Refill: 20 * time.Minute})
+	guildChallenge := middleware.ChallengeMiddleware(middleware.ChallengePolicy{Name: "guild_create", Threshold: 1, Refill: time.Hour})
+	clientLoginRateLimit := middleware.RateLimitMiddleware(middleware.RateLimit{
+		Name:      "client_login",
+		Capacity:  10,
+		Refill:    6 * time.Second,
+		OnLimited: handlers.TibiaRateLimited,
+	})
+
+	r.Handle("/api/login", loginRateLimit(http.HandlerFunc(handlers.LoginHandler))).Methods("POST")
+	r.Handle("/api/login/passkey/begin", loginRateLimit(http.HandlerFunc(handlers.BeginPasskeyLoginHandler))).Methods("POST")
+	r.Handle("/api/login/passkey", loginRateLimit(http.HandlerFunc(handlers.PasskeyLoginHandler))).Methods("POST")
+	r.Handle("/api/register", registerRateLimit(registerChallenge(http.HandlerFunc(handlers.RegisterHandler)))).Methods("POST")
 	r.HandleFunc("/api/logout", handlers.LogoutHandler).Methods("POST")
+	r.HandleFunc("/api/auth/refresh", handlers.RefreshTokenHandler).Methods("POST")
+	r.Handle("/api/account/recover/request", recoveryRequestRateLimit(http.HandlerFunc(handlers.RequestRecoveryHandler))).Methods("POST")
+	r.Handle("/api/account/recover/reset", recoveryResetRateLimit(http.HandlerFunc(handlers.ResetPasswordHandler))).Methods("POST")
+	r.Handle("/api/account/recover/key", recoveryResetRateLimit(http.HandlerFunc(handlers.RecoveryKeyResetHandler))).Methods("POST")
+	r.HandleFunc("/api/account/verify", handlers.VerifyEmailHandler).Methods("POST")
+	r.HandleFunc("/api/account/email/lookup", handlers.LookupEmailChangeHandler).Methods("POST")
+	r.HandleFunc("/api/account/email/confirm", handlers.ConfirmEmailChangeHandler).Methods("POST")
+	r.HandleFunc("/api/account/email/cancel", handlers.CancelEmailChangeByTokenHandler).Methods("POST")
 	r.HandleFunc("/api/server/config", handlers.GetServerConfigHandler).Methods("GET")
 	r.HandleFunc("/api/server/stages", handlers.GetStagesConfigHandler).Methods("GET")
 	r.HandleFunc("/api/towns", handlers.GetTownsHandler).Methods("GET")
@@ -83,7 +141,7 @@ func main() {
 	r.HandleFunc("/api/news/{id}", handlers.GetNewsDetailsPublicHandler).Methods("GET")
 	r.HandleFunc("/api/news/{id}/comments", handlers.GetNewsCommentsHandler).Methods("GET")
 
-	r.HandleFunc("/login", handlers.TibiaClientLoginHandler).Methods("POST", "OPTIONS")
+	r.Handle("/login", clientLoginRateLimit(http.HandlerFunc(handlers.TibiaClientLoginHandler))).Methods("POST", "OPTIONS")
 
 	protected := r.PathPrefix("/api").Subrouter()
 	protected.Use(middleware.AuthMiddleware)
@@ -94,16 +152,44 @@ func main() {
 	protected.HandleFunc("/account", handlers.GetAccountHandler).Methods("GET")
 	protected.HandleFunc("/account", handlers.DeleteAccountHandler).Methods("DELETE")
 	protected.HandleFunc("/account/cancel-deletion", handlers.CancelDeletionHandler).Methods("POST")
+	protected.HandleFunc("/account/recovery-key", handlers.GenerateRecoveryKeyHandler).Methods("POST")
+	protected.Handle("/account/password", passwordChangeRateLimit(http.HandlerFunc(handlers.ChangePasswordHandler))).Methods("PUT")
+	protected.Handle("/account/verify/resend", verificationRateLimit(http.HandlerFunc(handlers.ResendVerificationHandler))).Methods("POST")
+	protected.HandleFunc("/account/email", handlers.GetEmailChangeHandler).Methods("GET")
+	protected.Handle("/account/email", emailChangeRateLimit(http.HandlerFunc(handlers.RequestEmailChangeHandler))).Methods("POST")
+	protected.HandleFunc("/account/email", handlers.CancelEmailChangeHandler).Methods("DELETE")
+	protected.HandleFunc("/account/sessions", handlers.GetSessionsHandler).Methods("GET")
+	protected.HandleFunc("/account/sessions", handlers.RevokeAllSessionsHandler).Methods("DELETE")
+	protected.HandleFunc("/account/sessions/{id}", handlers.RevokeSessionHandler).Methods("DELETE")
+	protected.HandleFunc("/account/character-services", handlers.GetCharacterServicesHandler).Methods("GET")
+
+	protected.HandleFunc("/account/passkeys", handlers.GetPasskeysHandler).Methods("GET")
+	protected.Handle("/account/passkeys/register/begin", passkeyRateLimit(http.HandlerFunc(handlers.BeginPasskeyRegistrationHandler))).Methods("POST")
+	protected.HandleFunc("/account/passkeys/register/finish", handlers.FinishPasskeyRegistrationHandler).Methods("POST")
+	protected.HandleFunc("/account/passkeys/{id}", handlers.DeletePasskeyHandler).Methods("DELETE")
 
 	protected.HandleFunc("/account/2fa/status", handlers.Get2FAStatusHandler).Methods("GET")
 	protected.HandleFunc("/account/2fa/enable", handlers.Enable2FAHandler).Methods("POST")
 	protected.HandleFunc("/account/2fa/verify", handlers.Verify2FAHandler).Methods("POST")
 	protected.HandleFunc("/account/2fa/disable", handlers.Disable2FAHandler).Methods("POST")
+	protected.HandleFunc("/account/2fa/backup-codes", handlers.RegenerateBackupCodesHandler).Methods("POST")
 
 	protected.HandleFunc("/characters", handlers.GetCharactersHandler).Methods("GET")
-	protected.HandleFunc("/characters", handlers.CreateCharacterHandler).Methods("POST")
-
-	protected.HandleFunc("/guilds", handlers.CreateGuildHandler).Methods("POST")
+	protected.Handle("/characters", characterChallenge(http.HandlerFunc(handlers.CreateCharacterHandler))).Methods("POST")
+	protected.HandleFunc("/characters/{name}", handlers.DeleteCharacterHandler).Methods("DELETE")
+	protected.HandleFunc("/characters/{name}/cancel-deletion", handlers.CancelCharacterDeletionHandler).Methods("POST")
+	protected.HandleFunc("/characters/{name}/visibility", handlers.SetCharacterVisibilityHandler).Methods("PUT")
+	protected.HandleFunc("/characters/{name}/rename", handlers.RenameCharacterHandler).Methods("POST")
+	protected.HandleFunc("/characters/{name}/change-sex", handlers.ChangeCharacterSexHandler).Methods("POST")
+	protected.HandleFunc("/characters/{name}/profile", handlers.GetCharacterProfileHandler).Methods("GET")
+	protected.HandleFunc("/characters/{name}/profile", handlers.UpdateCharacterProfileHandler).Methods("PUT")
+
+	protected.HandleFunc("/account/bazaar", handlers.GetMyAuctionsHandler).Methods("GET")
+	protected.HandleFunc("/bazaar", handlers.CreateAuctionHandler).Methods("POST")
+	protected.HandleFunc("/bazaar/{id}/bid", handlers.BidAuctionHandler).Methods("POST")
+	protected.HandleFunc("/bazaar/{id}/cancel", handlers.CancelAuctionHandler).Methods("POST")
+
+	protected.Handle("/guilds", guildChallenge(http.HandlerFunc(handlers.CreateGuildHandler))).Methods("POST")
 	protected.HandleFunc("/guilds/invites", handlers.GetPendingInvitesHandler).Methods("GET")
 	protected.HandleFunc("/guilds/{name}/invite", handlers.InvitePlayerHandler).Methods("POST")
 	protected.HandleFunc("/guilds/{name}/accept-invite", handlers.AcceptInviteHandler).Methods("POST")
@@ -111,52 +197,71 @@ func main() {
 	protected.HandleFunc("/guilds/{name}/kick", handlers.KickPlayerHandler).Methods("POST")
 
 	r.HandleFunc("/api/characters/{name}", handlers.GetCharacterDetailsHandler).Methods("GET")
+	r.HandleFunc("/api/characters/{name}/pvp", handlers.GetCharacterPvPHandler).Methods("GET")
+	r.HandleFunc("/api/characters/{name}/history", handlers.GetCharacterHistoryHandler).Methods("GET")
+	r.HandleFunc("/api/characters/{name}/online-time", handlers.GetCharacterOnlineTimeHandler).Methods("GET")
 	r.HandleFunc("/api/players/online", handlers.GetOnlinePlayersHandler).Methods("GET")
 	r.HandleFunc("/api/ranking", handlers.GetRankingHandler).Methods("GET")
 	r.HandleFunc("/api/team", handlers.GetTeamHandler).Methods("GET")
 	r.HandleFunc("/api/deaths", handlers.GetDeathsHandler).Methods("GET")
+	r.HandleFunc("/api/top-fraggers", handlers.GetTopFraggersHandler).Methods("GET")
+	r.Handle("/api/outfit.png", outfitRateLimit(http.HandlerFunc(handlers.GetOutfitImageHandler))).Methods("GET")
 	r.HandleFunc("/api/changelogs", handlers.GetChangelogsHandler).Methods("GET")
 	r.HandleFunc("/api/guilds", handlers.GetGuildsHandler).Methods("GET")
 	r.HandleFunc("/api/boosted", handlers.GetBoostedHandler).Methods("GET")
 	r.HandleFunc("/api/banishments", handlers.GetBanishmentsHandler).Methods("GET")
+	r.HandleFunc("/api/bazaar", handlers.GetAuctionsHandler).Methods("GET")
 
 	guildDetailsRouter := r.PathPrefix("/api/guilds/{name}").Subrouter()
 	guildDetailsRouter.Use(middleware.OptionalAuthMiddleware)
 	guildDetailsRouter.HandleFunc("", handlers.GetGuildDetailsHandler).Methods("GET")
 
+	auctionDetailsRouter := r.PathPrefix("/api/bazaar/{id:[0-9]+}").Subrouter()
+	auctionDetailsRouter.Use(middleware.OptionalAuthMiddleware)
+	auctionDetailsRouter.HandleFunc("", handlers.GetAuctionHandler).Methods("GET")
+
 	admin := r.PathPrefix("/api/admin").Subrouter()
 	admin.Use(middleware.AuthMiddleware)
 	admin.Use(middleware.AdminMiddleware)
 
-	admin.HandleFunc("/stats", handlers.GetAdminStatsHandler).Methods("GET")
-	admin.HandleFunc("/accounts", handlers.GetAdminAccountsHandler).Methods("GET")
-	admin.HandleFunc("/account", handlers.GetAdminAccountDetailsHandler).Methods("GET")
-	admin.HandleFunc("/account", handlers.UpdateAdminAccountHandler).Methods("PUT")
-	admin.HandleFunc("/account/sql", handlers.ExecuteAdminSQLHandler).Methods("POST")
-	admin.HandleFunc("/players", handlers.GetAdminPlayersHandler).Methods("GET")
-	admin.HandleFunc("/player", handlers.GetAdminPlayerDetailsHandler).Methods("GET")
-	admin.HandleFunc("/player", handlers.UpdateAdminPlayerHandler).Methods("PUT")
-	admin.HandleFunc("/player/sql", handlers.ExecuteAdminPlayerSQLHandler).Methods("POST")
-	admin.HandleFunc("/maintenance", handlers.GetMaintenanceStatusHandler).Methods("GET")
-	admin.HandleFunc("/maintenance", handlers.ToggleMaintenanceHandler).Methods("POST")
-	admin.HandleFunc("/changelogs", handlers.CreateChangelogHandler).Methods("POST")
-	admin.HandleFunc("/changelogs/{id}", handlers.DeleteChangelogHandler).Methods("DELETE")
-	admin.HandleFunc("/pages/rules", handlers.UpdateRulesHandler).Methods("PUT")
-	admin.HandleFunc("/news", handlers.CreateNewsHandler).Methods("POST")
-	admin.HandleFunc("/news", handlers.GetNewsHandler).Methods("GET")
-	admin.HandleFunc("/news/comments/count", handlers.GetRecentCommentsCountHandler).Methods("GET")
-	admin.HandleFunc("/news/comments/unread", handlers.GetUnreadCommentsHandler).Methods("GET")
-	admin.HandleFunc("/news/comments/{id}/read", handlers.MarkCommentAsReadHandler).Methods("POST")
-	admin.HandleFunc("/news/comments/read-all", handlers.MarkAllCommentsAsReadHandler).Methods("POST")
-	admin.HandleFunc("/news/comments", handlers.GetAllNewsCommentsHandler).Methods("GET")
-	admin.HandleFunc("/news/comments/{id}", handlers.DeleteNewsCommentHandler).Methods("DELETE")
-	admin.HandleFunc("/news/{id}", handlers.GetNewsDetailsHandler).Methods("GET")
-	admin.HandleFunc("/news/{id}", handlers.UpdateNewsHandler).Methods("PUT")
-	admin.HandleFunc("/news/{id}", handlers.DeleteNewsHandler).Methods("DELETE")
-	admin.HandleFunc("/news/{id}/comments", handlers.GetNewsCommentsHandler).Methods("GET")
-	admin.HandleFunc("/news/{id}/comments", handlers.CreateNewsCommentHandler).Methods("POST")
-	admin.HandleFunc("/logs", handlers.GetLogsListHandler).Methods("GET")
-	admin.HandleFunc("/logs/content", handlers.GetLogContentHandler).Methods("GET")
+	admin.Handle("/stats", middleware.RequirePermission(auth.PermDashboardView, handlers.GetAdminStatsHandler)).Methods("GET")
+	admin.Handle("/accounts", middleware.RequirePermission(auth.PermAccountsView, handlers.GetAdminAccountsHandler)).Methods("GET")
+	admin.Handle("/account", middleware.RequirePermission(auth.PermAccountsView, handlers.GetAdminAccountDetailsHandler)).Methods("GET")
+	admin.Handle("/account", middleware.RequirePermission(auth.PermAccountsEdit, handlers.UpdateAdminAccountHandler)).Methods("PUT")
+	admin.Handle("/account/sql", middleware.RequirePermission(auth.PermSQLExecute, handlers.ExecuteAdminSQLHandler)).Methods("POST")
+	admin.Handle("/accounts/bulk-edit", middleware.RequirePermission(auth.PermAccountsEdit, handlers.BulkEditAccountsHandler)).Methods("POST")
+	admin.Handle("/players", middleware.RequirePermission(auth.PermPlayersView, handlers.GetAdminPlayersHandler)).Methods("GET")
+	admin.Handle("/player", middleware.RequirePermission(auth.PermPlayersView, handlers.GetAdminPlayerDetailsHandler)).Methods("GET")
+	admin.Handle("/player", middleware.RequirePermission(auth.PermPlayersEdit, handlers.UpdateAdminPlayerHandler)).Methods("PUT")
+	admin.Handle("/players/bulk-edit", middleware.RequirePermission(auth.PermPlayersEdit, handlers.BulkEditPlayersHandler)).Methods("POST")
+	admin.Handle("/player/sql", middleware.RequirePermission(auth.PermSQLExecute, handlers.ExecuteAdminPlayerSQLHandler)).Methods("POST")
+	admin.Handle("/maintenance", middleware.RequirePermission(auth.PermMaintenance, handlers.GetMaintenanceStatusHandler)).Methods("GET")
+	admin.Handle("/maintenance", middleware.RequirePermission(auth.PermMaintenance, handlers.ToggleMaintenanceHandler)).Methods("POST")
+	admin.Handle("/changelogs", middleware.RequirePermission(auth.PermChangelogsManage, handlers.CreateChangelogHandler)).Methods("POST")
+	admin.Handle("/changelogs/{id}", middleware.RequirePermission(auth.PermChangelogsManage, handlers.DeleteChangelogHandler)).Methods("DELETE")
+	admin.Handle("/pages/rules", middleware.RequirePermission(auth.PermPagesManage, handlers.UpdateRulesHandler)).Methods("PUT")
+	admin.Handle("/news", middleware.RequirePermission(auth.PermNewsManage, handlers.CreateNewsHandler)).Methods("POST")
+	admin.Handle("/news", middleware.RequirePermission(auth.PermNewsManage, handlers.GetNewsHandler)).Methods("GET")
+	admin.Handle("/news/comments/count", middleware.RequirePermission(auth.PermCommentsModerate, handlers.GetRecentCommentsCountHandler)).Methods("GET")
+	admin.Handle("/news/comments/unread", middleware.RequirePermission(auth.PermCommentsModerate, handlers.GetUnreadCommentsHandler)).Methods("GET")
+	admin.Handle("/news/comments/{id}/read", middleware.RequirePermission(auth.PermCommentsModerate, handlers.MarkCommentAsReadHandler)).Methods("POST")
+	admin.Handle("/news/comments/read-all", middleware.RequirePermission(auth.PermCommentsModerate, handlers.MarkAllCommentsAsReadHandler)).Methods("POST")
+	admin.Handle("/news/comments", middleware.RequirePermission(auth.PermCommentsModerate, handlers.GetAllNewsCommentsHandler)).Methods("GET")
+	admin.Handle("/news/comments/{id}", middleware.RequirePermission(auth.PermCommentsModerate, handlers.DeleteNewsCommentHandler)).Methods("DELETE")
+	admin.Handle("/news/{id}", middleware.RequirePermission(auth.PermNewsManage, handlers.GetNewsDetailsHandler)).Methods("GET")
+	admin.Handle("/news/{id}", middleware.RequirePermission(auth.PermNewsManage, handlers.UpdateNewsHandler)).Methods("PUT")
+	admin.Handle("/news/{id}", middleware.RequirePermission(auth.PermNewsManage, handlers.DeleteNewsHandler)).Methods("DELETE")
+	admin.Handle("/news/{id}/comments", middleware.RequirePermission(auth.PermCommentsModerate, handlers.GetNewsCommentsHandler)).Methods("GET")
+	admin.Handle("/news/{id}/comments", middleware.RequirePermission(auth.PermCommentsModerate, handlers.CreateNewsCommentHandler)).Methods("POST")
+	admin.Handle("/logs", middleware.RequirePermission(auth.PermLogsView, handlers.GetLogsListHandler)).Methods("GET")
+	admin.Handle("/logs/content", middleware.RequirePermission(auth.PermLogsView, handlers.GetLogContentHandler)).Methods("GET")
+	admin.Handle("/lockouts", middleware.RequirePermission(auth.PermLockoutsManage, handlers.GetLockoutsHandler)).Methods("GET")
+	admin.Handle("/lockouts", middleware.RequirePermission(auth.PermLockoutsManage, handlers.ClearLockoutHandler)).Methods("DELETE")
+	admin.Handle("/audit", middleware.RequirePermission(auth.PermAuditView, handlers.GetAuditLogHandler)).Methods("GET")
+	admin.Handle("/bazaar/{id}/events", middleware.RequirePermission(auth.PermAuditView, handlers.GetAuctionEventsHandler)).Methods("GET")
+	admin.Handle("/roles", middleware.RequirePermission(auth.PermRolesManage, handlers.GetRolesHandler)).Methods("GET")
+	admin.Handle("/account/roles", middleware.RequirePermission(auth.PermRolesManage, handlers.GetAccountRolesHandler)).Methods("GET")
+	admin.Handle("/account/roles", middleware.RequirePermission(auth.PermRolesManage, handlers.UpdateAccountRolesHandler)).Methods("PUT")
 
 	port := os.Getenv("PORT")
 	if port == "" {
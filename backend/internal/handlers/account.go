@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/config"
 	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/utils"
@@ -28,6 +29,7 @@ type AccountInfo struct {
 	DeletionScheduledAt    *int64 `json:"deletionScheduledAt,omitempty"`
 	Status                 string `json:"status"`
 	PageAccess             int    `json:"page_access"`
+	Permissions            []string `json:"permissions"`
 }
 
 type DeleteAccountRequest struct {
@@ -37,6 +39,8 @@ type DeleteAccountRequest struct {
 const (
 	AccountStatusActive         = "active"
 	AccountStatusPendingDeletion = "pending_deletion"
+	// AccountStatusUnverified is set on new accounts when EMAIL_VERIFICATION_REQUIRED=true
+	AccountStatusUnverified = "unverified"
 	DefaultDeletionGracePeriodDays = 30
 )
 
@@ -170,6 +174,11 @@ func GetAccountHandler(w http.ResponseWriter, r *http.Request) {
 		LoyaltyTitle:           loyaltyTitleFormatted,
 		Status:                 status,
 		PageAccess:             pageAccess,
+		Permissions:            []string{},
+	}
+
+	if permissions, err := auth.GetAccountPermissions(ctx, userID); err == nil {
+		accountInfo.Permissions = permissions.List()
 	}
 
 	if vipExpiry != "" {
@@ -244,8 +253,13 @@ func DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hashedPassword := utils.HashSHA1(req.Password)
-	if storedPassword != hashedPassword {
+	// Cancelling a deletion makes the account active, which would skip verification
+	if currentStatus == AccountStatusUnverified {
+		utils.WriteError(w, http.StatusBadRequest, "Unverified accounts are deleted automatically if they are not activated")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
 		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
 		return
 	}
@@ -273,6 +287,27 @@ func DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var inBazaar bool
+	err = database.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM character_auctions
+			WHERE status = ? AND (seller_account_id = ? OR highest_bidder_account_id = ?)
+		)`,
+		AuctionStatusActive, userID, userID,
+	).Scan(&inBazaar)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error checking auctions")
+		return
+	}
+
+	if inBazaar {
+		utils.WriteError(w, http.StatusBadRequest, "Cannot delete account while it has a running auction or the highest bid on one")
+		return
+	}
+
 	gracePeriodDays := getDeletionGracePeriodDays()
 	deletionTime := time.Now().AddDate(0, 0, gracePeriodDays).Unix()
 
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/config"
+	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/utils"
 )
 
@@ -390,7 +392,7 @@ type UpdateAdminAccountRequest struct {
 	Coins             *int  `json:"coins"`
 	CoinsTransferable *int  `json:"coinsTransferable"`
 	Status            *int  `json:"status"`
-	IsAdmin           *bool `json:"isAdmin"`
+	IsAdmin           *bool `json:"isAdmin"` // Grants or removes super_admin, prefer PUT /api/admin/account/roles
 }
 
 func UpdateAdminAccountHandler(w http.ResponseWriter, r *http.Request) {
@@ -446,6 +448,15 @@ func UpdateAdminAccountHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.IsAdmin != nil {
+		if !middleware.HasPermission(r, auth.PermRolesManage) {
+			utils.WriteError(w, http.StatusForbidden, "You don't have permission to change admin access")
+			return
+		}
+		// Same rule as UpdateAccountRolesHandler, or the last super admin could lock everyone out
+		if userID, _ := r.Context().Value(middleware.UserIDKey).(int); accountID == userID {
+			utils.WriteError(w, http.StatusBadRequest, "You cannot change your own roles")
+			return
+		}
 		pageAccess := 0
 		if *req.IsAdmin {
 			pageAccess = 1
@@ -459,10 +470,13 @@ func UpdateAdminAccountHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	columns := updateColumns(updates)
+	before := loadAuditSnapshot(ctx, "accounts", accountID, columns)
+	after := updateValues(columns, args)
+
 	args = append(args, accountID)
 
-	query := "UPDATE accounts SET " + strings.Join(updates, ", ") + " WHERE id = ?"
-	_, err = database.DB.ExecContext(ctx, query, args...)
+	tx, err := database.DB.BeginTx(ctx, nil)
 	if err != nil {
 		if utils.HandleDBError(w, err) {
 			return
@@ -470,6 +484,42 @@ func UpdateAdminAccountHandler(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusInternalServerError, "Error updating account")
 		return
 	}
+	defer tx.Rollback()
+
+	if req.IsAdmin != nil && !*req.IsAdmin {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM account_roles WHERE account_id = ? AND role = ?", accountID, auth.RoleSuperAdmin); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error updating account")
+			return
+		}
+	}
+
+	query := "UPDATE accounts SET " + strings.Join(updates, ", ") + " WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating account")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating account")
+		return
+	}
+
+	recordAdminAction(r, AuditEntry{
+		Action:     "account.update",
+		TargetType: "account",
+		TargetID:   strconv.Itoa(accountID),
+		Before:     before,
+		After:      after,
+	})
 
 	utils.WriteSuccess(w, http.StatusOK, "Account updated successfully", nil)
 }
@@ -478,7 +528,18 @@ type ExecuteAdminSQLRequest struct {
 	SQL string `json:"sql"`
 }
 
+// rawSQLEnabled reports whether the raw SQL endpoints are switched on. They are off by default;
+// the bulk edit endpoints cover routine changes with parameterized queries.
+func rawSQLEnabled() bool {
+	return os.Getenv("ADMIN_RAW_SQL_ENABLED") == "true"
+}
+
 func ExecuteAdminSQLHandler(w http.ResponseWriter, r *http.Request) {
+	if !rawSQLEnabled() {
+		utils.WriteError(w, http.StatusForbidden, "Raw SQL is disabled. Use the bulk edit tool or set ADMIN_RAW_SQL_ENABLED=true")
+		return
+	}
+
 	accountIDStr := r.URL.Query().Get("id")
 	if accountIDStr == "" {
 		utils.WriteError(w, http.StatusBadRequest, "Account ID is required")
@@ -542,6 +603,13 @@ func ExecuteAdminSQLHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	recordAdminAction(r, AuditEntry{
+		Action:     "account.sql",
+		TargetType: "account",
+		TargetID:   strconv.Itoa(accountID),
+		After:      map[string]interface{}{"sql": req.SQL, "rowsAffected": rowsAffected},
+	})
+
 	response := map[string]interface{}{
 		"rowsAffected": rowsAffected,
 		"message":      "SQL executed successfully",
@@ -1003,6 +1071,10 @@ func UpdateAdminPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	columns := updateColumns(updates)
+	before := loadAuditSnapshot(ctx, "players", playerID, columns)
+	after := updateValues(columns, args)
+
 	args = append(args, playerID)
 
 	query := "UPDATE players SET " + strings.Join(updates, ", ") + " WHERE id = ?"
@@ -1015,10 +1087,23 @@ func UpdateAdminPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "player.update",
+		TargetType: "player",
+		TargetID:   strconv.Itoa(playerID),
+		Before:     before,
+		After:      after,
+	})
+
 	utils.WriteSuccess(w, http.StatusOK, "Player updated successfully", nil)
 }
 
 func ExecuteAdminPlayerSQLHandler(w http.ResponseWriter, r *http.Request) {
+	if !rawSQLEnabled() {
+		utils.WriteError(w, http.StatusForbidden, "Raw SQL is disabled. Use the bulk edit tool or set ADMIN_RAW_SQL_ENABLED=true")
+		return
+	}
+
 	playerIDStr := r.URL.Query().Get("id")
 	if playerIDStr == "" {
 		utils.WriteError(w, http.StatusBadRequest, "Player ID is required")
@@ -1082,6 +1167,13 @@ func ExecuteAdminPlayerSQLHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	recordAdminAction(r, AuditEntry{
+		Action:     "player.sql",
+		TargetType: "player",
+		TargetID:   strconv.Itoa(playerID),
+		After:      map[string]interface{}{"sql": req.SQL, "rowsAffected": rowsAffected},
+	})
+
 	response := map[string]interface{}{
 		"rowsAffected": rowsAffected,
 		"message":      "SQL executed successfully",
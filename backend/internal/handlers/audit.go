@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 100
+)
+
+// AuditEntry describes a privileged action for recordAdminAction.
+// Before and After hold the affected fields; only the ones that changed are stored.
+type AuditEntry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     map[string]interface{}
+	After      map[string]interface{}
+}
+
+// AuditChange is one field of the stored before/after diff
+type AuditChange struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditLogEntry is an admin_audit_log row as returned by the API
+type AuditLogEntry struct {
+	ID         int64                  `json:"id"`
+	ActorID    int                    `json:"actorId"`
+	ActorEmail string                 `json:"actorEmail"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"targetType"`
+	TargetID   string                 `json:"targetId"`
+	Changes    map[string]AuditChange `json:"changes"`
+	IP         string                 `json:"ip"`
+	CreatedAt  string                 `json:"createdAt"`
+}
+
+// recordAdminAction writes an entry to admin_audit_log for the account behind the request.
+// Failures are logged and never block the action that was already performed.
+func recordAdminAction(r *http.Request, entry AuditEntry) {
+	actorID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	writeAuditLog(actorID, utils.GetClientIP(r), entry)
+}
+
+// writeAuditLog stores an audit entry for actions that don't happen inside an admin request
+func writeAuditLog(actorID int, ip string, entry AuditEntry) {
+	changes, err := json.Marshal(diffAuditFields(entry.Before, entry.After))
+	if err != nil {
+		log.Printf("Error encoding audit changes for %s: %v", entry.Action, err)
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	_, err = database.DB.ExecContext(ctx,
+		`INSERT INTO admin_audit_log (actor_id, action, target_type, target_id, changes, ip, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		actorID, entry.Action, entry.TargetType, entry.TargetID, string(changes), ip, time.Now().Unix(),
+	)
+	if err != nil {
+		log.Printf("Error writing audit log for %s by account %d: %v", entry.Action, actorID, err)
+	}
+}
+
+func diffAuditFields(before, after map[string]interface{}) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+	for field, newValue := range after {
+		oldValue, existed := before[field]
+		if existed && fmt.Sprint(oldValue) == fmt.Sprint(newValue) {
+			continue
+		}
+		changes[field] = AuditChange{Before: oldValue, After: newValue}
+	}
+	for field, oldValue := range before {
+		if _, ok := after[field]; !ok {
+			changes[field] = AuditChange{Before: oldValue}
+		}
+	}
+	return changes
+}
+
+// updateColumns extracts the column names from "column = ?" fragments built by the update handlers
+func updateColumns(updates []string) []string {
+	columns := make([]string, len(updates))
+	for i, update := range updates {
+		columns[i] = strings.TrimSuffix(update, " = ?")
+	}
+	return columns
+}
+
+// loadAuditSnapshot reads the current values of columns before an update so they can be diffed.
+// columns must come from code, never from user input.
+func loadAuditSnapshot(ctx context.Context, table string, id int, columns []string) map[string]interface{} {
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM " + table + " WHERE id = ?"
+	if err := database.DB.QueryRowContext(ctx, query, id).Scan(pointers...); err != nil {
+		log.Printf("Error loading audit snapshot from %s %d: %v", table, id, err)
+		return nil
+	}
+
+	snapshot := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if raw, ok := values[i].([]byte); ok {
+			snapshot[column] = string(raw)
+		} else {
+			snapshot[column] = values[i]
+		}
+	}
+	return snapshot
+}
+
+// updateValues pairs the columns of an update with the values that were written
+func updateValues(columns []string, args []interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		values[column] = args[i]
+	}
+	return values
+}
+
+// GetAuditLogHandler lists admin audit log entries, newest first.
+// Filters: actor (account id or email), action (prefix), targetType, targetId, from and to (YYYY-MM-DD).
+func GetAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page := 1
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	limit := DefaultAuditLimit
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && l <= MaxAuditLimit {
+		limit = l
+	}
+	offset := (page - 1) * limit
+
+	conditions := []string{}
+	args := []interface{}{}
+
+	if actor := strings.TrimSpace(query.Get("actor")); actor != "" {
+		if actorID, err := strconv.Atoi(actor); err == nil {
+			conditions = append(conditions, "l.actor_id = ?")
+			args = append(args, actorID)
+		} else {
+			conditions = append(conditions, "a.email = ?")
+			args = append(args, utils.SanitizeString(actor, 255))
+		}
+	}
+	if action := strings.TrimSpace(query.Get("action")); action != "" {
+		conditions = append(conditions, "l.action LIKE ?")
+		args = append(args, utils.SanitizeString(action, 64)+"%")
+	}
+	if targetType := strings.TrimSpace(query.Get("targetType")); targetType != "" {
+		conditions = append(conditions, "l.target_type = ?")
+		args = append(args, utils.SanitizeString(targetType, 32))
+	}
+	if targetID := strings.TrimSpace(query.Get("targetId")); targetID != "" {
+		conditions = append(conditions, "l.target_id = ?")
+		args = append(args, utils.SanitizeString(targetID, 320))
+	}
+	if from := query.Get("from"); from != "" {
+		fromDate, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+		conditions = append(conditions, "l.created_at >= ?")
+		args = append(args, fromDate.Unix())
+	}
+	if to := query.Get("to"); to != "" {
+		toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+		conditions = append(conditions, "l.created_at < ?")
+		args = append(args, toDate.AddDate(0, 0, 1).Unix())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var total int
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM admin_audit_log l LEFT JOIN accounts a ON a.id = l.actor_id"+where,
+		args...,
+	).Scan(&total)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching audit log")
+		return
+	}
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT l.id, l.actor_id, COALESCE(a.email, ''), l.action, l.target_type, COALESCE(l.target_id, ''),
+		        COALESCE(l.changes, ''), COALESCE(l.ip, ''), l.created_at
+		 FROM admin_audit_log l
+		 LEFT JOIN accounts a ON a.id = l.actor_id`+where+`
+		 ORDER BY l.id DESC
+		 LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching audit log")
+		return
+	}
+	defer rows.Close()
+
+	entries := []AuditLogEntry{}
+	for rows.Next() {
+		var entry AuditLogEntry
+		var changes string
+		var createdAt int64
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorEmail, &entry.Action, &entry.TargetType,
+			&entry.TargetID, &changes, &entry.IP, &createdAt); err != nil {
+			continue
+		}
+		if changes != "" {
+			_ = json.Unmarshal([]byte(changes), &entry.Changes)
+		}
+		entry.CreatedAt = time.Unix(createdAt, 0).Format("Jan 2, 2006, 15:04:05")
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching audit log")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Audit log retrieved successfully", map[string]interface{}{
+		"entries": entries,
+		"pagination": map[string]interface{}{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + limit - 1) / limit,
+		},
+	})
+}
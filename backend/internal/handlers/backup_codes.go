@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/twofactor"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	BackupCodeCount = 10
+
+	backupCodeGroups = 2
+	// maxTwoFactorTokenLength fits a TOTP token or a backup code typed with dashes or spaces
+	maxTwoFactorTokenLength = 32
+)
+
+type RegenerateBackupCodesRequest struct {
+	Password string `json:"password"`
+	Token    string `json:"token"` // 2FA token or an unused backup code
+}
+
+// RegenerateBackupCodesHandler replaces the 2FA backup codes of the logged in account.
+// The new codes are only shown once; the previous ones stop working.
+func RegenerateBackupCodesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req RegenerateBackupCodesRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if len(req.Password) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+	if len(req.Token) > maxTwoFactorTokenLength {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid 2FA token format")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword, secret string
+	err := database.DB.QueryRowContext(ctx, "SELECT password, COALESCE(secret, '') FROM accounts WHERE id = ?", userID).Scan(&storedPassword, &secret)
+	if err == sql.ErrNoRows {
+		utils.WriteError(w, http.StatusNotFound, "Account not found")
+		return
+	} else if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	if secret == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if !verifyTwoFactor(ctx, userID, secret, req.Token) {
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
+		return
+	}
+
+	codes, err := generateBackupCodes(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Failed to generate backup codes")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "New backup codes generated. Store them in a safe place, they will not be shown again", map[string][]string{
+		"backupCodes": codes,
+	})
+}
+
+// verifyTwoFactor accepts a current TOTP token or one of the account's unused backup codes.
+// A backup code is used up by a successful check.
+func verifyTwoFactor(ctx context.Context, accountID int, secret, token string) bool {
+	if token == "" || len(token) > maxTwoFactorTokenLength {
+		return false
+	}
+
+	if len(token) == 6 {
+		return twofactor.ValidateToken(secret, token)
+	}
+
+	used, err := consumeBackupCode(ctx, accountID, token)
+	if err != nil {
+		log.Printf("Error checking backup code for account %d: %v", accountID, err)
+		return false
+	}
+	return used
+}
+
+// generateBackupCodes replaces the backup codes of an account and returns the plain codes
+func generateBackupCodes(ctx context.Context, accountID int) ([]string, error) {
+	codes := make([]string, BackupCodeCount)
+	for i := range codes {
+		code, err := generateGroupedCode(backupCodeGroups)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM account_backup_codes WHERE account_id = ?", accountID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	for _, code := range codes {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO account_backup_codes (account_id, code_hash, created_at) VALUES (?, ?, ?)",
+			accountID, utils.HashSHA256(normalizeRecoveryKey(code)), now,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// consumeBackupCode marks an unused backup code as used. Returns false when it doesn't match.
+func consumeBackupCode(ctx context.Context, accountID int, code string) (bool, error) {
+	result, err := database.DB.ExecContext(ctx,
+		"UPDATE account_backup_codes SET used_at = ? WHERE account_id = ? AND code_hash = ? AND used_at IS NULL",
+		time.Now().Unix(), accountID, utils.HashSHA256(normalizeRecoveryKey(code)),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+func countBackupCodes(ctx context.Context, accountID int) (int, error) {
+	var count int
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM account_backup_codes WHERE account_id = ? AND used_at IS NULL",
+		accountID,
+	).Scan(&count)
+	return count, err
+}
+
+func deleteBackupCodes(ctx context.Context, accountID int) {
+	if _, err := database.DB.ExecContext(ctx, "DELETE FROM account_backup_codes WHERE account_id = ?", accountID); err != nil {
+		log.Printf("Error deleting backup codes for account %d: %v", accountID, err)
+	}
+}
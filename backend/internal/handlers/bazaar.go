@@ -0,0 +1,854 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/config"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	AuctionStatusActive    = "active"
+	AuctionStatusSold      = "sold"
+	AuctionStatusExpired   = "expired"
+	AuctionStatusCancelled = "cancelled"
+	// AuctionStatusFailed is set when a finished auction could not be settled, e.g. the character was deleted
+	AuctionStatusFailed = "failed"
+
+	// Events stored in character_auction_events
+	AuctionEventCreated   = "created"
+	AuctionEventBid       = "bid"
+	AuctionEventRefund    = "refund"
+	AuctionEventCancelled = "cancelled"
+	AuctionEventSold      = "sold"
+	AuctionEventExpired   = "expired"
+	AuctionEventFailed    = "failed"
+
+	DefaultBazaarMaxAuctionDays = 7
+	DefaultBazaarLimit          = 20
+	MaxBazaarLimit              = 50
+)
+
+// notOnAuctionCondition leaves out characters with an active auction. Requires players aliased as p.
+const notOnAuctionCondition = "NOT EXISTS (SELECT 1 FROM character_auctions ca WHERE ca.player_id = p.id AND ca.status = 'active')"
+
+type CreateAuctionRequest struct {
+	Character    string `json:"character"`
+	MinimumBid   int    `json:"minimumBid"`
+	DurationDays int    `json:"durationDays"`
+	Password     string `json:"password"`
+}
+
+type BidAuctionRequest struct {
+	Amount int `json:"amount"`
+}
+
+type Auction struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Level      int    `json:"level"`
+	Vocation   string `json:"vocation"`
+	LookType   int    `json:"lookType"`
+	LookHead   int    `json:"lookHead"`
+	LookBody   int    `json:"lookBody"`
+	LookLegs   int    `json:"lookLegs"`
+	LookFeet   int    `json:"lookFeet"`
+	LookAddons int    `json:"lookAddons"`
+	MinimumBid int    `json:"minimumBid"`
+	CurrentBid int    `json:"currentBid"`
+	BidCount   int    `json:"bidCount"`
+	Status     string `json:"status"`
+	CreatedAt  int64  `json:"createdAt"`
+	EndsAt     int64  `json:"endsAt"`
+	// IsSeller and IsHighestBidder are only set for the logged in account
+	IsSeller        bool `json:"isSeller,omitempty"`
+	IsHighestBidder bool `json:"isHighestBidder,omitempty"`
+
+	playerID int
+}
+
+type AuctionBid struct {
+	Amount int   `json:"amount"`
+	Time   int64 `json:"time"`
+}
+
+type AuctionDetailsResponse struct {
+	Auction   Auction                   `json:"auction"`
+	Character *CharacterDetailsResponse `json:"character"`
+	Bids      []AuctionBid              `json:"bids"`
+}
+
+type AuctionsResponse struct {
+	Auctions   []Auction `json:"auctions"`
+	Pagination struct {
+		Page       int `json:"page"`
+		Limit      int `json:"limit"`
+		Total      int `json:"total"`
+		TotalPages int `json:"totalPages"`
+	} `json:"pagination"`
+}
+
+type AuctionEvent struct {
+	ID         int64  `json:"id"`
+	AuctionID  int    `json:"auctionId"`
+	AccountID  int    `json:"accountId,omitempty"`
+	Event      string `json:"event"`
+	FromStatus string `json:"fromStatus,omitempty"`
+	ToStatus   string `json:"toStatus"`
+	Amount     int    `json:"amount"`
+	IP         string `json:"ip,omitempty"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// auctionColumns are scanned by scanAuction; requires character_auctions aliased as ca and players as p
+const auctionColumns = `ca.id, p.name, p.level, p.vocation,
+	COALESCE(p.looktype, 128), COALESCE(p.lookhead, 0), COALESCE(p.lookbody, 0),
+	COALESCE(p.looklegs, 0), COALESCE(p.lookfeet, 0), COALESCE(p.lookaddons, 0),
+	ca.minimum_bid, ca.current_bid, ca.bid_count, ca.status, ca.created_at, ca.ends_at,
+	ca.seller_account_id, COALESCE(ca.highest_bidder_account_id, 0), ca.player_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAuction reads a row selected with auctionColumns; accountID marks the auctions of the logged in account
+func scanAuction(row rowScanner, accountID int) (Auction, error) {
+	var a Auction
+	var vocationID, sellerID, bidderID int
+	err := row.Scan(
+		&a.ID, &a.Name, &a.Level, &vocationID,
+		&a.LookType, &a.LookHead, &a.LookBody, &a.LookLegs, &a.LookFeet, &a.LookAddons,
+		&a.MinimumBid, &a.CurrentBid, &a.BidCount, &a.Status, &a.CreatedAt, &a.EndsAt,
+		&sellerID, &bidderID, &a.playerID,
+	)
+	if err != nil {
+		return a, err
+	}
+	a.Vocation = config.GetVocationName(vocationID)
+	if accountID > 0 {
+		a.IsSeller = sellerID == accountID
+		a.IsHighestBidder = bidderID == accountID
+	}
+	return a, nil
+}
+
+// GetBazaarMaxAuctionDays returns the longest auction a seller can choose (BAZAAR_MAX_AUCTION_DAYS)
+func GetBazaarMaxAuctionDays() int {
+	days, err := strconv.Atoi(os.Getenv("BAZAAR_MAX_AUCTION_DAYS"))
+	if err != nil || days < 1 {
+		return DefaultBazaarMaxAuctionDays
+	}
+	return days
+}
+
+// RecordAuctionEvent adds an entry to the audit trail of an auction inside the transaction that made the change.
+// accountID is 0 for changes made by the settlement job.
+func RecordAuctionEvent(ctx context.Context, tx *sql.Tx, event AuctionEvent) error {
+	var accountID sql.NullInt64
+	if event.AccountID > 0 {
+		accountID = sql.NullInt64{Int64: int64(event.AccountID), Valid: true}
+	}
+	var fromStatus, ip sql.NullString
+	if event.FromStatus != "" {
+		fromStatus = sql.NullString{String: event.FromStatus, Valid: true}
+	}
+	if event.IP != "" {
+		ip = sql.NullString{String: event.IP, Valid: true}
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO character_auction_events (auction_id, account_id, event, from_status, to_status, amount, ip, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.AuctionID, accountID, event.Event, fromStatus, event.ToStatus, event.Amount, ip, time.Now().Unix(),
+	)
+	return err
+}
+
+// GetAuctionsHandler lists running auctions ending soonest first, or finished ones with ?status=finished
+func GetAuctionsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page := 1
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	limit := DefaultBazaarLimit
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && l <= MaxBazaarLimit {
+		limit = l
+	}
+	offset := (page - 1) * limit
+
+	where := "ca.status = 'active'"
+	order := "ca.ends_at ASC"
+	if query.Get("status") == "finished" {
+		where = "ca.status IN ('sold', 'expired') AND " + visiblePlayerCondition
+		order = "ca.ends_at DESC"
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var total int
+	if err := database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM character_auctions ca INNER JOIN players p ON p.id = ca.player_id WHERE "+where,
+	).Scan(&total); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auctions")
+		return
+	}
+
+	rows, err := database.DB.QueryContext(ctx,
+		"SELECT "+auctionColumns+`
+		 FROM character_auctions ca
+		 INNER JOIN players p ON p.id = ca.player_id
+		 WHERE `+where+`
+		 ORDER BY `+order+`
+		 LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auctions")
+		return
+	}
+	defer rows.Close()
+
+	auctions := make([]Auction, 0, limit)
+	for rows.Next() {
+		auction, err := scanAuction(rows, 0)
+		if err != nil {
+			continue
+		}
+		auctions = append(auctions, auction)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing auctions")
+		return
+	}
+
+	var response AuctionsResponse
+	response.Auctions = auctions
+	response.Pagination.Page = page
+	response.Pagination.Limit = limit
+	response.Pagination.Total = total
+	response.Pagination.TotalPages = int(math.Ceil(float64(total) / float64(limit)))
+
+	utils.WriteSuccess(w, http.StatusOK, "Auctions retrieved successfully", response)
+}
+
+// GetAuctionHandler returns an auction with the same character profile as the character page and its bids.
+// Characters of running auctions are shown even if their owner hid them; once the auction is over,
+// a hidden character comes back as null.
+func GetAuctionHandler(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || auctionID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid auction ID")
+		return
+	}
+
+	accountID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	auction, err := scanAuction(database.DB.QueryRowContext(ctx,
+		"SELECT "+auctionColumns+`
+		 FROM character_auctions ca
+		 INNER JOIN players p ON p.id = ca.player_id
+		 WHERE ca.id = ? AND ca.status != ?`,
+		auctionID, AuctionStatusCancelled,
+	), accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Auction not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auction")
+		return
+	}
+
+	characterCondition := "p.id = ?"
+	if auction.Status != AuctionStatusActive {
+		characterCondition += " AND " + visiblePlayerCondition
+	}
+
+	character, err := loadCharacterDetails(ctx, characterCondition, auction.playerID)
+	if err != nil && err != sql.ErrNoRows {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auction")
+		return
+	}
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT amount, created_at FROM character_auction_events
+		 WHERE auction_id = ? AND event = ?
+		 ORDER BY id DESC`,
+		auctionID, AuctionEventBid,
+	)
+	bids := make([]AuctionBid, 0)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var bid AuctionBid
+			if err := rows.Scan(&bid.Amount, &bid.Time); err == nil {
+				bids = append(bids, bid)
+			}
+		}
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Auction retrieved successfully", AuctionDetailsResponse{
+		Auction:   auction,
+		Character: character,
+		Bids:      bids,
+	})
+}
+
+// GetMyAuctionsHandler lists the auctions of the logged in account and the running auctions it leads
+func GetMyAuctionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	rows, err := database.DB.QueryContext(ctx,
+		"SELECT "+auctionColumns+`
+		 FROM character_auctions ca
+		 INNER JOIN players p ON p.id = ca.player_id
+		 WHERE ca.seller_account_id = ? OR (ca.highest_bidder_account_id = ? AND ca.status = 'active')
+		 ORDER BY ca.created_at DESC
+		 LIMIT 50`,
+		userID, userID,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auctions")
+		return
+	}
+	defer rows.Close()
+
+	auctions := make([]Auction, 0)
+	for rows.Next() {
+		auction, err := scanAuction(rows, userID)
+		if err != nil {
+			continue
+		}
+		auctions = append(auctions, auction)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing auctions")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Auctions retrieved successfully", map[string]interface{}{
+		"auctions":       auctions,
+		"maxAuctionDays": GetBazaarMaxAuctionDays(),
+	})
+}
+
+// CreateAuctionHandler puts an offline character of the logged in account up for auction.
+// The character is left out of the game client's character list until the auction ends.
+func CreateAuctionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CreateAuctionRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.MinimumBid < 1 || req.MinimumBid > math.MaxInt32 {
+		utils.WriteError(w, http.StatusBadRequest, "Minimum bid must be at least 1 coin")
+		return
+	}
+
+	maxDays := GetBazaarMaxAuctionDays()
+	if req.DurationDays < 1 || req.DurationDays > maxDays {
+		utils.WriteError(w, http.StatusBadRequest, "Auction duration must be between 1 and "+strconv.Itoa(maxDays)+" days")
+		return
+	}
+
+	if req.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	if len(req.Password) > utils.MaxPasswordLength {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, req.Character)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if msg := characterServiceUnavailable(char); msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Guild ownership follows the character, so selling a leader would hand the guild to the buyer
+	if char.guildOwner {
+		utils.WriteError(w, http.StatusBadRequest, "Guild leaders can't be auctioned. Pass the leadership or disband the guild first.")
+		return
+	}
+
+	var storedPassword string
+	if err := database.DB.QueryRowContext(ctx, "SELECT password FROM accounts WHERE id = ?", userID).Scan(&storedPassword); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
+		return
+	}
+
+	now := time.Now()
+	endsAt := now.AddDate(0, 0, req.DurationDays).Unix()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+	defer tx.Rollback()
+
+	// The checks above ran outside the transaction: lock the character so a concurrent request waits here,
+	// then look for an active auction again with a locking read that sees the other request's commit
+	var playerID int
+	if err := tx.QueryRowContext(ctx,
+		"SELECT id FROM players WHERE id = ? AND account_id = ? AND deletion = 0 FOR UPDATE",
+		char.id, userID,
+	).Scan(&playerID); err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+
+	var activeAuctions int
+	if err := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM character_auctions WHERE player_id = ? AND status = ? FOR UPDATE",
+		char.id, AuctionStatusActive,
+	).Scan(&activeAuctions); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+	if activeAuctions > 0 {
+		utils.WriteError(w, http.StatusBadRequest, "The character is on auction")
+		return
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO character_auctions (player_id, seller_account_id, minimum_bid, status, created_at, ends_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		char.id, userID, req.MinimumBid, AuctionStatusActive, now.Unix(), endsAt,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+
+	auctionID, err := result.LastInsertId()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+
+	if err := RecordAuctionEvent(ctx, tx, AuctionEvent{
+		AuctionID: int(auctionID),
+		AccountID: userID,
+		Event:     AuctionEventCreated,
+		ToStatus:  AuctionStatusActive,
+		Amount:    req.MinimumBid,
+		IP:        utils.GetClientIP(r),
+	}); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating auction")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, "Character put up for auction", map[string]interface{}{
+		"id":     auctionID,
+		"endsAt": endsAt,
+	})
+}
+
+// BidAuctionHandler places a bid. The coins are held from the bidder's account right away and
+// handed back when someone bids higher, so a finished auction can always be paid.
+func BidAuctionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	auctionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || auctionID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid auction ID")
+		return
+	}
+
+	var req BidAuctionRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Amount < 1 || req.Amount > math.MaxInt32 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid bid amount")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+	defer tx.Rollback()
+
+	var sellerID, minimumBid, currentBid, bidderID int
+	var status string
+	var endsAt int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT seller_account_id, minimum_bid, current_bid, COALESCE(highest_bidder_account_id, 0), status, ends_at
+		 FROM character_auctions WHERE id = ? FOR UPDATE`,
+		auctionID,
+	).Scan(&sellerID, &minimumBid, &currentBid, &bidderID, &status, &endsAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Auction not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+
+	if status != AuctionStatusActive || endsAt <= time.Now().Unix() {
+		utils.WriteError(w, http.StatusBadRequest, "This auction has ended")
+		return
+	}
+
+	if sellerID == userID {
+		utils.WriteError(w, http.StatusBadRequest, "You can't bid on your own auction")
+		return
+	}
+
+	if req.Amount < minimumBid {
+		utils.WriteError(w, http.StatusBadRequest, "The bid must be at least "+strconv.Itoa(minimumBid)+" coins")
+		return
+	}
+
+	if req.Amount <= currentBid {
+		utils.WriteError(w, http.StatusBadRequest, "The bid must be higher than "+strconv.Itoa(currentBid)+" coins")
+		return
+	}
+
+	ip := utils.GetClientIP(r)
+
+	// The previous highest bid is returned first, which also covers raising the own bid
+	if bidderID > 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET coins = coins + ? WHERE id = ?", currentBid, bidderID); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+			return
+		}
+		if err := RecordAuctionEvent(ctx, tx, AuctionEvent{
+			AuctionID:  auctionID,
+			AccountID:  bidderID,
+			Event:      AuctionEventRefund,
+			FromStatus: AuctionStatusActive,
+			ToStatus:   AuctionStatusActive,
+			Amount:     currentBid,
+			IP:         ip,
+		}); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+			return
+		}
+	}
+
+	if err := chargeCoins(ctx, tx, userID, req.Amount); err != nil {
+		if errors.Is(err, errNotEnoughCoins) {
+			utils.WriteError(w, http.StatusBadRequest, "You don't have enough coins for this bid")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE character_auctions SET current_bid = ?, highest_bidder_account_id = ?, bid_count = bid_count + 1 WHERE id = ?",
+		req.Amount, userID, auctionID,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+
+	if err := RecordAuctionEvent(ctx, tx, AuctionEvent{
+		AuctionID:  auctionID,
+		AccountID:  userID,
+		Event:      AuctionEventBid,
+		FromStatus: AuctionStatusActive,
+		ToStatus:   AuctionStatusActive,
+		Amount:     req.Amount,
+		IP:         ip,
+	}); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error placing bid")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Your bid of "+strconv.Itoa(req.Amount)+" coins was placed", nil)
+}
+
+// CancelAuctionHandler withdraws an auction of the logged in account that has no bids yet
+func CancelAuctionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	auctionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || auctionID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid auction ID")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling auction")
+		return
+	}
+	defer tx.Rollback()
+
+	var status string
+	var bidCount int
+	err = tx.QueryRowContext(ctx,
+		"SELECT status, bid_count FROM character_auctions WHERE id = ? AND seller_account_id = ? FOR UPDATE",
+		auctionID, userID,
+	).Scan(&status, &bidCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Auction not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling auction")
+		return
+	}
+
+	if status != AuctionStatusActive {
+		utils.WriteError(w, http.StatusBadRequest, "This auction has ended")
+		return
+	}
+
+	if bidCount > 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Auctions with bids can't be canceled")
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE character_auctions SET status = ?, settled_at = ? WHERE id = ?",
+		AuctionStatusCancelled, time.Now().Unix(), auctionID,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling auction")
+		return
+	}
+
+	if err := RecordAuctionEvent(ctx, tx, AuctionEvent{
+		AuctionID:  auctionID,
+		AccountID:  userID,
+		Event:      AuctionEventCancelled,
+		FromStatus: AuctionStatusActive,
+		ToStatus:   AuctionStatusCancelled,
+		IP:         utils.GetClientIP(r),
+	}); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling auction")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling auction")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Auction canceled successfully", nil)
+}
+
+// GetAuctionEventsHandler returns the full audit trail of an auction, including accounts and IPs
+func GetAuctionEventsHandler(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || auctionID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid auction ID")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT id, auction_id, COALESCE(account_id, 0), event, COALESCE(from_status, ''), to_status, amount, COALESCE(ip, ''), created_at
+		 FROM character_auction_events
+		 WHERE auction_id = ?
+		 ORDER BY id`,
+		auctionID,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching auction events")
+		return
+	}
+	defer rows.Close()
+
+	events := make([]AuctionEvent, 0)
+	for rows.Next() {
+		var e AuctionEvent
+		if err := rows.Scan(&e.ID, &e.AuctionID, &e.AccountID, &e.Event, &e.FromStatus, &e.ToStatus, &e.Amount, &e.IP, &e.CreatedAt); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing auction events")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Auction events retrieved successfully", map[string]interface{}{
+		"events": events,
+	})
+}
@@ -0,0 +1,505 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	// MaxBulkEditRows caps how many rows a single bulk edit may change
+	MaxBulkEditRows   = 1000
+	bulkPreviewLimit  = 50
+	maxBulkConditions = 10
+	maxBulkInValues   = 500
+	// maxBulkBigInt bounds 64-bit fields to the integers JSON numbers carry exactly, which also keeps
+	// "add" far away from overflowing BIGINT before its result is clamped
+	maxBulkBigInt = 1 << 53
+)
+
+type bulkFieldType int
+
+const (
+	bulkInt bulkFieldType = iota
+	bulkString
+)
+
+// bulkField is a column exposed to the bulk edit API. Only Editable fields can be changed; all can be filtered on.
+type bulkField struct {
+	Type     bulkFieldType
+	Editable bool
+	Min      int64
+	Max      int64
+}
+
+// bulkEditSchema whitelists the columns of a table that the bulk edit API may touch
+type bulkEditSchema struct {
+	Target        string
+	Table         string
+	LabelColumn   string
+	BaseCondition string
+	Fields        map[string]bulkField
+}
+
+var accountBulkSchema = bulkEditSchema{
+	Target:      "accounts",
+	Table:       "accounts",
+	LabelColumn: "email",
+	Fields: map[string]bulkField{
+		"id":                 {Type: bulkInt},
+		"email":              {Type: bulkString},
+		"creation":           {Type: bulkInt},
+		"premdays":           {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"coins":              {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"coins_transferable": {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+	},
+}
+
+var playerBulkSchema = bulkEditSchema{
+	Target:        "players",
+	Table:         "players",
+	LabelColumn:   "name",
+	BaseCondition: "deletion = 0",
+	Fields: map[string]bulkField{
+		"id":              {Type: bulkInt},
+		"name":            {Type: bulkString},
+		"account_id":      {Type: bulkInt},
+		"vocation":        {Type: bulkInt, Editable: true, Min: 0, Max: 255},
+		"level":           {Type: bulkInt, Editable: true, Min: 1, Max: math.MaxInt32},
+		"experience":      {Type: bulkInt, Editable: true, Min: 0, Max: maxBulkBigInt},
+		"maglevel":        {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"health":          {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"healthmax":       {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"mana":            {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"manamax":         {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"soul":            {Type: bulkInt, Editable: true, Min: 0, Max: 255},
+		"cap":             {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"town_id":         {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"group_id":        {Type: bulkInt, Editable: true, Min: 1, Max: math.MaxInt32},
+		"balance":         {Type: bulkInt, Editable: true, Min: 0, Max: maxBulkBigInt},
+		"skill_fist":      {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_club":      {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_sword":     {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_axe":       {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_dist":      {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_shielding": {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+		"skill_fishing":   {Type: bulkInt, Editable: true, Min: 0, Max: math.MaxInt32},
+	},
+}
+
+// BulkAssignment changes one field: op "set" writes value, op "add" adds value (may be negative) to numeric fields
+type BulkAssignment struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+// BulkCondition filters the rows to change. Ops: eq, ne, lt, lte, gt, gte, in (value is an array) and like (strings).
+type BulkCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+type BulkEditRequest struct {
+	Set    []BulkAssignment `json:"set"`
+	Where  []BulkCondition  `json:"where"`
+	DryRun bool             `json:"dryRun"`
+}
+
+// BulkEditPreviewRow shows how one matched row would change
+type BulkEditPreviewRow struct {
+	ID     int64                  `json:"id"`
+	Label  string                 `json:"label"`
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+}
+
+type BulkEditResponse struct {
+	DryRun       bool                 `json:"dryRun"`
+	Matched      int                  `json:"matched"`
+	RowsAffected int64                `json:"rowsAffected"`
+	Preview      []BulkEditPreviewRow `json:"preview,omitempty"`
+}
+
+var bulkConditionOperators = map[string]string{
+	"eq":   "=",
+	"ne":   "<>",
+	"lt":   "<",
+	"lte":  "<=",
+	"gt":   ">",
+	"gte":  ">=",
+	"in":   "IN",
+	"like": "LIKE",
+}
+
+// compiledBulkEdit holds the parameterized SQL fragments built from a request
+type compiledBulkEdit struct {
+	setColumns     []string
+	setExpressions []string
+	setArgs        []interface{}
+	where          string
+	whereArgs      []interface{}
+}
+
+// setClause joins the assignments for an UPDATE
+func (c *compiledBulkEdit) setClause() string {
+	assignments := make([]string, len(c.setColumns))
+	for i, column := range c.setColumns {
+		assignments[i] = column + " = " + c.setExpressions[i]
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// BulkEditAccountsHandler applies a structured edit to the accounts matching the conditions
+func BulkEditAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	handleBulkEdit(w, r, accountBulkSchema)
+}
+
+// BulkEditPlayersHandler applies a structured edit to the players matching the conditions
+func BulkEditPlayersHandler(w http.ResponseWriter, r *http.Request) {
+	handleBulkEdit(w, r, playerBulkSchema)
+}
+
+func handleBulkEdit(w http.ResponseWriter, r *http.Request, schema bulkEditSchema) {
+	var req BulkEditRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	compiled, err := compileBulkEdit(schema, req)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	if req.DryRun {
+		response, err := previewBulkEdit(ctx, schema, compiled)
+		if err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error previewing bulk edit")
+			return
+		}
+		utils.WriteSuccess(w, http.StatusOK, "Bulk edit preview generated", response)
+		return
+	}
+
+	changes, rowsAffected, err := applyBulkEdit(ctx, schema, compiled)
+	if err != nil {
+		var tooMany errBulkTooManyRows
+		if errors.As(err, &tooMany) {
+			utils.WriteError(w, http.StatusBadRequest, tooMany.Error())
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error applying bulk edit")
+		return
+	}
+
+	// One entry per row keeps the old values of relative edits, so each change can be looked up and undone
+	for _, change := range changes {
+		recordAdminAction(r, AuditEntry{
+			Action:     schema.Target + ".bulk_edit",
+			TargetType: strings.TrimSuffix(schema.Target, "s"),
+			TargetID:   strconv.FormatInt(change.ID, 10),
+			Before:     change.Before,
+			After:      change.After,
+		})
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Bulk edit applied successfully", BulkEditResponse{
+		Matched:      len(changes),
+		RowsAffected: rowsAffected,
+	})
+}
+
+type errBulkTooManyRows struct{ matched int }
+
+func (e errBulkTooManyRows) Error() string {
+	return fmt.Sprintf("The conditions match %d rows, more than the limit of %d. Narrow them down and try again.", e.matched, MaxBulkEditRows)
+}
+
+// compileBulkEdit validates a request against the schema and builds parameterized SQL fragments.
+// Column names only ever come from the schema, values are always bound as arguments.
+func compileBulkEdit(schema bulkEditSchema, req BulkEditRequest) (*compiledBulkEdit, error) {
+	if len(req.Set) == 0 {
+		return nil, errors.New("At least one field to change is required")
+	}
+	if len(req.Where) == 0 {
+		return nil, errors.New("At least one condition is required")
+	}
+	if len(req.Where) > maxBulkConditions {
+		return nil, fmt.Errorf("At most %d conditions are allowed", maxBulkConditions)
+	}
+
+	compiled := &compiledBulkEdit{}
+
+	seen := map[string]bool{}
+	for _, assignment := range req.Set {
+		field, ok := schema.Fields[assignment.Field]
+		if !ok || !field.Editable {
+			return nil, fmt.Errorf("Field %q cannot be edited", utils.SanitizeString(assignment.Field, 64))
+		}
+		if seen[assignment.Field] {
+			return nil, fmt.Errorf("Field %q is set more than once", assignment.Field)
+		}
+		seen[assignment.Field] = true
+
+		switch assignment.Op {
+		case "set", "":
+			value, err := bulkValue(assignment.Field, field, assignment.Value, true)
+			if err != nil {
+				return nil, err
+			}
+			compiled.setColumns = append(compiled.setColumns, assignment.Field)
+			compiled.setExpressions = append(compiled.setExpressions, "?")
+			compiled.setArgs = append(compiled.setArgs, value)
+		case "add":
+			if field.Type != bulkInt {
+				return nil, fmt.Errorf("Field %q is not numeric", assignment.Field)
+			}
+			delta, err := bulkInteger(assignment.Field, assignment.Value)
+			if err != nil {
+				return nil, err
+			}
+			// A larger step can't change the clamped result, but could overflow BIGINT before the clamp
+			if span := field.Max - field.Min; delta > span || delta < -span {
+				return nil, fmt.Errorf("Value to add to %q must be between %d and %d", assignment.Field, -span, span)
+			}
+			// Keep the result inside the field's range instead of failing or wrapping around.
+			// The cast lets negative steps go below zero on unsigned columns before GREATEST clamps them.
+			compiled.setColumns = append(compiled.setColumns, assignment.Field)
+			compiled.setExpressions = append(compiled.setExpressions, "LEAST(GREATEST(CAST("+assignment.Field+" AS SIGNED) + ?, ?), ?)")
+			compiled.setArgs = append(compiled.setArgs, delta, field.Min, field.Max)
+		default:
+			return nil, fmt.Errorf("Unknown operation %q", utils.SanitizeString(assignment.Op, 16))
+		}
+	}
+
+	conditions := []string{}
+	if schema.BaseCondition != "" {
+		conditions = append(conditions, schema.BaseCondition)
+	}
+	for _, condition := range req.Where {
+		field, ok := schema.Fields[condition.Field]
+		if !ok {
+			return nil, fmt.Errorf("Cannot filter on field %q", utils.SanitizeString(condition.Field, 64))
+		}
+		operator, ok := bulkConditionOperators[condition.Op]
+		if !ok {
+			return nil, fmt.Errorf("Unknown condition operator %q", utils.SanitizeString(condition.Op, 16))
+		}
+
+		switch condition.Op {
+		case "in":
+			values, ok := condition.Value.([]interface{})
+			if !ok || len(values) == 0 || len(values) > maxBulkInValues {
+				return nil, fmt.Errorf("Condition on %q needs a list of 1 to %d values", condition.Field, maxBulkInValues)
+			}
+			placeholders := make([]string, len(values))
+			for i, raw := range values {
+				value, err := bulkValue(condition.Field, field, raw, false)
+				if err != nil {
+					return nil, err
+				}
+				placeholders[i] = "?"
+				compiled.whereArgs = append(compiled.whereArgs, value)
+			}
+			conditions = append(conditions, condition.Field+" IN ("+strings.Join(placeholders, ", ")+")")
+		case "like":
+			if field.Type != bulkString {
+				return nil, fmt.Errorf("Condition like only works on text fields")
+			}
+			value, err := bulkValue(condition.Field, field, condition.Value, false)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition.Field+" LIKE ?")
+			compiled.whereArgs = append(compiled.whereArgs, value)
+		default:
+			value, err := bulkValue(condition.Field, field, condition.Value, false)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition.Field+" "+operator+" ?")
+			compiled.whereArgs = append(compiled.whereArgs, value)
+		}
+	}
+	compiled.where = strings.Join(conditions, " AND ")
+
+	return compiled, nil
+}
+
+// bulkValue checks that a JSON value matches the field type, and its range when it is written
+func bulkValue(name string, field bulkField, raw interface{}, checkRange bool) (interface{}, error) {
+	switch field.Type {
+	case bulkInt:
+		value, err := bulkInteger(name, raw)
+		if err != nil {
+			return nil, err
+		}
+		if checkRange && (value < field.Min || value > field.Max) {
+			return nil, fmt.Errorf("Value for %q must be between %d and %d", name, field.Min, field.Max)
+		}
+		return value, nil
+	case bulkString:
+		value, ok := raw.(string)
+		if !ok || len(value) > 255 {
+			return nil, fmt.Errorf("Value for %q must be text of at most 255 characters", name)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("Unsupported field %q", name)
+}
+
+func bulkInteger(name string, raw interface{}) (int64, error) {
+	number, ok := raw.(float64)
+	if !ok || number != math.Trunc(number) || math.Abs(number) > 1<<53 {
+		return 0, fmt.Errorf("Value for %q must be a whole number", name)
+	}
+	return int64(number), nil
+}
+
+// previewBulkEdit counts the matching rows and shows the first ones with their values before and after
+func previewBulkEdit(ctx context.Context, schema bulkEditSchema, compiled *compiledBulkEdit) (*BulkEditResponse, error) {
+	response := &BulkEditResponse{DryRun: true, Preview: []BulkEditPreviewRow{}}
+
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM "+schema.Table+" WHERE "+compiled.where,
+		compiled.whereArgs...,
+	).Scan(&response.Matched)
+	if err != nil {
+		return nil, err
+	}
+
+	// The after values are computed by MySQL with the same expressions the update uses
+	rows, err := database.DB.QueryContext(ctx,
+		compiled.changesQuery(schema)+" ORDER BY id LIMIT ?",
+		append(compiled.changesArgs(), bulkPreviewLimit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	response.Preview, err = scanBulkEditRows(rows, compiled.setColumns)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// changesQuery selects the matching rows with the current and resulting values of the set columns
+func (c *compiledBulkEdit) changesQuery(schema bulkEditSchema) string {
+	return "SELECT id, " + schema.LabelColumn + ", " + strings.Join(c.setColumns, ", ") + ", " + strings.Join(c.setExpressions, ", ") +
+		" FROM " + schema.Table + " WHERE " + c.where
+}
+
+// changesArgs binds the set expressions and then the conditions of changesQuery
+func (c *compiledBulkEdit) changesArgs() []interface{} {
+	return append(append([]interface{}{}, c.setArgs...), c.whereArgs...)
+}
+
+// scanBulkEditRows reads the rows of changesQuery
+func scanBulkEditRows(rows *sql.Rows, columns []string) ([]BulkEditPreviewRow, error) {
+	result := []BulkEditPreviewRow{}
+	for rows.Next() {
+		var row BulkEditPreviewRow
+		values := make([]sql.NullString, len(columns)*2)
+		dest := []interface{}{&row.ID, &row.Label}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row.Before = make(map[string]interface{}, len(columns))
+		row.After = make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row.Before[column] = values[i].String
+			row.After[column] = values[len(columns)+i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// applyBulkEdit locks the matching rows and updates them in one transaction.
+// Returns every changed row with the values of the set columns before and after the update.
+func applyBulkEdit(ctx context.Context, schema bulkEditSchema, compiled *compiledBulkEdit) ([]BulkEditPreviewRow, int64, error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	// The rows are locked, so the values read here are exactly the ones the update starts from
+	rows, err := tx.QueryContext(ctx,
+		compiled.changesQuery(schema)+" ORDER BY id LIMIT ? FOR UPDATE",
+		append(compiled.changesArgs(), MaxBulkEditRows+1)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	changes, err := scanBulkEditRows(rows, compiled.setColumns)
+	rows.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(changes) > MaxBulkEditRows {
+		var matched int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+schema.Table+" WHERE "+compiled.where, compiled.whereArgs...).Scan(&matched); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, errBulkTooManyRows{matched: matched}
+	}
+	if len(changes) == 0 {
+		return changes, 0, nil
+	}
+
+	placeholders := make([]string, len(changes))
+	args := append([]interface{}{}, compiled.setArgs...)
+	for i, change := range changes {
+		placeholders[i] = "?"
+		args = append(args, change.ID)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE "+schema.Table+" SET "+compiled.setClause()+" WHERE id IN ("+strings.Join(placeholders, ", ")+")",
+		args...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return changes, rowsAffected, nil
+}
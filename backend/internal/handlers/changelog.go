@@ -231,6 +231,13 @@ func CreateChangelogHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "changelog.create",
+		TargetType: "changelog",
+		TargetID:   strconv.FormatInt(changelogID, 10),
+		After:      map[string]interface{}{"version": req.Version, "title": req.Title, "description": req.Description, "type": req.Type},
+	})
+
 	utils.WriteSuccess(w, http.StatusCreated, "Changelog created successfully", map[string]interface{}{
 		"id": int(changelogID),
 	})
@@ -249,6 +256,8 @@ func DeleteChangelogHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
 
+	before := loadAuditSnapshot(ctx, "changelogs", changelogID, []string{"version", "title", "type"})
+
 	deleteQuery := "DELETE FROM changelogs WHERE id = ?"
 	result, err := database.DB.ExecContext(ctx, deleteQuery, changelogID)
 	if err != nil {
@@ -270,6 +279,13 @@ func DeleteChangelogHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "changelog.delete",
+		TargetType: "changelog",
+		TargetID:   changelogIDStr,
+		Before:     before,
+	})
+
 	utils.WriteSuccess(w, http.StatusOK, "Changelog deleted successfully", nil)
 }
 
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -61,12 +63,78 @@ type CharacterDetails struct {
 	Experience           int64           `json:"experience"`
 	ExperienceToNextLevel int64          `json:"experienceToNextLevel"`
 	Equipment            []EquipmentItem `json:"equipment"`
+	EquipmentSlots       EquipmentSlots  `json:"equipmentSlots"`
+	FormerNames          []string        `json:"formerNames"`
+	Comment              string          `json:"comment,omitempty"`
+	RealName             string          `json:"realName,omitempty"`
+	Location             string          `json:"location,omitempty"`
+	IsMainCharacter      bool            `json:"isMainCharacter"`
+	Achievements         *CharacterAchievements `json:"achievements,omitempty"`
+	Bestiary             *CharacterBestiary     `json:"bestiary,omitempty"`
+	Charms               *CharacterCharms       `json:"charms,omitempty"`
 }
 
 type EquipmentItem struct {
-	Slot   int `json:"slot"`
-	ItemID int `json:"itemId"`
-	Count  int `json:"count"`
+	Slot     int    `json:"slot"`
+	SlotName string `json:"slotName"`
+	ItemID   int    `json:"itemId"`
+	ClientID int    `json:"clientId"`
+	Name     string `json:"name,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// EquipmentSlots is the equipment by slot; empty slots are null
+type EquipmentSlots struct {
+	Head      *EquipmentItem `json:"head"`
+	Necklace  *EquipmentItem `json:"necklace"`
+	Backpack  *EquipmentItem `json:"backpack"`
+	Armor     *EquipmentItem `json:"armor"`
+	RightHand *EquipmentItem `json:"rightHand"`
+	LeftHand  *EquipmentItem `json:"leftHand"`
+	Legs      *EquipmentItem `json:"legs"`
+	Feet      *EquipmentItem `json:"feet"`
+	Ring      *EquipmentItem `json:"ring"`
+	Ammo      *EquipmentItem `json:"ammo"`
+}
+
+// equipmentSlotNames maps the player_items pid of the equipment slots to their names
+var equipmentSlotNames = map[int]string{
+	1:  "head",
+	2:  "necklace",
+	3:  "backpack",
+	4:  "armor",
+	5:  "rightHand",
+	6:  "leftHand",
+	7:  "legs",
+	8:  "feet",
+	9:  "ring",
+	10: "ammo",
+}
+
+// set puts an item in its named slot
+func (s *EquipmentSlots) set(item *EquipmentItem) {
+	switch item.Slot {
+	case 1:
+		s.Head = item
+	case 2:
+		s.Necklace = item
+	case 3:
+		s.Backpack = item
+	case 4:
+		s.Armor = item
+	case 5:
+		s.RightHand = item
+	case 6:
+		s.LeftHand = item
+	case 7:
+		s.Legs = item
+	case 8:
+		s.Feet = item
+	case 9:
+		s.Ring = item
+	case 10:
+		s.Ammo = item
+	}
 }
 
 type Death struct {
@@ -94,6 +162,9 @@ type Character struct {
 	LookLegs  int    `json:"lookLegs"`
 	LookFeet   int    `json:"lookFeet"`
 	LookAddons int    `json:"lookAddons"`
+	Hidden     bool   `json:"hidden"`
+	// DeletionScheduledAt is set while the character is waiting to be deleted
+	DeletionScheduledAt *int64 `json:"deletionScheduledAt,omitempty"`
 }
 
 func CreateCharacterHandler(w http.ResponseWriter, r *http.Request) {
@@ -116,19 +187,8 @@ func CreateCharacterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	req.Name = utils.SanitizeString(req.Name, 255)
-	if req.Name == "" {
-		utils.WriteError(w, http.StatusBadRequest, "Character name is required")
-		return
-	}
-
-	if len(req.Name) < 3 || len(req.Name) > 20 {
-		utils.WriteError(w, http.StatusBadRequest, "Character name must be between 3 and 20 characters")
-		return
-	}
-
-	nameRegex := utils.GetNameRegex()
-	if !nameRegex.MatchString(req.Name) {
-		utils.WriteError(w, http.StatusBadRequest, "Character name must contain only letters and spaces")
+	if msg := validateCharacterName(req.Name); msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, msg)
 		return
 	}
 
@@ -154,8 +214,23 @@ func CreateCharacterHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
 
+	var accountStatus string
+	err := database.DB.QueryRowContext(ctx, "SELECT COALESCE(status, 'active') FROM accounts WHERE id = ?", userID).Scan(&accountStatus)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error checking account")
+		return
+	}
+
+	if isAccountUnverified(accountStatus) {
+		utils.WriteError(w, http.StatusForbidden, "Verify your email address before creating characters")
+		return
+	}
+
 	var exists bool
-	err := database.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM players WHERE name = ?)", req.Name).Scan(&exists)
+	err = database.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM players WHERE name = ?)", req.Name).Scan(&exists)
 	if err != nil {
 		if utils.HandleDBError(w, err) {
 			return
@@ -255,6 +330,23 @@ func CreateCharacterHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// validateCharacterName checks a sanitized name against the naming rules and returns the reason it was rejected
+func validateCharacterName(name string) string {
+	if name == "" {
+		return "Character name is required"
+	}
+
+	if len(name) < 3 || len(name) > 20 {
+		return "Character name must be between 3 and 20 characters"
+	}
+
+	if !utils.GetNameRegex().MatchString(name) {
+		return "Character name must contain only letters and spaces"
+	}
+
+	return ""
+}
+
 func GetCharactersHandler(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
 	if !ok {
@@ -277,10 +369,13 @@ func GetCharactersHandler(w http.ResponseWriter, r *http.Request) {
 			COALESCE(p.lookbody, 0) as lookbody,
 			COALESCE(p.looklegs, 0) as looklegs,
 			COALESCE(p.lookfeet, 0) as lookfeet,
-			COALESCE(p.lookaddons, 0) as lookaddons
+			COALESCE(p.lookaddons, 0) as lookaddons,
+			COALESCE(pss.hidden, 0) as hidden,
+			pss.deletion_scheduled_at
 		FROM players p
 		LEFT JOIN players_online po ON p.id = po.player_id
-		WHERE p.account_id = ?
+		LEFT JOIN player_site_settings pss ON pss.player_id = p.id
+		WHERE p.account_id = ? AND p.deletion = 0
 		ORDER BY p.name
 	`
 
@@ -299,8 +394,9 @@ func GetCharactersHandler(w http.ResponseWriter, r *http.Request) {
 		var char Character
 		var vocationID int
 		var status string
+		var deletionScheduledAt sql.NullInt64
 
-		if err := rows.Scan(&char.ID, &char.Name, &vocationID, &char.Level, &status, &char.LookType, &char.LookHead, &char.LookBody, &char.LookLegs, &char.LookFeet, &char.LookAddons); err != nil {
+		if err := rows.Scan(&char.ID, &char.Name, &vocationID, &char.Level, &status, &char.LookType, &char.LookHead, &char.LookBody, &char.LookLegs, &char.LookFeet, &char.LookAddons, &char.Hidden, &deletionScheduledAt); err != nil {
 			utils.WriteError(w, http.StatusInternalServerError, "Error reading character data")
 			return
 		}
@@ -308,6 +404,9 @@ func GetCharactersHandler(w http.ResponseWriter, r *http.Request) {
 		char.Vocation = config.GetVocationName(vocationID)
 		char.Status = status
 		char.World = config.GetServerName()
+		if deletionScheduledAt.Valid {
+			char.DeletionScheduledAt = &deletionScheduledAt.Int64
+		}
 
 		characters = append(characters, char)
 	}
@@ -320,6 +419,13 @@ func GetCharactersHandler(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusOK, "Characters retrieved successfully", characters)
 }
 
+// characterByNameCondition matches players aliased as p by name, taking the name twice as arguments.
+// A character can also be found by a former name, unless another character carries that name now.
+const characterByNameCondition = `p.id = COALESCE(
+	(SELECT id FROM players WHERE name = ? LIMIT 1),
+	(SELECT pfn.player_id FROM player_former_names pfn WHERE pfn.name = ? ORDER BY pfn.changed_at DESC LIMIT 1)
+)`
+
 func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	characterName := vars["name"]
@@ -334,6 +440,26 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
 
+	response, err := loadCharacterDetails(ctx, characterByNameCondition+" AND "+visiblePlayerCondition, characterName, characterName)
+	if err == sql.ErrNoRows {
+		utils.WriteError(w, http.StatusNotFound, "Character not found")
+		return
+	}
+
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character details")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character details retrieved successfully", response)
+}
+
+// loadCharacterDetails fetches the public profile of the first non-deleted character matching condition,
+// which may use the players table as p. Returns sql.ErrNoRows if there is none.
+func loadCharacterDetails(ctx context.Context, condition string, args ...interface{}) (*CharacterDetailsResponse, error) {
 	var char CharacterDetails
 	var vocationID, sexID int
 	var lastLogin, created int64
@@ -377,7 +503,11 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 			COALESCE(p.skill_fishing, 10) as skill_fishing,
 			COALESCE(p.soul, 0) as soul,
 			COALESCE(p.cap, 0) as cap,
-			COALESCE(p.experience, 0) as experience
+			COALESCE(p.experience, 0) as experience,
+			COALESCE(pp.comment, '') as comment,
+			COALESCE(pp.real_name, '') as real_name,
+			COALESCE(pp.location, '') as location,
+			COALESCE(pp.is_main, 0) as is_main
 		FROM players p
 		LEFT JOIN players_online po ON p.id = po.player_id
 		LEFT JOIN towns t ON p.town_id = t.id
@@ -385,12 +515,13 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN guilds g ON gm.guild_id = g.id
 		LEFT JOIN guild_ranks gr ON gm.rank_id = gr.id
 		LEFT JOIN accounts a ON p.account_id = a.id
-		WHERE p.name = ?
+		LEFT JOIN player_profiles pp ON p.id = pp.player_id
+		WHERE ` + condition + ` AND p.deletion = 0
 		LIMIT 1
 	`
 
 	var playerID int
-	err := database.DB.QueryRowContext(ctx, query, characterName).Scan(
+	err := database.DB.QueryRowContext(ctx, query, args...).Scan(
 		&playerID,
 		&char.Name,
 		&sexID,
@@ -425,19 +556,13 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		&char.Soul,
 		&char.Cap,
 		&experience,
+		&char.Comment,
+		&char.RealName,
+		&char.Location,
+		&char.IsMainCharacter,
 	)
-
-	if err == sql.ErrNoRows {
-		utils.WriteError(w, http.StatusNotFound, "Character not found")
-		return
-	}
-
 	if err != nil {
-		if utils.HandleDBError(w, err) {
-			return
-		}
-		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character details")
-		return
+		return nil, err
 	}
 
 	char.Vocation = config.GetVocationName(vocationID)
@@ -496,6 +621,10 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		for equipmentRows.Next() {
 			var item EquipmentItem
 			if err := equipmentRows.Scan(&item.Slot, &item.ItemID, &item.Count); err == nil {
+				itemType := config.GetItemType(item.ItemID)
+				item.SlotName = equipmentSlotNames[item.Slot]
+				item.ClientID = itemType.ClientID
+				item.Name = itemType.Name
 				equipment = append(equipment, item)
 			}
 		}
@@ -503,6 +632,35 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	char.Equipment = equipment
+	for i := range equipment {
+		char.EquipmentSlots.set(&equipment[i])
+	}
+
+	formerNamesQuery := `
+		SELECT name
+		FROM player_former_names
+		WHERE player_id = ?
+		ORDER BY changed_at DESC
+		LIMIT 10
+	`
+
+	formerNameRows, err := database.DB.QueryContext(ctx, formerNamesQuery, playerID)
+	formerNames := make([]string, 0)
+	if err == nil {
+		defer formerNameRows.Close()
+		for formerNameRows.Next() {
+			var name string
+			if err := formerNameRows.Scan(&name); err == nil {
+				formerNames = append(formerNames, name)
+			}
+		}
+	}
+	char.FormerNames = formerNames
+
+	// Progress comes from the game server's storages; the profile is still shown without it
+	if err := loadCharacterProgress(ctx, playerID, &char); err != nil {
+		log.Printf("Error loading progress of player %d: %v", playerID, err)
+	}
 
 	deathsQuery := `
 		SELECT time, level, killed_by, is_player
@@ -526,12 +684,10 @@ func GetCharacterDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response := CharacterDetailsResponse{
+	return &CharacterDetailsResponse{
 		Character: char,
 		Deaths:    deaths,
-	}
-
-	utils.WriteSuccess(w, http.StatusOK, "Character details retrieved successfully", response)
+	}, nil
 }
 
 type OnlinePlayer struct {
@@ -585,7 +741,7 @@ func GetOnlinePlayersHandler(w http.ResponseWriter, r *http.Request) {
 			COALESCE(p.lookaddons, 0) as lookaddons
 		FROM players_online po
 		INNER JOIN players p ON po.player_id = p.id
-		WHERE p.deletion = 0
+		WHERE p.deletion = 0 AND ` + visiblePlayerCondition + `
 	`
 
 	args := make([]interface{}, 0, 3)
@@ -634,7 +790,7 @@ func GetOnlinePlayersHandler(w http.ResponseWriter, r *http.Request) {
 		SELECT COUNT(*)
 		FROM players_online po
 		INNER JOIN players p ON po.player_id = p.id
-		WHERE p.deletion = 0
+		WHERE p.deletion = 0 AND ` + visiblePlayerCondition + `
 	`
 	countArgs := make([]interface{}, 0, 1)
 	if search != "" {
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const DefaultCharacterDeletionGracePeriodDays = 7
+
+// visiblePlayerCondition leaves out characters their owners chose to hide. Requires players aliased as p.
+const visiblePlayerCondition = "NOT EXISTS (SELECT 1 FROM player_site_settings pss WHERE pss.player_id = p.id AND pss.hidden = 1)"
+
+type DeleteCharacterRequest struct {
+	Password string `json:"password"`
+}
+
+type CharacterVisibilityRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// ownedCharacter is a character of the logged in account that hasn't been deleted yet
+type ownedCharacter struct {
+	id                  int
+	online              bool
+	guildOwner          bool
+	onAuction           bool
+	deletionScheduledAt sql.NullInt64
+}
+
+// GetCharacterDeletionGracePeriodDays returns how long a character deletion can be cancelled (CHARACTER_DELETION_GRACE_PERIOD_DAYS)
+func GetCharacterDeletionGracePeriodDays() int {
+	days, err := strconv.Atoi(os.Getenv("CHARACTER_DELETION_GRACE_PERIOD_DAYS"))
+	if err != nil || days < 1 {
+		return DefaultCharacterDeletionGracePeriodDays
+	}
+	return days
+}
+
+// DeleteCharacterHandler schedules a character for deletion. It can still be played until the
+// grace period ends and the cleanup job deletes it.
+func DeleteCharacterHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req DeleteCharacterRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	if len(req.Password) > utils.MaxPasswordLength {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if char.deletionScheduledAt.Valid {
+		utils.WriteError(w, http.StatusBadRequest, "Character is already scheduled for deletion")
+		return
+	}
+
+	if char.online {
+		utils.WriteError(w, http.StatusBadRequest, "Cannot delete a character while it is online. Please log out first.")
+		return
+	}
+
+	if char.guildOwner {
+		utils.WriteError(w, http.StatusBadRequest, "Guild leaders can't be deleted. Pass the leadership or disband the guild first.")
+		return
+	}
+
+	if char.onAuction {
+		utils.WriteError(w, http.StatusBadRequest, "Characters on auction can't be deleted")
+		return
+	}
+
+	var storedPassword string
+	if err := database.DB.QueryRowContext(ctx, "SELECT password FROM accounts WHERE id = ?", userID).Scan(&storedPassword); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
+		return
+	}
+
+	gracePeriodDays := GetCharacterDeletionGracePeriodDays()
+	deletionTime := time.Now().AddDate(0, 0, gracePeriodDays).Unix()
+
+	if _, err := database.DB.ExecContext(ctx,
+		`INSERT INTO player_site_settings (player_id, deletion_scheduled_at) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE deletion_scheduled_at = VALUES(deletion_scheduled_at)`,
+		char.id, deletionTime,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error scheduling character deletion")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character scheduled for deletion. You have "+strconv.Itoa(gracePeriodDays)+" days to cancel.", map[string]interface{}{
+		"deletionScheduledAt": deletionTime,
+		"gracePeriodDays":     gracePeriodDays,
+	})
+}
+
+// CancelCharacterDeletionHandler cancels a scheduled character deletion
+func CancelCharacterDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if !char.deletionScheduledAt.Valid {
+		utils.WriteError(w, http.StatusBadRequest, "Character is not scheduled for deletion")
+		return
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		"UPDATE player_site_settings SET deletion_scheduled_at = NULL WHERE player_id = ?",
+		char.id,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error canceling character deletion")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character deletion canceled successfully", nil)
+}
+
+// SetCharacterVisibilityHandler hides a character from its public profile, the rankings and the online list.
+// Hidden characters can still log in to the game.
+func SetCharacterVisibilityHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CharacterVisibilityRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		`INSERT INTO player_site_settings (player_id, hidden) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE hidden = VALUES(hidden)`,
+		char.id, req.Hidden,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating character visibility")
+		return
+	}
+
+	message := "Character is now visible"
+	if req.Hidden {
+		message = "Character is now hidden"
+	}
+	utils.WriteSuccess(w, http.StatusOK, message, map[string]bool{"hidden": req.Hidden})
+}
+
+func loadOwnedCharacter(ctx context.Context, accountID int, name string) (*ownedCharacter, error) {
+	name = utils.SanitizeString(name, 255)
+	if name == "" {
+		return nil, sql.ErrNoRows
+	}
+
+	var char ownedCharacter
+	err := database.DB.QueryRowContext(ctx,
+		`SELECT
+			p.id,
+			EXISTS(SELECT 1 FROM players_online po WHERE po.player_id = p.id),
+			EXISTS(SELECT 1 FROM guilds g WHERE g.ownerid = p.id),
+			NOT `+notOnAuctionCondition+`,
+			pss.deletion_scheduled_at
+		 FROM players p
+		 LEFT JOIN player_site_settings pss ON pss.player_id = p.id
+		 WHERE p.name = ? AND p.account_id = ? AND p.deletion = 0`,
+		name, accountID,
+	).Scan(&char.id, &char.online, &char.guildOwner, &char.onAuction, &char.deletionScheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	return &char, nil
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	DefaultExperienceHistoryRetentionDays = 365
+	DefaultExperienceHistoryDays          = 30
+
+	// HistoryDayFormat is the format of player_experience_history.day
+	HistoryDayFormat = "2006-01-02"
+)
+
+type ExperienceHistoryPoint struct {
+	Day        string `json:"day"`
+	Level      int    `json:"level"`
+	Experience int64  `json:"experience"`
+}
+
+type ExperienceHistoryResponse struct {
+	Name    string                   `json:"name"`
+	Days    int                      `json:"days"`
+	History []ExperienceHistoryPoint `json:"history"`
+}
+
+// GetExperienceHistoryRetentionDays returns how long experience snapshots are kept (EXPERIENCE_HISTORY_RETENTION_DAYS)
+func GetExperienceHistoryRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("EXPERIENCE_HISTORY_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		return DefaultExperienceHistoryRetentionDays
+	}
+	return days
+}
+
+// GetCharacterHistoryHandler returns the level and experience of a character over the last days.
+// Snapshots are only stored when the experience changed, so a day without a point keeps the previous value.
+// The last snapshot before the period is included so charts start at the right value.
+func GetCharacterHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	characterName := utils.SanitizeString(mux.Vars(r)["name"], 255)
+	if characterName == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Character name is required")
+		return
+	}
+
+	days := DefaultExperienceHistoryDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 && d <= GetExperienceHistoryRetentionDays() {
+			days = d
+		}
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var playerID int
+	response := ExperienceHistoryResponse{Days: days, History: []ExperienceHistoryPoint{}}
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT p.id, p.name FROM players p WHERE "+characterByNameCondition+" AND p.deletion = 0 AND "+visiblePlayerCondition,
+		characterName, characterName,
+	).Scan(&playerID, &response.Name)
+	if err == sql.ErrNoRows {
+		utils.WriteError(w, http.StatusNotFound, "Character not found")
+		return
+	}
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format(HistoryDayFormat)
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT DATE_FORMAT(day, '%Y-%m-%d'), level, experience
+		 FROM player_experience_history
+		 WHERE player_id = ? AND day >= COALESCE(
+			(SELECT MAX(h.day) FROM player_experience_history h WHERE h.player_id = ? AND h.day < ?), ?
+		 )
+		 ORDER BY day`,
+		playerID, playerID, since, since,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching experience history")
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var point ExperienceHistoryPoint
+		if err := rows.Scan(&point.Day, &point.Level, &point.Experience); err != nil {
+			continue
+		}
+		response.History = append(response.History, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing experience history")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Experience history retrieved successfully", response)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	DefaultOnlinePollIntervalSeconds  = 60
+	DefaultOnlineHistoryRetentionDays = 365
+	DefaultOnlineTimeDays             = 30
+	MaxOnlineSessions                 = 10
+)
+
+type OnlineDay struct {
+	Day     string  `json:"day"`
+	Seconds int64   `json:"seconds"`
+	Hours   float64 `json:"hours"`
+}
+
+// OnlineSession is a stay in the game seen by the online poller. LogoutAt is nil while the character is online.
+type OnlineSession struct {
+	LoginAt  int64  `json:"loginAt"`
+	LogoutAt *int64 `json:"logoutAt"`
+}
+
+type CharacterOnlineTimeResponse struct {
+	Name         string          `json:"name"`
+	Days         int             `json:"days"`
+	TotalSeconds int64           `json:"totalSeconds"`
+	TotalHours   float64         `json:"totalHours"`
+	History      []OnlineDay     `json:"history"`
+	Sessions     []OnlineSession `json:"sessions"`
+}
+
+// OnlineRecord is the highest number of players seen online at once
+type OnlineRecord struct {
+	Players    int   `json:"players"`
+	RecordedAt int64 `json:"recordedAt"`
+}
+
+// GetOnlinePollInterval returns how often the server samples players_online (ONLINE_POLL_INTERVAL_SECONDS).
+// 0 turns the poller off, e.g. on all but one instance.
+func GetOnlinePollInterval() time.Duration {
+	value := os.Getenv("ONLINE_POLL_INTERVAL_SECONDS")
+	if value == "" {
+		return DefaultOnlinePollIntervalSeconds * time.Second
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return DefaultOnlinePollIntervalSeconds * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetOnlineHistoryRetentionDays returns how long online sessions and daily online time are kept (ONLINE_HISTORY_RETENTION_DAYS)
+func GetOnlineHistoryRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("ONLINE_HISTORY_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		return DefaultOnlineHistoryRetentionDays
+	}
+	return days
+}
+
+// GetCharacterOnlineTimeHandler returns the time a character spent online per day and its latest sessions
+func GetCharacterOnlineTimeHandler(w http.ResponseWriter, r *http.Request) {
+	characterName := utils.SanitizeString(mux.Vars(r)["name"], 255)
+	if characterName == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Character name is required")
+		return
+	}
+
+	days := DefaultOnlineTimeDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 && d <= GetOnlineHistoryRetentionDays() {
+			days = d
+		}
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var playerID int
+	response := CharacterOnlineTimeResponse{
+		Days:     days,
+		History:  []OnlineDay{},
+		Sessions: []OnlineSession{},
+	}
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT p.id, p.name FROM players p WHERE "+characterByNameCondition+" AND p.deletion = 0 AND "+visiblePlayerCondition,
+		characterName, characterName,
+	).Scan(&playerID, &response.Name)
+	if err == sql.ErrNoRows {
+		utils.WriteError(w, http.StatusNotFound, "Character not found")
+		return
+	}
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format(HistoryDayFormat)
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT DATE_FORMAT(day, '%Y-%m-%d'), seconds
+		 FROM player_online_daily
+		 WHERE player_id = ? AND day >= ?
+		 ORDER BY day`,
+		playerID, since,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching online time")
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day OnlineDay
+		if err := rows.Scan(&day.Day, &day.Seconds); err != nil {
+			continue
+		}
+		day.Hours = secondsToHours(day.Seconds)
+		response.TotalSeconds += day.Seconds
+		response.History = append(response.History, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing online time")
+		return
+	}
+	response.TotalHours = secondsToHours(response.TotalSeconds)
+
+	sessionRows, err := database.DB.QueryContext(ctx,
+		`SELECT login_at, logout_at
+		 FROM player_online_sessions
+		 WHERE player_id = ?
+		 ORDER BY login_at DESC
+		 LIMIT ?`,
+		playerID, MaxOnlineSessions,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching online sessions")
+		return
+	}
+	defer sessionRows.Close()
+
+	for sessionRows.Next() {
+		var session OnlineSession
+		var logoutAt sql.NullInt64
+		if err := sessionRows.Scan(&session.LoginAt, &logoutAt); err != nil {
+			continue
+		}
+		if logoutAt.Valid {
+			session.LogoutAt = &logoutAt.Int64
+		}
+		response.Sessions = append(response.Sessions, session)
+	}
+
+	if err = sessionRows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing online sessions")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Online time retrieved successfully", response)
+}
+
+// loadOnlineRecord returns the all time player peak, or nil if the poller hasn't recorded one yet
+func loadOnlineRecord(ctx context.Context) (*OnlineRecord, error) {
+	var record OnlineRecord
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT players, recorded_at FROM server_online_peaks ORDER BY players DESC, recorded_at ASC LIMIT 1",
+	).Scan(&record.Players, &record.RecordedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func secondsToHours(seconds int64) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	MaxCharacterCommentLength = 2000
+	MaxProfileFieldLength     = 50
+)
+
+type CharacterProfile struct {
+	Comment         string `json:"comment"`
+	RealName        string `json:"realName"`
+	Location        string `json:"location"`
+	IsMainCharacter bool   `json:"isMainCharacter"`
+}
+
+// GetCharacterProfileHandler returns the editable profile of a character of the logged in account
+func GetCharacterProfileHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	var profile CharacterProfile
+	err = database.DB.QueryRowContext(ctx,
+		"SELECT COALESCE(comment, ''), COALESCE(real_name, ''), COALESCE(location, ''), is_main FROM player_profiles WHERE player_id = ?",
+		char.id,
+	).Scan(&profile.Comment, &profile.RealName, &profile.Location, &profile.IsMainCharacter)
+	if err != nil && err != sql.ErrNoRows {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character profile")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character profile retrieved successfully", profile)
+}
+
+// UpdateCharacterProfileHandler saves the comment and profile fields shown on the character page.
+// Marking a character as main unmarks the other characters of the account.
+func UpdateCharacterProfileHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CharacterProfile
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	req.Comment = utils.SanitizeText(req.Comment, MaxCharacterCommentLength)
+	req.RealName = utils.SanitizeString(req.RealName, MaxProfileFieldLength)
+	req.Location = utils.SanitizeString(req.Location, MaxProfileFieldLength)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating character profile")
+		return
+	}
+	defer tx.Rollback()
+
+	if req.IsMainCharacter {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE player_profiles pp
+			 INNER JOIN players p ON p.id = pp.player_id
+			 SET pp.is_main = 0
+			 WHERE p.account_id = ? AND pp.player_id != ?`,
+			userID, char.id,
+		); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error updating character profile")
+			return
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO player_profiles (player_id, comment, real_name, location, is_main, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE comment = VALUES(comment), real_name = VALUES(real_name),
+		 location = VALUES(location), is_main = VALUES(is_main), updated_at = VALUES(updated_at)`,
+		char.id, req.Comment, req.RealName, req.Location, req.IsMainCharacter, time.Now().Unix(),
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating character profile")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating character profile")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character profile updated successfully", req)
+}
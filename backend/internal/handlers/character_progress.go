@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/config"
+)
+
+type CompletedAchievement struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Points      int    `json:"points"`
+	Secret      bool   `json:"secret"`
+}
+
+type CharacterAchievements struct {
+	Points       int                    `json:"points"`
+	Completed    int                    `json:"completed"`
+	Total        int                    `json:"total"`
+	Achievements []CompletedAchievement `json:"achievements"`
+}
+
+// CharacterBestiary counts the creatures a character has killed at least once (known) and the completed ones
+type CharacterBestiary struct {
+	Known     int `json:"known"`
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	Kills     int `json:"kills"`
+}
+
+type CharacterCharms struct {
+	Unlocked []string `json:"unlocked"`
+	Total    int      `json:"total"`
+}
+
+// kvIntValueSQL decodes a kv_store value the same way as decodeKVInt. Requires kv_store aliased as kv.
+// Canary stores values as a protobuf ValueWrapper: integers are field 2 (0x10 and a varint of up to 5 bytes),
+// booleans field 6 (0x30 and one byte). Values written as plain numbers are read too.
+const kvIntValueSQL = `(CASE
+	WHEN CAST(kv.value AS CHAR CHARACTER SET latin1) REGEXP '^-?[0-9]+$' THEN CAST(CAST(kv.value AS CHAR CHARACTER SET latin1) AS SIGNED)
+	WHEN ORD(SUBSTRING(kv.value, 1, 1)) = 16 AND LENGTH(kv.value) BETWEEN 2 AND 6 THEN
+		(ORD(SUBSTRING(kv.value, 2, 1)) & 127) +
+		((ORD(SUBSTRING(kv.value, 3, 1)) & 127) << 7) +
+		((ORD(SUBSTRING(kv.value, 4, 1)) & 127) << 14) +
+		((ORD(SUBSTRING(kv.value, 5, 1)) & 127) << 21) +
+		((ORD(SUBSTRING(kv.value, 6, 1)) & 127) << 28)
+	WHEN ORD(SUBSTRING(kv.value, 1, 1)) = 48 AND LENGTH(kv.value) = 2 THEN ORD(SUBSTRING(kv.value, 2, 1))
+	ELSE 0
+END)`
+
+// decodeKVInt reads an integer or boolean kv_store value; anything else counts as 0
+func decodeKVInt(value []byte) int {
+	if n, err := strconv.Atoi(string(value)); err == nil && len(value) > 0 && value[0] != '+' {
+		return n
+	}
+
+	switch {
+	case len(value) >= 2 && len(value) <= 6 && value[0] == 0x10:
+		// Negative numbers take 10 bytes and are left out, as in kvIntValueSQL
+		n, size := binary.Uvarint(value[1:])
+		if size == len(value)-1 {
+			return int(n)
+		}
+	case len(value) == 2 && value[0] == 0x30:
+		return int(value[1])
+	}
+	return 0
+}
+
+// playerKVKey returns the kv_store key of a mapping KV key in a player's scope
+func playerKVKey(playerID int, kvKey string) string {
+	return "player." + strconv.Itoa(playerID) + "." + kvKey
+}
+
+// loadCharacterProgress fills the achievements, bestiary and charms of a character from the player storages
+// and KV store keys mapped in the progress mapping file. Sections without mapped entries are left nil.
+func loadCharacterProgress(ctx context.Context, playerID int, char *CharacterDetails) error {
+	progress := config.GetProgressConfig()
+	keys := progress.StorageKeys()
+	kvKeys := progress.KVKeys()
+	if len(keys) == 0 && len(kvKeys) == 0 {
+		return nil
+	}
+
+	storages := make(map[int]int, len(keys))
+	if len(keys) > 0 {
+		args := make([]interface{}, 0, len(keys)+1)
+		args = append(args, playerID)
+		for _, key := range keys {
+			args = append(args, key)
+		}
+
+		rows, err := database.DB.QueryContext(ctx,
+			"SELECT ps.key, ps.value FROM player_storage ps WHERE ps.player_id = ? AND ps.key IN ("+placeholderList(len(keys))+")",
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key, value int
+			if err := rows.Scan(&key, &value); err != nil {
+				continue
+			}
+			storages[key] = value
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	kvValues := make(map[string]int, len(kvKeys))
+	if len(kvKeys) > 0 {
+		args := make([]interface{}, 0, len(kvKeys))
+		for _, kvKey := range kvKeys {
+			args = append(args, playerKVKey(playerID, kvKey))
+		}
+
+		rows, err := database.DB.QueryContext(ctx,
+			"SELECT kv.key_name, kv.value FROM kv_store kv WHERE kv.key_name IN ("+placeholderList(len(kvKeys))+")",
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		prefix := playerKVKey(playerID, "")
+		for rows.Next() {
+			var key string
+			var value []byte
+			if err := rows.Scan(&key, &value); err != nil {
+				continue
+			}
+			kvValues[strings.TrimPrefix(key, prefix)] = decodeKVInt(value)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	valueOf := func(storage int, kvKey string) int {
+		if kvKey != "" {
+			return kvValues[kvKey]
+		}
+		return storages[storage]
+	}
+
+	if len(progress.Achievements) > 0 {
+		achievements := &CharacterAchievements{
+			Total:        len(progress.Achievements),
+			Achievements: []CompletedAchievement{},
+		}
+		for _, achievement := range progress.Achievements {
+			if valueOf(achievement.Storage, achievement.KVKey) < achievement.Value {
+				continue
+			}
+			achievements.Points += achievement.Points
+			achievements.Completed++
+			achievements.Achievements = append(achievements.Achievements, CompletedAchievement{
+				Name:        achievement.Name,
+				Description: achievement.Description,
+				Points:      achievement.Points,
+				Secret:      achievement.Secret,
+			})
+		}
+		char.Achievements = achievements
+	}
+
+	if len(progress.Bestiary) > 0 {
+		bestiary := &CharacterBestiary{Total: len(progress.Bestiary)}
+		for _, entry := range progress.Bestiary {
+			kills := valueOf(entry.Storage, entry.KVKey)
+			if kills <= 0 {
+				continue
+			}
+			bestiary.Known++
+			bestiary.Kills += kills
+			if kills >= entry.Kills {
+				bestiary.Completed++
+			}
+		}
+		char.Bestiary = bestiary
+	}
+
+	if len(progress.Charms) > 0 {
+		charms := &CharacterCharms{Total: len(progress.Charms), Unlocked: []string{}}
+		for _, charm := range progress.Charms {
+			if valueOf(charm.Storage, charm.KVKey) >= 1 {
+				charms.Unlocked = append(charms.Unlocked, charm.Name)
+			}
+		}
+		char.Charms = charms
+	}
+
+	return nil
+}
+
+// achievementPointsJoin returns a join adding the achievement points of each character as ap.points,
+// or "" if no achievements are mapped. Requires players aliased as p.
+func achievementPointsJoin() (string, []interface{}) {
+	achievements := config.GetProgressConfig().Achievements
+	if len(achievements) == 0 {
+		return "", nil
+	}
+
+	// An achievement can share its storage or KV key with others that need a higher value, so every one is its own term
+	var storageTerms, kvTerms []string
+	var storageArgs, kvArgs []interface{}
+	storageKeys := make(map[int]bool)
+	kvKeys := make(map[string]bool)
+	for _, achievement := range achievements {
+		if achievement.KVKey != "" {
+			kvTerms = append(kvTerms, "(pkv.kv_key = ? AND pkv.value >= ?) * ?")
+			kvArgs = append(kvArgs, achievement.KVKey, achievement.Value, achievement.Points)
+			kvKeys[achievement.KVKey] = true
+			continue
+		}
+		storageTerms = append(storageTerms, "(ps.key = ? AND ps.value >= ?) * ?")
+		storageArgs = append(storageArgs, achievement.Storage, achievement.Value, achievement.Points)
+		storageKeys[achievement.Storage] = true
+	}
+
+	var sources []string
+	var args []interface{}
+	if len(storageTerms) > 0 {
+		for key := range storageKeys {
+			storageArgs = append(storageArgs, key)
+		}
+		sources = append(sources, `
+				SELECT ps.player_id, `+strings.Join(storageTerms, " + ")+` as points
+				FROM player_storage ps
+				WHERE ps.key IN (`+placeholderList(len(storageKeys))+`)`)
+		args = append(args, storageArgs...)
+	}
+	if len(kvTerms) > 0 {
+		// Player KV keys are "player.<id>.<key>"; the key starts after the id and its dot
+		conditions := make([]string, 0, len(kvKeys))
+		for key := range kvKeys {
+			conditions = append(conditions, "CONCAT('player.', pk.id, '.', ?)")
+			kvArgs = append(kvArgs, key)
+		}
+		sources = append(sources, `
+				SELECT pkv.player_id, `+strings.Join(kvTerms, " + ")+` as points
+				FROM (
+					SELECT pk.id as player_id, SUBSTRING(kv.key_name, LENGTH(pk.id) + 9) as kv_key, `+kvIntValueSQL+` as value
+					FROM players pk
+					INNER JOIN kv_store kv ON kv.key_name IN (`+strings.Join(conditions, ", ")+`)
+				) pkv`)
+		args = append(args, kvArgs...)
+	}
+
+	return `
+		INNER JOIN (
+			SELECT progress.player_id, SUM(progress.points) as points
+			FROM (` + strings.Join(sources, `
+				UNION ALL`) + `
+			) progress
+			GROUP BY progress.player_id
+		) ap ON ap.player_id = p.id AND ap.points > 0`, args
+}
+
+// placeholderList returns n comma separated query placeholders
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/config"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type RenameCharacterRequest struct {
+	NewName string `json:"newName"`
+}
+
+type CharacterServicesResponse struct {
+	RenamePrice    int `json:"renamePrice"`
+	SexChangePrice int `json:"sexChangePrice"`
+}
+
+// errNotEnoughCoins is returned by chargeCoins when the account can't pay for a service
+var errNotEnoughCoins = errors.New("not enough coins")
+
+// GetCharacterRenamePrice returns the coins charged for a rename (CHARACTER_RENAME_PRICE, free by default)
+func GetCharacterRenamePrice() int {
+	return getServicePrice("CHARACTER_RENAME_PRICE")
+}
+
+// GetCharacterSexChangePrice returns the coins charged for a sex change (CHARACTER_SEX_CHANGE_PRICE, free by default)
+func GetCharacterSexChangePrice() int {
+	return getServicePrice("CHARACTER_SEX_CHANGE_PRICE")
+}
+
+func getServicePrice(env string) int {
+	price, err := strconv.Atoi(os.Getenv(env))
+	if err != nil || price < 0 {
+		return 0
+	}
+	return price
+}
+
+// GetCharacterServicesHandler returns the prices of the character services
+func GetCharacterServicesHandler(w http.ResponseWriter, r *http.Request) {
+	utils.WriteSuccess(w, http.StatusOK, "Character services retrieved successfully", CharacterServicesResponse{
+		RenamePrice:    GetCharacterRenamePrice(),
+		SexChangePrice: GetCharacterSexChangePrice(),
+	})
+}
+
+// RenameCharacterHandler renames an offline character. The old name is kept in player_former_names
+// so the character can still be found by it.
+func RenameCharacterHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req RenameCharacterRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	req.NewName = utils.SanitizeString(req.NewName, 255)
+	if msg := validateCharacterName(req.NewName); msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if msg := characterServiceUnavailable(char); msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var oldName string
+	if err := database.DB.QueryRowContext(ctx, "SELECT name FROM players WHERE id = ?", char.id).Scan(&oldName); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if req.NewName == oldName {
+		utils.WriteError(w, http.StatusBadRequest, "The new name must be different from the current one")
+		return
+	}
+
+	// Changing the case of the own name is allowed; any other existing name is taken
+	var exists bool
+	err = database.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM players WHERE name = ? AND id != ?)", req.NewName, char.id).Scan(&exists)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error checking character name")
+		return
+	}
+
+	if exists {
+		utils.WriteError(w, http.StatusConflict, "Character name already exists")
+		return
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error renaming character")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := chargeCoins(ctx, tx, userID, GetCharacterRenamePrice()); err != nil {
+		if errors.Is(err, errNotEnoughCoins) {
+			utils.WriteError(w, http.StatusBadRequest, "You don't have enough coins for a rename")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error renaming character")
+		return
+	}
+
+	// The online check is repeated in the update in case the character logged in meanwhile
+	result, err := tx.ExecContext(ctx,
+		`UPDATE players SET name = ?
+		 WHERE id = ? AND NOT EXISTS (SELECT 1 FROM players_online po WHERE po.player_id = ?)`,
+		req.NewName, char.id, char.id,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error renaming character")
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Cannot rename a character while it is online. Please log out first.")
+		return
+	}
+
+	// Only a real name change is recorded, not a case correction
+	if !strings.EqualFold(oldName, req.NewName) {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO player_former_names (player_id, name, changed_at) VALUES (?, ?, ?)",
+			char.id, oldName, time.Now().Unix(),
+		); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error renaming character")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error renaming character")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character renamed to "+req.NewName, map[string]string{
+		"name":    req.NewName,
+		"oldName": oldName,
+	})
+}
+
+// ChangeCharacterSexHandler switches an offline character between male and female.
+// The outfit is reset to the default one of the new sex since outfits are bound to a sex; colors are kept.
+func ChangeCharacterSexHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	char, err := loadOwnedCharacter(ctx, userID, mux.Vars(r)["name"])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Character not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if msg := characterServiceUnavailable(char); msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var sexID int
+	if err := database.DB.QueryRowContext(ctx, "SELECT sex FROM players WHERE id = ?", char.id).Scan(&sexID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	newSexID := config.SexMapping["female"]
+	if sexID == newSexID {
+		newSexID = config.SexMapping["male"]
+	}
+
+	lookType, ok := config.LookTypeMapping[newSexID]
+	if !ok {
+		lookType = 136
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error changing character sex")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := chargeCoins(ctx, tx, userID, GetCharacterSexChangePrice()); err != nil {
+		if errors.Is(err, errNotEnoughCoins) {
+			utils.WriteError(w, http.StatusBadRequest, "You don't have enough coins for a sex change")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error changing character sex")
+		return
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE players SET sex = ?, looktype = ?, lookaddons = 0
+		 WHERE id = ? AND sex = ? AND NOT EXISTS (SELECT 1 FROM players_online po WHERE po.player_id = ?)`,
+		newSexID, lookType, char.id, sexID, char.id,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error changing character sex")
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Cannot change the sex of a character while it is online. Please log out first.")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error changing character sex")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Character is now "+config.GetSexName(newSexID), map[string]string{
+		"sex": config.GetSexName(newSexID),
+	})
+}
+
+// characterServiceUnavailable returns why a character can't be renamed, have its sex changed or be auctioned right now
+func characterServiceUnavailable(char *ownedCharacter) string {
+	if char.online {
+		return "The character must be logged out first"
+	}
+	if char.deletionScheduledAt.Valid {
+		return "The character is scheduled for deletion. Cancel the deletion first."
+	}
+	if char.onAuction {
+		return "The character is on auction"
+	}
+	return ""
+}
+
+// chargeCoins takes the price of a service from the account within the caller's transaction
+func chargeCoins(ctx context.Context, tx *sql.Tx, accountID, price int) error {
+	if price <= 0 {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE accounts SET coins = coins - ? WHERE id = ? AND coins >= ?",
+		price, accountID, price,
+	)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errNotEnoughCoins
+	}
+	return nil
+}
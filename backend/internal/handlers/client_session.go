@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+)
+
+const DefaultClientSessionTTLHours = 24
+
+// useLegacyClientSessionKey reports whether the client should receive the old "email\npassword" session key.
+// Only meant for game servers that still authenticate the game world login with the account password.
+func useLegacyClientSessionKey() bool {
+	return os.Getenv("CLIENT_SESSION_LEGACY") == "true"
+}
+
+func getClientSessionTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("CLIENT_SESSION_TTL_HOURS"))
+	if err != nil || hours < 1 {
+		hours = DefaultClientSessionTTLHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// createClientSession stores a new game client session bound to the requesting IP and returns the plain session key.
+// Only the SHA-1 of the key is stored, which is what the game server looks up in account_sessions.id.
+func createClientSession(ctx context.Context, accountID int, ip string) (string, error) {
+	sessionKey, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = database.DB.ExecContext(ctx,
+		"INSERT INTO account_sessions (id, account_id, expires, ip, created_at) VALUES (?, ?, ?, ?, ?)",
+		utils.HashSHA1(sessionKey), accountID, now.Add(getClientSessionTTL()).Unix(), ip, now.Unix(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return sessionKey, nil
+}
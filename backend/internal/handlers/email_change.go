@@ -0,0 +1,719 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/mail"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	DefaultEmailChangeDelayHours = 24
+
+	// emailChangeConfirmTTL is how long both addresses have to confirm a requested change
+	emailChangeConfirmTTL = 24 * time.Hour
+)
+
+var errEmailChangeTaken = errors.New("email already in use")
+
+type EmailChangeRequest struct {
+	NewEmail string `json:"newEmail"`
+	Password string `json:"password"`
+	Token    string `json:"token"` // 2FA token, required when 2FA is enabled
+}
+
+type EmailChangeTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// EmailChangeStatus describes the pending email change of an account
+type EmailChangeStatus struct {
+	NewEmail       string `json:"newEmail"`
+	OldConfirmed   bool   `json:"oldConfirmed"`
+	NewConfirmed   bool   `json:"newConfirmed"`
+	ExpiresAt      int64  `json:"expiresAt"`
+	ApplyAfter     int64  `json:"applyAfter,omitempty"`
+	AppliedAt      int64  `json:"appliedAt,omitempty"`
+	ConfirmedEmail string `json:"confirmedEmail,omitempty"`
+}
+
+type emailChange struct {
+	ID             int
+	AccountID      int
+	OldEmail       string
+	NewEmail       string
+	OldTokenHash   string
+	OldConfirmedAt sql.NullInt64
+	NewConfirmedAt sql.NullInt64
+	ExpiresAt      int64
+	ApplyAfter     sql.NullInt64
+	RequestIP      string
+}
+
+func (c *emailChange) status() EmailChangeStatus {
+	return EmailChangeStatus{
+		NewEmail:     c.NewEmail,
+		OldConfirmed: c.OldConfirmedAt.Valid,
+		NewConfirmed: c.NewConfirmedAt.Valid,
+		ExpiresAt:    c.ExpiresAt,
+		ApplyAfter:   c.ApplyAfter.Int64,
+	}
+}
+
+// expired reports whether the confirmation links ran out before both addresses confirmed
+func (c *emailChange) expired() bool {
+	return !c.ApplyAfter.Valid && c.ExpiresAt <= time.Now().Unix()
+}
+
+// getEmailChangeDelay returns how long a confirmed change waits before it is applied, so the
+// owner of the old address can still cancel it. EMAIL_CHANGE_DELAY_HOURS=0 applies it right away.
+func getEmailChangeDelay() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("EMAIL_CHANGE_DELAY_HOURS"))
+	if err != nil || hours < 0 {
+		hours = DefaultEmailChangeDelayHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// GetEmailChangeHandler returns the pending email change of the logged in account, if any
+func GetEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	change, err := loadPendingEmailChange(ctx, database.DB, "account_id = ? ORDER BY id DESC LIMIT 1", userID)
+	if err == nil && change.expired() {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteSuccess(w, http.StatusOK, "No pending email change", nil)
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching email change")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Pending email change found", change.status())
+}
+
+// RequestEmailChangeHandler starts an email change. Nothing changes until both the current and the new
+// address confirm through the links emailed to them.
+func RequestEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req EmailChangeRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	req.NewEmail = utils.SanitizeString(req.NewEmail, 255)
+	if !utils.IsValidEmail(req.NewEmail) {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid email")
+		return
+	}
+
+	if req.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	if len(req.Password) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword, currentEmail, secret string
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT password, email, COALESCE(secret, '') FROM accounts WHERE id = ?",
+		userID,
+	).Scan(&storedPassword, &currentEmail, &secret)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
+		return
+	}
+
+	if strings.EqualFold(req.NewEmail, currentEmail) {
+		utils.WriteError(w, http.StatusBadRequest, "The new email is the same as the current one")
+		return
+	}
+
+	var exists bool
+	if err := database.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE email = ?)", req.NewEmail).Scan(&exists); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+	if exists {
+		utils.WriteError(w, http.StatusConflict, "Email already in use")
+		return
+	}
+
+	// Checked last so a backup code isn't used up by a request that fails anyway
+	if secret != "" {
+		if req.Token == "" {
+			utils.WriteError(w, http.StatusBadRequest, "2FA token is required")
+			return
+		}
+		if !verifyTwoFactor(ctx, userID, secret, req.Token) {
+			utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
+			return
+		}
+	}
+
+	oldToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+	newToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(emailChangeConfirmTTL)
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+	defer tx.Rollback()
+
+	// Only one change can be pending at a time; a new request replaces the previous one
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE account_email_changes SET cancelled_at = ? WHERE account_id = ? AND applied_at IS NULL AND cancelled_at IS NULL",
+		now.Unix(), userID,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO account_email_changes (account_id, old_email, new_email, old_token_hash, new_token_hash, expires_at, request_ip)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		userID, currentEmail, req.NewEmail, utils.HashSHA256(oldToken), utils.HashSHA256(newToken), expiresAt.Unix(), utils.GetClientIP(r),
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error requesting email change")
+		return
+	}
+
+	hours := int(emailChangeConfirmTTL.Hours())
+	messages := []mail.Message{
+		{
+			To:      currentEmail,
+			Subject: "Confirm your email change",
+			Body: fmt.Sprintf(
+				"A change of your account email to %s was requested.\n\n"+
+					"Open the link below to confirm it:\n%s/account/email/confirm?token=%s\n\n"+
+					"If you did not request this, cancel it here and change your password:\n%s/account/email/confirm?token=%s&cancel=1\n\n"+
+					"The change also has to be confirmed from the new address. The links expire in %d hours.",
+				req.NewEmail, getSiteURL(), oldToken, getSiteURL(), oldToken, hours,
+			),
+		},
+		{
+			To:      req.NewEmail,
+			Subject: "Confirm your new email address",
+			Body: fmt.Sprintf(
+				"This address was entered as the new email of an account.\n\n"+
+					"Open the link below to confirm it:\n%s/account/email/confirm?token=%s\n\n"+
+					"The link expires in %d hours. If you did not expect this, you can ignore this email.",
+				getSiteURL(), newToken, hours,
+			),
+		},
+	}
+
+	go func() {
+		for _, msg := range messages {
+			if err := mail.Send(msg); err != nil {
+				log.Printf("Error sending email change confirmation for account %d: %v", userID, err)
+			}
+		}
+	}()
+
+	utils.WriteSuccess(w, http.StatusOK, "Confirmation links have been sent to your current and your new email address", EmailChangeStatus{
+		NewEmail:  req.NewEmail,
+		ExpiresAt: expiresAt.Unix(),
+	})
+}
+
+// LookupEmailChangeHandler shows the pending change behind an emailed token without confirming it,
+// so the confirmation page can wait for a click instead of acting as soon as a link scanner opens it
+func LookupEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	var req EmailChangeTokenRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid or expired confirmation link")
+		return
+	}
+	tokenHash := utils.HashSHA256(req.Token)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	change, err := loadPendingEmailChange(ctx, database.DB, "(old_token_hash = ? OR new_token_hash = ?)", tokenHash, tokenHash)
+	if err == nil && change.expired() {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid or expired confirmation link")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching email change")
+		return
+	}
+
+	// ConfirmedEmail is the address this link confirms once it is used
+	status := change.status()
+	status.ConfirmedEmail = change.NewEmail
+	if tokenHash == change.OldTokenHash {
+		status.ConfirmedEmail = change.OldEmail
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Email change retrieved successfully", status)
+}
+
+// ConfirmEmailChangeHandler confirms one side of a pending email change with an emailed token.
+// Once both addresses have confirmed, the change is applied after the cancellation window.
+func ConfirmEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	var req EmailChangeTokenRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid or expired confirmation link")
+		return
+	}
+	tokenHash := utils.HashSHA256(req.Token)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := loadPendingEmailChange(ctx, tx, "(old_token_hash = ? OR new_token_hash = ?) FOR UPDATE", tokenHash, tokenHash)
+	if err == nil && change.expired() {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid or expired confirmation link")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+		return
+	}
+
+	now := time.Now().Unix()
+	column, confirmedEmail, confirmedAt := "new_confirmed_at", change.NewEmail, &change.NewConfirmedAt
+	if tokenHash == change.OldTokenHash {
+		column, confirmedEmail, confirmedAt = "old_confirmed_at", change.OldEmail, &change.OldConfirmedAt
+	}
+	if !confirmedAt.Valid {
+		*confirmedAt = sql.NullInt64{Int64: now, Valid: true}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE account_email_changes SET "+column+" = COALESCE("+column+", ?) WHERE id = ?",
+		now, change.ID,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+		return
+	}
+
+	bothConfirmed := change.OldConfirmedAt.Valid && change.NewConfirmedAt.Valid
+	applyNow := false
+	if bothConfirmed && !change.ApplyAfter.Valid {
+		applyAfter := time.Now().Add(getEmailChangeDelay()).Unix()
+		if _, err := tx.ExecContext(ctx, "UPDATE account_email_changes SET apply_after = ? WHERE id = ?", applyAfter, change.ID); err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+			return
+		}
+		change.ApplyAfter = sql.NullInt64{Int64: applyAfter, Valid: true}
+		applyNow = applyAfter <= now
+	}
+
+	if applyNow {
+		if err := applyEmailChange(ctx, tx, change); err != nil {
+			if errors.Is(err, errEmailChangeTaken) {
+				if err := tx.Commit(); err != nil {
+					utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+					return
+				}
+				utils.WriteError(w, http.StatusConflict, "The new email is already used by another account. The change was cancelled")
+				return
+			}
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error confirming email change")
+		return
+	}
+
+	status := change.status()
+	status.ConfirmedEmail = confirmedEmail
+
+	switch {
+	case applyNow:
+		emailChangeApplied(change)
+		status.AppliedAt = now
+		utils.WriteSuccess(w, http.StatusOK, "Your account email has been changed", status)
+	case bothConfirmed:
+		notifyEmailChangeScheduled(change)
+		utils.WriteSuccess(w, http.StatusOK, "Both addresses are confirmed. The change will be applied after the cancellation window", status)
+	default:
+		utils.WriteSuccess(w, http.StatusOK, "Address confirmed. The change still has to be confirmed from the other address", status)
+	}
+}
+
+// CancelEmailChangeByTokenHandler cancels a pending email change from one of the emailed links
+func CancelEmailChangeByTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req EmailChangeTokenRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid or expired link")
+		return
+	}
+	tokenHash := utils.HashSHA256(req.Token)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx,
+		`UPDATE account_email_changes SET cancelled_at = ?
+		 WHERE (old_token_hash = ? OR new_token_hash = ?) AND applied_at IS NULL AND cancelled_at IS NULL`,
+		time.Now().Unix(), tokenHash, tokenHash,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error cancelling email change")
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid or expired link")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "The email change has been cancelled", nil)
+}
+
+// CancelEmailChangeHandler cancels the pending email change of the logged in account
+func CancelEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx,
+		"UPDATE account_email_changes SET cancelled_at = ? WHERE account_id = ? AND applied_at IS NULL AND cancelled_at IS NULL",
+		time.Now().Unix(), userID,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error cancelling email change")
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		utils.WriteError(w, http.StatusNotFound, "No pending email change")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "The email change has been cancelled", nil)
+}
+
+// ApplyDueEmailChanges applies the confirmed email changes whose cancellation window has passed.
+// Returns how many were applied.
+func ApplyDueEmailChanges() (int, error) {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT id FROM account_email_changes
+		 WHERE apply_after IS NOT NULL AND apply_after <= ? AND applied_at IS NULL AND cancelled_at IS NULL`,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, id := range ids {
+		change, err := applyDueEmailChange(ctx, id)
+		if err != nil {
+			log.Printf("Error applying email change %d: %v", id, err)
+			continue
+		}
+		if change != nil {
+			emailChangeApplied(change)
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+func applyDueEmailChange(ctx context.Context, id int) (*emailChange, error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Re-check under lock, the change may have been cancelled since it was listed
+	change, err := loadPendingEmailChange(ctx, tx, "id = ? AND apply_after <= ? FOR UPDATE", id, time.Now().Unix())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := applyEmailChange(ctx, tx, change); err != nil {
+		if errors.Is(err, errEmailChangeTaken) {
+			log.Printf("Email change %d for account %d cancelled: %s is already in use", change.ID, change.AccountID, change.NewEmail)
+			return nil, tx.Commit()
+		}
+		return nil, err
+	}
+
+	return change, tx.Commit()
+}
+
+// applyEmailChange writes the new email to the account. When another account took the address in the
+// meantime the change is cancelled instead and errEmailChangeTaken is returned; commit tx to keep that.
+func applyEmailChange(ctx context.Context, tx *sql.Tx, change *emailChange) error {
+	now := time.Now().Unix()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM accounts WHERE email = ? AND id <> ?)",
+		change.NewEmail, change.AccountID,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		if _, err := tx.ExecContext(ctx, "UPDATE account_email_changes SET cancelled_at = ? WHERE id = ?", now, change.ID); err != nil {
+			return err
+		}
+		return errEmailChangeTaken
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET email = ? WHERE id = ?", change.NewEmail, change.AccountID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE account_email_changes SET applied_at = ? WHERE id = ?", now, change.ID)
+	return err
+}
+
+// emailChangeApplied records the audit entry and tells the old address once a change went through
+func emailChangeApplied(change *emailChange) {
+	writeAuditLog(change.AccountID, change.RequestIP, AuditEntry{
+		Action:     "account.email.change",
+		TargetType: "account",
+		TargetID:   strconv.Itoa(change.AccountID),
+		Before:     map[string]interface{}{"email": change.OldEmail},
+		After:      map[string]interface{}{"email": change.NewEmail},
+	})
+
+	msg := mail.Message{
+		To:      change.OldEmail,
+		Subject: "Your account email was changed",
+		Body: fmt.Sprintf(
+			"The email of your account has been changed to %s.\n\n"+
+				"If you did not do this, contact the staff right away.",
+			change.NewEmail,
+		),
+	}
+	go func() {
+		if err := mail.Send(msg); err != nil {
+			log.Printf("Error sending email change notice for account %d: %v", change.AccountID, err)
+		}
+	}()
+}
+
+// notifyEmailChangeScheduled reminds the old address that it can still cancel a confirmed change
+func notifyEmailChangeScheduled(change *emailChange) {
+	msg := mail.Message{
+		To:      change.OldEmail,
+		Subject: "Your account email will be changed",
+		Body: fmt.Sprintf(
+			"The change of your account email to %s has been confirmed from both addresses.\n\n"+
+				"It will be applied on %s. Until then you can cancel it with the link from the first email "+
+				"or from the account settings.",
+			change.NewEmail, time.Unix(change.ApplyAfter.Int64, 0).UTC().Format("Jan 2, 2006, 15:04 MST"),
+		),
+	}
+	go func() {
+		if err := mail.Send(msg); err != nil {
+			log.Printf("Error sending email change notice for account %d: %v", change.AccountID, err)
+		}
+	}()
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// loadPendingEmailChange finds a change that was neither applied nor cancelled. condition comes from code.
+func loadPendingEmailChange(ctx context.Context, db queryRower, condition string, args ...interface{}) (*emailChange, error) {
+	var change emailChange
+	err := db.QueryRowContext(ctx,
+		`SELECT id, account_id, old_email, new_email, old_token_hash, old_confirmed_at, new_confirmed_at,
+		        expires_at, apply_after, COALESCE(request_ip, '')
+		 FROM account_email_changes
+		 WHERE applied_at IS NULL AND cancelled_at IS NULL AND `+condition,
+		args...,
+	).Scan(&change.ID, &change.AccountID, &change.OldEmail, &change.NewEmail, &change.OldTokenHash,
+		&change.OldConfirmedAt, &change.NewConfirmedAt, &change.ExpiresAt, &change.ApplyAfter, &change.RequestIP)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
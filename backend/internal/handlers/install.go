@@ -3,6 +3,7 @@ package handlers
 import (
 	"bufio"
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
@@ -440,6 +441,7 @@ func ApplySchema(ctx context.Context) map[string]string {
 		"page_access":          "TINYINT NOT NULL DEFAULT 0",
 		"deletion_scheduled_at": "BIGINT UNSIGNED NULL",
 		"status":                "VARCHAR(50) NULL DEFAULT 'active'",
+		"recovery_key":          "CHAR(64) NULL",
 	}
 
 	for columnName, columnDef := range columns {
@@ -566,6 +568,342 @@ func ApplySchema(ctx context.Context) map[string]string {
 		results["accounts."+columnName] = "added"
 	}
 
+	// 9. Widen accounts.password so bcrypt/argon2id hashes fit (game servers ship it as CHAR(40))
+	var passwordLength sql.NullInt64
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT CHARACTER_MAXIMUM_LENGTH FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = 'accounts' AND column_name = 'password'",
+	).Scan(&passwordLength)
+	if err == nil && passwordLength.Valid && passwordLength.Int64 < 255 {
+		_, err = database.DB.ExecContext(ctx, "ALTER TABLE accounts MODIFY COLUMN password VARCHAR(255) NOT NULL")
+		if err != nil {
+			results["accounts.password"] = "Error widening: " + err.Error()
+		} else {
+			results["accounts.password"] = "added"
+		}
+	} else if err == nil {
+		results["accounts.password"] = "already exists"
+	}
+
+	// 10. Check and add account_recovery_tokens table if missing
+	if err := CreateTableIfNotExists(ctx, "account_recovery_tokens", `
+		CREATE TABLE IF NOT EXISTS account_recovery_tokens (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			purpose VARCHAR(32) NOT NULL DEFAULT 'password_reset',
+			token_hash CHAR(64) NOT NULL,
+			expires_at BIGINT UNSIGNED NOT NULL,
+			used_at BIGINT UNSIGNED NULL,
+			request_ip VARCHAR(45) NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY unique_token_hash (token_hash),
+			INDEX idx_account_purpose (account_id, purpose),
+			INDEX idx_expires_at (expires_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_recovery_tokens"] = "Error: " + err.Error()
+	}
+
+	// 11. Check and add account_sessions table used for game client session keys (same layout the game server reads)
+	if err := CreateTableIfNotExists(ctx, "account_sessions", `
+		CREATE TABLE IF NOT EXISTS account_sessions (
+			id VARCHAR(191) NOT NULL PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			expires BIGINT UNSIGNED NOT NULL,
+			ip VARCHAR(45) NULL,
+			created_at BIGINT UNSIGNED NULL,
+			INDEX idx_account_id (account_id),
+			INDEX idx_expires (expires)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_sessions"] = "Error: " + err.Error()
+	}
+
+	// 11.1. Servers that already ship account_sessions lack the IP binding columns
+	sessionColumns := map[string]string{
+		"ip":         "VARCHAR(45) NULL",
+		"created_at": "BIGINT UNSIGNED NULL",
+	}
+	for columnName, columnDef := range sessionColumns {
+		var exists bool
+		err := database.DB.QueryRowContext(ctx,
+			"SELECT COUNT(*) > 0 FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = 'account_sessions' AND column_name = ?",
+			columnName,
+		).Scan(&exists)
+		if err != nil {
+			results["account_sessions."+columnName] = "Error checking: " + err.Error()
+			continue
+		}
+		if exists {
+			results["account_sessions."+columnName] = "already exists"
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE account_sessions ADD COLUMN %s %s", columnName, columnDef)
+		if _, err := database.DB.ExecContext(ctx, query); err != nil {
+			results["account_sessions."+columnName] = "Error adding: " + err.Error()
+			continue
+		}
+		results["account_sessions."+columnName] = "added"
+	}
+
+	// 12. Check and add account_web_sessions table (one row per website login, id is the JWT jti)
+	if err := CreateTableIfNotExists(ctx, "account_web_sessions", `
+		CREATE TABLE IF NOT EXISTS account_web_sessions (
+			id VARCHAR(64) NOT NULL PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			refresh_token_hash CHAR(64) NOT NULL,
+			ip VARCHAR(45) NULL,
+			user_agent VARCHAR(255) NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			last_seen_at BIGINT UNSIGNED NOT NULL,
+			expires_at BIGINT UNSIGNED NOT NULL,
+			revoked_at BIGINT UNSIGNED NULL,
+			UNIQUE KEY unique_refresh_token_hash (refresh_token_hash),
+			INDEX idx_account_id (account_id),
+			INDEX idx_expires_at (expires_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_web_sessions"] = "Error: " + err.Error()
+	}
+
+	// 13. Check and add account_roles table (staff roles, see pkg/auth/permissions.go)
+	if err := CreateTableIfNotExists(ctx, "account_roles", `
+		CREATE TABLE IF NOT EXISTS account_roles (
+			account_id INT UNSIGNED NOT NULL,
+			role VARCHAR(32) NOT NULL,
+			granted_by INT UNSIGNED NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			PRIMARY KEY (account_id, role)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_roles"] = "Error: " + err.Error()
+	}
+
+	// 14. Check and add admin_audit_log table (who changed what from the admin panel)
+	if err := CreateTableIfNotExists(ctx, "admin_audit_log", `
+		CREATE TABLE IF NOT EXISTS admin_audit_log (
+			id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			actor_id INT UNSIGNED NOT NULL,
+			action VARCHAR(64) NOT NULL,
+			target_type VARCHAR(32) NOT NULL,
+			target_id VARCHAR(320) NULL,
+			changes MEDIUMTEXT NULL,
+			ip VARCHAR(45) NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			INDEX idx_actor_id (actor_id),
+			INDEX idx_action (action),
+			INDEX idx_target (target_type, target_id),
+			INDEX idx_created_at (created_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["admin_audit_log"] = "Error: " + err.Error()
+	}
+
+	// 15. Check and add account_email_changes table (pending email changes confirmed by both addresses)
+	if err := CreateTableIfNotExists(ctx, "account_email_changes", `
+		CREATE TABLE IF NOT EXISTS account_email_changes (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			old_email VARCHAR(255) NOT NULL,
+			new_email VARCHAR(255) NOT NULL,
+			old_token_hash CHAR(64) NOT NULL,
+			new_token_hash CHAR(64) NOT NULL,
+			old_confirmed_at BIGINT UNSIGNED NULL,
+			new_confirmed_at BIGINT UNSIGNED NULL,
+			expires_at BIGINT UNSIGNED NOT NULL,
+			apply_after BIGINT UNSIGNED NULL,
+			applied_at BIGINT UNSIGNED NULL,
+			cancelled_at BIGINT UNSIGNED NULL,
+			request_ip VARCHAR(45) NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY unique_old_token_hash (old_token_hash),
+			UNIQUE KEY unique_new_token_hash (new_token_hash),
+			INDEX idx_account_id (account_id),
+			INDEX idx_apply_after (apply_after)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_email_changes"] = "Error: " + err.Error()
+	}
+
+	// 16. Check and add account_backup_codes table (single-use 2FA backup codes, stored hashed)
+	if err := CreateTableIfNotExists(ctx, "account_backup_codes", `
+		CREATE TABLE IF NOT EXISTS account_backup_codes (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			code_hash CHAR(64) NOT NULL,
+			used_at BIGINT UNSIGNED NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			UNIQUE KEY unique_account_code (account_id, code_hash)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_backup_codes"] = "Error: " + err.Error()
+	}
+
+	// 17. Check and add account_passkeys table (WebAuthn credentials used as second factor or passwordless login)
+	if err := CreateTableIfNotExists(ctx, "account_passkeys", `
+		CREATE TABLE IF NOT EXISTS account_passkeys (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			account_id INT UNSIGNED NOT NULL,
+			credential_id VARCHAR(255) NOT NULL,
+			name VARCHAR(64) NOT NULL,
+			credential TEXT NOT NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			last_used_at BIGINT UNSIGNED NULL,
+			UNIQUE KEY unique_credential_id (credential_id),
+			INDEX idx_account_id (account_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["account_passkeys"] = "Error: " + err.Error()
+	}
+
+	// 18. Check and add webauthn_ceremonies table (pending passkey registrations and logins)
+	if err := CreateTableIfNotExists(ctx, "webauthn_ceremonies", `
+		CREATE TABLE IF NOT EXISTS webauthn_ceremonies (
+			token_hash CHAR(64) PRIMARY KEY,
+			account_id INT UNSIGNED NULL,
+			kind VARCHAR(16) NOT NULL,
+			session_data TEXT NOT NULL,
+			expires_at BIGINT UNSIGNED NOT NULL,
+			INDEX idx_expires_at (expires_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["webauthn_ceremonies"] = "Error: " + err.Error()
+	}
+
+	// 19. Check and add player_site_settings table (characters hidden or scheduled for deletion by their owner)
+	if err := CreateTableIfNotExists(ctx, "player_site_settings", `
+		CREATE TABLE IF NOT EXISTS player_site_settings (
+			player_id INT NOT NULL PRIMARY KEY,
+			hidden TINYINT(1) NOT NULL DEFAULT 0,
+			deletion_scheduled_at BIGINT UNSIGNED NULL,
+			INDEX idx_deletion_scheduled_at (deletion_scheduled_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_site_settings"] = "Error: " + err.Error()
+	}
+
+	// 20. Check and add player_former_names table (names a character had before being renamed)
+	if err := CreateTableIfNotExists(ctx, "player_former_names", `
+		CREATE TABLE IF NOT EXISTS player_former_names (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			player_id INT NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			changed_at BIGINT UNSIGNED NOT NULL,
+			INDEX idx_player_id (player_id),
+			INDEX idx_name (name)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_former_names"] = "Error: " + err.Error()
+	}
+
+	// 21. Check and add character_auctions table (character bazaar)
+	if err := CreateTableIfNotExists(ctx, "character_auctions", `
+		CREATE TABLE IF NOT EXISTS character_auctions (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			player_id INT NOT NULL,
+			seller_account_id INT NOT NULL,
+			minimum_bid INT UNSIGNED NOT NULL,
+			current_bid INT UNSIGNED NOT NULL DEFAULT 0,
+			highest_bidder_account_id INT NULL,
+			bid_count INT UNSIGNED NOT NULL DEFAULT 0,
+			status VARCHAR(16) NOT NULL DEFAULT 'active',
+			created_at BIGINT UNSIGNED NOT NULL,
+			ends_at BIGINT UNSIGNED NOT NULL,
+			settled_at BIGINT UNSIGNED NULL,
+			INDEX idx_status_ends_at (status, ends_at),
+			INDEX idx_player_id (player_id),
+			INDEX idx_seller_account_id (seller_account_id),
+			INDEX idx_highest_bidder_account_id (highest_bidder_account_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["character_auctions"] = "Error: " + err.Error()
+	}
+
+	// 22. Check and add character_auction_events table (audit trail of every auction state change and coin movement)
+	if err := CreateTableIfNotExists(ctx, "character_auction_events", `
+		CREATE TABLE IF NOT EXISTS character_auction_events (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			auction_id INT NOT NULL,
+			account_id INT NULL,
+			event VARCHAR(16) NOT NULL,
+			from_status VARCHAR(16) NULL,
+			to_status VARCHAR(16) NOT NULL,
+			amount INT UNSIGNED NOT NULL DEFAULT 0,
+			ip VARCHAR(45) NULL,
+			created_at BIGINT UNSIGNED NOT NULL,
+			INDEX idx_auction_id (auction_id),
+			INDEX idx_account_id (account_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["character_auction_events"] = "Error: " + err.Error()
+	}
+
+	// 23. Check and add player_profiles table (comment and profile fields set by the character owner)
+	if err := CreateTableIfNotExists(ctx, "player_profiles", `
+		CREATE TABLE IF NOT EXISTS player_profiles (
+			player_id INT NOT NULL PRIMARY KEY,
+			comment TEXT NULL,
+			real_name VARCHAR(50) NULL,
+			location VARCHAR(50) NULL,
+			is_main TINYINT(1) NOT NULL DEFAULT 0,
+			updated_at BIGINT UNSIGNED NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_profiles"] = "Error: " + err.Error()
+	}
+
+	// 24. Check and add player_experience_history table (daily level and experience snapshots, only stored on change)
+	if err := CreateTableIfNotExists(ctx, "player_experience_history", `
+		CREATE TABLE IF NOT EXISTS player_experience_history (
+			player_id INT NOT NULL,
+			day DATE NOT NULL,
+			level INT NOT NULL,
+			experience BIGINT UNSIGNED NOT NULL,
+			PRIMARY KEY (player_id, day),
+			INDEX idx_day (day)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_experience_history"] = "Error: " + err.Error()
+	}
+
+	// 25. Check and add player_online_sessions table (login and logout times seen by the online poller)
+	if err := CreateTableIfNotExists(ctx, "player_online_sessions", `
+		CREATE TABLE IF NOT EXISTS player_online_sessions (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			player_id INT NOT NULL,
+			login_at BIGINT UNSIGNED NOT NULL,
+			last_seen_at BIGINT UNSIGNED NOT NULL,
+			logout_at BIGINT UNSIGNED NULL,
+			INDEX idx_player_login (player_id, login_at),
+			INDEX idx_logout_at (logout_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_online_sessions"] = "Error: " + err.Error()
+	}
+
+	// 26. Check and add player_online_daily table (seconds online per character and day)
+	if err := CreateTableIfNotExists(ctx, "player_online_daily", `
+		CREATE TABLE IF NOT EXISTS player_online_daily (
+			player_id INT NOT NULL,
+			day DATE NOT NULL,
+			seconds INT UNSIGNED NOT NULL DEFAULT 0,
+			PRIMARY KEY (player_id, day),
+			INDEX idx_day (day)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["player_online_daily"] = "Error: " + err.Error()
+	}
+
+	// 27. Check and add server_online_peaks table (highest number of players online per day)
+	if err := CreateTableIfNotExists(ctx, "server_online_peaks", `
+		CREATE TABLE IF NOT EXISTS server_online_peaks (
+			day DATE NOT NULL PRIMARY KEY,
+			players INT UNSIGNED NOT NULL,
+			recorded_at BIGINT UNSIGNED NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`, "", &results); err != nil {
+		results["server_online_peaks"] = "Error: " + err.Error()
+	}
+
 	return results
 }
 
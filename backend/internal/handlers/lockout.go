@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+type ClearLockoutRequest struct {
+	Key string `json:"key"`
+}
+
+// GetLockoutsHandler lists the IPs and accounts currently locked out of logging in
+func GetLockoutsHandler(w http.ResponseWriter, r *http.Request) {
+	utils.WriteSuccess(w, http.StatusOK, "Lockouts retrieved successfully", middleware.LoginLockouts())
+}
+
+// ClearLockoutHandler lifts a login lockout before it expires
+func ClearLockoutHandler(w http.ResponseWriter, r *http.Request) {
+	var req ClearLockoutRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Key == "" || len(req.Key) > 320 || !middleware.ClearLoginLockout(req.Key) {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid lockout key")
+		return
+	}
+
+	recordAdminAction(r, AuditEntry{
+		Action:     "lockout.clear",
+		TargetType: "lockout",
+		TargetID:   req.Key,
+	})
+
+	utils.WriteSuccess(w, http.StatusOK, "Lockout cleared successfully", nil)
+}
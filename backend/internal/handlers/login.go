@@ -1,27 +1,33 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"log"
 	"net/http"
-	"os"
+	"strconv"
+	"time"
 
 	"codexaac-backend/internal/database"
 	"codexaac-backend/pkg/auth"
-	"codexaac-backend/pkg/twofactor"
+	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/utils"
 )
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Token    string `json:"token,omitempty"` // 2FA token (required if account has 2FA enabled)
+	Email    string            `json:"email"`
+	Password string            `json:"password"`
+	Token    string            `json:"token,omitempty"`   // 2FA token (required if account has 2FA enabled)
+	Passkey  *PasskeyAssertion `json:"passkey,omitempty"` // Passkey assertion, accepted instead of the 2FA token
 }
 
 type LoginResponse struct {
-	Token        string `json:"token,omitempty"`        // JWT token (for development - localStorage)
-	Requires2FA  bool   `json:"requires2FA,omitempty"`  // True if 2FA token is required
-	Message      string `json:"message,omitempty"`      // Message for the user
+	Token            string `json:"token,omitempty"`            // JWT token (for development - localStorage)
+	Requires2FA      bool   `json:"requires2FA,omitempty"`      // True if 2FA token is required
+	PasskeyAvailable bool   `json:"passkeyAvailable,omitempty"` // True if a passkey can be used instead of the 2FA token
+	PasskeyRequired  bool   `json:"passkeyRequired,omitempty"`  // True if the account has no 2FA token and must confirm with a passkey
+	Message          string `json:"message,omitempty"`          // Message for the user
 	// Note: Token is sent via httpOnly cookie in production, and in JSON for development (different ports)
 }
 
@@ -49,7 +55,14 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hashedPassword := utils.HashSHA1(req.Password)
+	if retryAfter := middleware.CheckLoginAllowed(r); retryAfter > 0 {
+		writeLoginLockedOut(w, retryAfter)
+		return
+	}
+
+	if !checkAccountThrottle(w, r, req.Email) {
+		return
+	}
 
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
@@ -72,7 +85,7 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	} else {
-		loginValid = (storedPassword == hashedPassword)
+		loginValid = verifyLoginPassword(ctx, userID, req.Password, storedPassword)
 		
 		if secret.Valid && secret.String != "" {
 			has2FA = true
@@ -81,41 +94,107 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !loginValid {
+		middleware.RecordLoginFailure(r, req.Email)
 		utils.WriteError(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
-	if has2FA {
-		if len(req.Token) > 6 {
+	passkeys, err := countPasskeys(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	// A registered passkey is a second factor on its own, so accounts without 2FA have to confirm with it
+	if has2FA || passkeys > 0 {
+		if len(req.Token) > maxTwoFactorTokenLength {
 			utils.WriteError(w, http.StatusBadRequest, "Invalid 2FA token format")
 			return
 		}
 
-		if req.Token == "" {
+		if req.Passkey != nil {
+			if !verifyPasskeySecondFactor(ctx, userID, req.Passkey) {
+				middleware.RecordLoginFailure(r, req.Email)
+				utils.WriteError(w, http.StatusUnauthorized, "Invalid passkey")
+				return
+			}
+		} else if !has2FA {
 			utils.WriteJSON(w, http.StatusOK, LoginResponse{
-				Requires2FA: true,
-				Message:     "Two-factor authentication required. Please enter your 2FA token.",
+				Requires2FA:      true,
+				PasskeyAvailable: true,
+				PasskeyRequired:  true,
+				Message:          "Please confirm the login with your passkey.",
 			})
 			return
-		}
-
-		if !twofactor.ValidateToken(twoFactorSecret, req.Token) {
+		} else if req.Token == "" {
+			utils.WriteJSON(w, http.StatusOK, LoginResponse{
+				Requires2FA:      true,
+				PasskeyAvailable: passkeys > 0,
+				Message:          "Two-factor authentication required. Please enter your 2FA token.",
+			})
+			return
+		} else if !verifyTwoFactor(ctx, userID, twoFactorSecret, req.Token) {
+			middleware.RecordLoginFailure(r, req.Email)
 			utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
 			return
 		}
 	}
 
-	jwtToken, err := auth.GenerateToken(userID)
+	middleware.RecordLoginSuccess(req.Email)
+
+	jwtToken, err := issueSession(ctx, w, r, userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Error generating token")
 		return
 	}
 
-	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
-	utils.SetAuthCookie(w, jwtToken, isSecure)
-
 	utils.WriteJSON(w, http.StatusOK, LoginResponse{
 		Token:   jwtToken,
 		Message: "Login successful",
 	})
 }
+
+// writeLoginLockedOut rejects a login attempt while the IP or account is locked out
+func writeLoginLockedOut(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	utils.WriteError(w, http.StatusTooManyRequests, "Too many failed login attempts. Please try again in "+middleware.FormatRetryAfter(retryAfter)+".")
+}
+
+// checkAccountThrottle makes every attempt at an account that is being guessed at from many IPs solve a
+// challenge first, so the owner can still log in while the attacker pays for each try. Without a challenge
+// configured the attempt has to wait out the throttle. Returns false after writing the response.
+func checkAccountThrottle(w http.ResponseWriter, r *http.Request, email string) bool {
+	retryAfter := middleware.AccountLoginThrottled(email)
+	if retryAfter <= 0 {
+		return true
+	}
+	if middleware.GetChallenge() == nil {
+		writeLoginLockedOut(w, retryAfter)
+		return false
+	}
+	return middleware.RequireChallenge(w, r)
+}
+
+// verifyLoginPassword checks the password and transparently rehashes legacy hashes
+// with the configured algorithm after a successful login
+func verifyLoginPassword(ctx context.Context, accountID int, password, storedHash string) bool {
+	valid, needsRehash := auth.VerifyPassword(password, storedHash)
+	if !valid || !needsRehash {
+		return valid
+	}
+
+	newHash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Printf("Error rehashing password for account %d: %v", accountID, err)
+		return true
+	}
+
+	if _, err := database.DB.ExecContext(ctx, "UPDATE accounts SET password = ? WHERE id = ?", newHash, accountID); err != nil {
+		log.Printf("Error upgrading password hash for account %d: %v", accountID, err)
+	}
+
+	return true
+}
@@ -4,12 +4,27 @@ import (
 	"net/http"
 	"os"
 
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/utils"
 )
 
 func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	// Revoke the session server-side so a copied token can't outlive the logout
+	if refreshToken, err := utils.GetRefreshCookie(r); err == nil && refreshToken != "" {
+		_ = auth.RevokeSessionByRefreshToken(ctx, refreshToken)
+	}
+	if tokenString, err := utils.GetAuthCookie(r); err == nil {
+		if claims, err := auth.ValidateToken(tokenString); err == nil && claims.ID != "" {
+			_, _ = auth.RevokeSession(ctx, claims.UserID, claims.ID)
+		}
+	}
+
 	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
 	utils.ClearAuthCookie(w, isSecure)
+	utils.ClearRefreshCookie(w, isSecure)
 
 	utils.WriteJSON(w, http.StatusOK, map[string]string{
 		"message": "Logged out successfully",
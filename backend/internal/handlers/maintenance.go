@@ -110,6 +110,10 @@ func ToggleMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var wasEnabled bool
+	var previousMessage sql.NullString
+	_ = database.DB.QueryRowContext(ctx, "SELECT enabled, message FROM maintenance WHERE id = 1").Scan(&wasEnabled, &previousMessage)
+
 	_, err := database.DB.ExecContext(ctx,
 		"INSERT INTO maintenance (id, enabled, message, updated_at) VALUES (1, ?, ?, NOW()) ON DUPLICATE KEY UPDATE enabled = ?, message = ?, updated_at = NOW()",
 		request.Enabled, request.Message, request.Enabled, request.Message,
@@ -120,6 +124,14 @@ func ToggleMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "maintenance.update",
+		TargetType: "maintenance",
+		TargetID:   "1",
+		Before:     map[string]interface{}{"enabled": wasEnabled, "message": previousMessage.String},
+		After:      map[string]interface{}{"enabled": request.Enabled, "message": request.Message},
+	})
+
 	status := MaintenanceStatus{
 		Enabled:   request.Enabled,
 		Message:   request.Message,
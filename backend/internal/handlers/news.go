@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/utils"
 	"github.com/gorilla/mux"
@@ -271,6 +272,13 @@ func CreateNewsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "news.create",
+		TargetType: "news",
+		TargetID:   strconv.FormatInt(newsID, 10),
+		After:      map[string]interface{}{"title": req.Title, "content": req.Content, "character_id": req.CharacterID, "icon": icon},
+	})
+
 	utils.WriteSuccess(w, http.StatusCreated, "News created successfully", map[string]interface{}{
 		"id": int(newsID),
 	})
@@ -366,6 +374,9 @@ func UpdateNewsHandler(w http.ResponseWriter, r *http.Request) {
 		icon = *req.Icon
 	}
 
+	auditColumns := []string{"title", "content", "character_id", "icon"}
+	before := loadAuditSnapshot(ctx, "news", newsID, auditColumns)
+
 	// Update news
 	query := `
 		UPDATE news
@@ -382,6 +393,14 @@ func UpdateNewsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "news.update",
+		TargetType: "news",
+		TargetID:   newsIDStr,
+		Before:     before,
+		After:      updateValues(auditColumns, []interface{}{req.Title, req.Content, req.CharacterID, icon}),
+	})
+
 	utils.WriteSuccess(w, http.StatusOK, "News updated successfully", nil)
 }
 
@@ -398,6 +417,8 @@ func DeleteNewsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
 
+	before := loadAuditSnapshot(ctx, "news", newsID, []string{"title", "content"})
+
 	deleteQuery := "DELETE FROM news WHERE id = ?"
 	result, err := database.DB.ExecContext(ctx, deleteQuery, newsID)
 	if err != nil {
@@ -419,6 +440,13 @@ func DeleteNewsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordAdminAction(r, AuditEntry{
+		Action:     "news.delete",
+		TargetType: "news",
+		TargetID:   newsIDStr,
+		Before:     before,
+	})
+
 	utils.WriteSuccess(w, http.StatusOK, "News deleted successfully", nil)
 }
 
@@ -1035,24 +1063,22 @@ func DeleteNewsCommentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if current user is admin
-	var isAdmin bool
-	err = database.DB.QueryRowContext(ctx,
-		`SELECT COALESCE(page_access, 0) = 1 FROM accounts WHERE id = ?`,
-		userID,
-	).Scan(&isAdmin)
-
+	// Check if current user can moderate comments
+	permissions, err := auth.GetAccountPermissions(ctx, userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Error checking admin status")
 		return
 	}
 
-	// Only allow deletion if user is admin or comment owner
-	if !isAdmin && commentAuthorID != userID {
+	// Only allow deletion if user is a moderator or comment owner
+	if !permissions.Has(auth.PermCommentsModerate) && commentAuthorID != userID {
 		utils.WriteError(w, http.StatusForbidden, "You can only delete your own comments")
 		return
 	}
 
+	var commentContent string
+	_ = database.DB.QueryRowContext(ctx, "SELECT content FROM news_comments WHERE id = ?", commentID).Scan(&commentContent)
+
 	deleteQuery := "DELETE FROM news_comments WHERE id = ?"
 	result, err := database.DB.ExecContext(ctx, deleteQuery, commentID)
 	if err != nil {
@@ -1074,6 +1100,16 @@ func DeleteNewsCommentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Authors deleting their own comments are not moderation
+	if commentAuthorID != userID {
+		recordAdminAction(r, AuditEntry{
+			Action:     "news.comment.delete",
+			TargetType: "news_comment",
+			TargetID:   commentIDStr,
+			Before:     map[string]interface{}{"author_id": commentAuthorID, "content": commentContent},
+		})
+	}
+
 	utils.WriteSuccess(w, http.StatusOK, "Comment deleted successfully", nil)
 }
 
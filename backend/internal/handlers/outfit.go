@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"codexaac-backend/pkg/outfit"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	// OutfitImageMaxAge is how long browsers may keep a rendered outfit, in seconds
+	OutfitImageMaxAge = 86400
+
+	DefaultOutfitCacheMaxMB = 100
+)
+
+var (
+	outfitCache     *outfit.Cache
+	outfitCacheOnce sync.Once
+)
+
+// GetOutfitSpritesPath returns the folder with the outfit sprites (OUTFIT_SPRITES_PATH), "" when not configured
+func GetOutfitSpritesPath() string {
+	return os.Getenv("OUTFIT_SPRITES_PATH")
+}
+
+// GetOutfitCachePath returns the folder rendered outfits are kept in (OUTFIT_CACHE_PATH)
+func GetOutfitCachePath() string {
+	if path := os.Getenv("OUTFIT_CACHE_PATH"); path != "" {
+		return path
+	}
+	return filepath.Join(os.TempDir(), "codexaac-outfits")
+}
+
+// GetOutfitCacheMaxBytes returns how large the outfit cache may grow (OUTFIT_CACHE_MAX_MB)
+func GetOutfitCacheMaxBytes() int64 {
+	megabytes, err := strconv.Atoi(os.Getenv("OUTFIT_CACHE_MAX_MB"))
+	if err != nil || megabytes < 1 {
+		megabytes = DefaultOutfitCacheMaxMB
+	}
+	return int64(megabytes) << 20
+}
+
+// getOutfitCache opens the outfit cache on first use. Returns nil if the folder can't be used,
+// in which case outfits are rendered on every request.
+func getOutfitCache() *outfit.Cache {
+	outfitCacheOnce.Do(func() {
+		cache, err := outfit.NewCache(GetOutfitCachePath(), GetOutfitCacheMaxBytes())
+		if err != nil {
+			log.Printf("Error opening outfit cache %s: %v", GetOutfitCachePath(), err)
+			return
+		}
+		outfitCache = cache
+	})
+	return outfitCache
+}
+
+// GetOutfitImageHandler renders an outfit as PNG from the local sprites, e.g.
+// /api/outfit.png?looktype=128&addons=3&head=78&body=69&legs=58&feet=76
+func GetOutfitImageHandler(w http.ResponseWriter, r *http.Request) {
+	spritesPath := GetOutfitSpritesPath()
+	if spritesPath == "" {
+		utils.WriteError(w, http.StatusServiceUnavailable, "Outfit rendering is not configured")
+		return
+	}
+
+	query := r.URL.Query()
+	lookType, err := strconv.Atoi(query.Get("looktype"))
+	if err != nil || lookType <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid looktype")
+		return
+	}
+
+	queryInt := func(name string) int {
+		value, _ := strconv.Atoi(query.Get(name))
+		return value
+	}
+
+	o := outfit.Outfit{
+		LookType: lookType,
+		Addons:   queryInt("addons"),
+		Head:     queryInt("head"),
+		Body:     queryInt("body"),
+		Legs:     queryInt("legs"),
+		Feet:     queryInt("feet"),
+	}.Normalize()
+
+	cache := getOutfitCache()
+	if cache != nil {
+		if data, ok := cache.Get(o); ok {
+			writeOutfitImage(w, data)
+			return
+		}
+	}
+
+	img, err := outfit.Render(spritesPath, o)
+	if err != nil {
+		if errors.Is(err, outfit.ErrUnknownLookType) {
+			utils.WriteError(w, http.StatusNotFound, "Outfit not found")
+			return
+		}
+		log.Printf("Error rendering outfit %d: %v", o.LookType, err)
+		utils.WriteError(w, http.StatusInternalServerError, "Error rendering outfit")
+		return
+	}
+
+	var data []byte
+	if cache != nil {
+		data, err = cache.Put(o, img)
+		if err != nil && data != nil {
+			// The image is still served, it is just rendered again on the next request
+			log.Printf("Error caching outfit %s: %v", o.CacheName(), err)
+		}
+	} else {
+		data, err = outfit.Encode(img)
+	}
+	if data == nil {
+		log.Printf("Error encoding outfit %s: %v", o.CacheName(), err)
+		utils.WriteError(w, http.StatusInternalServerError, "Error rendering outfit")
+		return
+	}
+
+	writeOutfitImage(w, data)
+}
+
+func writeOutfitImage(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(OutfitImageMaxAge))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -51,6 +51,9 @@ func UpdateRulesHandler(w http.ResponseWriter, r *http.Request) {
     payload.Content = strings.ReplaceAll(payload.Content, "<script", "&lt;script")
     payload.Content = strings.ReplaceAll(payload.Content, "</script>", "&lt;/script&gt;")
 
+    var previousContent string
+    _ = database.DB.QueryRowContext(ctx, "SELECT content FROM site_pages WHERE page_key = ?", "rules").Scan(&previousContent)
+
     _, err := database.DB.ExecContext(ctx,
         "INSERT INTO site_pages (page_key, content) VALUES (?, ?) ON DUPLICATE KEY UPDATE content = VALUES(content)",
         "rules", payload.Content,
@@ -63,5 +66,13 @@ func UpdateRulesHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    recordAdminAction(r, AuditEntry{
+        Action:     "page.update",
+        TargetType: "page",
+        TargetID:   "rules",
+        Before:     map[string]interface{}{"content": previousContent},
+        After:      map[string]interface{}{"content": payload.Content},
+    })
+
     utils.WriteSuccess(w, http.StatusOK, "Page content updated successfully", payload)
 }
@@ -0,0 +1,701 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gorilla/mux"
+)
+
+const (
+	MaxPasskeysPerAccount = 10
+
+	maxPasskeyNameLength = 64
+	passkeyCeremonyTTL   = 5 * time.Minute
+
+	ceremonyRegistration = "register"
+	ceremonyLogin        = "login"
+)
+
+var errCeremonyNotFound = errors.New("webauthn ceremony not found or expired")
+
+var (
+	webAuthn     *webauthn.WebAuthn
+	webAuthnErr  error
+	webAuthnOnce sync.Once
+)
+
+// PasskeyAssertion is a signed passkey login: the ceremony token from the begin endpoint and the
+// browser's PublicKeyCredential serialized as JSON
+type PasskeyAssertion struct {
+	Ceremony   string          `json:"ceremony"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+type BeginPasskeyRegistrationRequest struct {
+	Password string `json:"password"`
+}
+
+// DeletePasskeyRequest confirms the removal like disabling 2FA does; Token is only checked when 2FA is enabled
+type DeletePasskeyRequest struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	Ceremony   string          `json:"ceremony"`
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+type Passkey struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"createdAt"`
+	LastUsedAt *int64 `json:"lastUsedAt"`
+}
+
+// passkeyUser adapts an account to the webauthn.User interface. The user handle is the account ID.
+type passkeyUser struct {
+	id          int
+	email       string
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte                         { return []byte(strconv.Itoa(u.id)) }
+func (u *passkeyUser) WebAuthnName() string                       { return u.email }
+func (u *passkeyUser) WebAuthnDisplayName() string                { return u.email }
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+// getWebAuthn configures the relying party from WEBAUTHN_RP_ORIGINS and WEBAUTHN_RP_ID, both defaulting to SITE_URL
+func getWebAuthn() (*webauthn.WebAuthn, error) {
+	webAuthnOnce.Do(func() {
+		origins := []string{getSiteURL()}
+		if value := os.Getenv("WEBAUTHN_RP_ORIGINS"); value != "" {
+			origins = origins[:0]
+			for _, origin := range strings.Split(value, ",") {
+				if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+					origins = append(origins, origin)
+				}
+			}
+		}
+
+		rpID := os.Getenv("WEBAUTHN_RP_ID")
+		if rpID == "" && len(origins) > 0 {
+			if parsed, err := url.Parse(origins[0]); err == nil {
+				rpID = parsed.Hostname()
+			}
+		}
+
+		displayName := os.Getenv("SERVER_NAME")
+		if displayName == "" {
+			displayName = "CodexAAC"
+		}
+
+		webAuthn, webAuthnErr = webauthn.New(&webauthn.Config{
+			RPID:          rpID,
+			RPDisplayName: displayName,
+			RPOrigins:     origins,
+			AuthenticatorSelection: protocol.AuthenticatorSelection{
+				ResidentKey:      protocol.ResidentKeyRequirementRequired,
+				UserVerification: protocol.VerificationRequired,
+			},
+		})
+		if webAuthnErr != nil {
+			log.Printf("⚠️  Passkeys are disabled, invalid WebAuthn configuration: %v", webAuthnErr)
+		}
+	})
+	return webAuthn, webAuthnErr
+}
+
+// GetPasskeysHandler lists the passkeys registered on the logged in account
+func GetPasskeysHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	rows, err := database.DB.QueryContext(ctx,
+		"SELECT id, name, created_at, last_used_at FROM account_passkeys WHERE account_id = ? ORDER BY created_at",
+		userID,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching passkeys")
+		return
+	}
+	defer rows.Close()
+
+	passkeys := []Passkey{}
+	for rows.Next() {
+		var passkey Passkey
+		var lastUsedAt sql.NullInt64
+		if err := rows.Scan(&passkey.ID, &passkey.Name, &passkey.CreatedAt, &lastUsedAt); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, "Error fetching passkeys")
+			return
+		}
+		if lastUsedAt.Valid {
+			passkey.LastUsedAt = &lastUsedAt.Int64
+		}
+		passkeys = append(passkeys, passkey)
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Passkeys retrieved", passkeys)
+}
+
+// BeginPasskeyRegistrationHandler confirms the password and returns the options for navigator.credentials.create()
+func BeginPasskeyRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	wa, err := getWebAuthn()
+	if err != nil {
+		utils.WriteError(w, http.StatusServiceUnavailable, "Passkeys are not available")
+		return
+	}
+
+	var req BeginPasskeyRegistrationRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Password == "" || len(req.Password) > utils.MaxPasswordLength {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword string
+	if err := database.DB.QueryRowContext(ctx, "SELECT password FROM accounts WHERE id = ?", userID).Scan(&storedPassword); err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	user, err := loadPasskeyUser(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error starting passkey registration")
+		return
+	}
+
+	if len(user.credentials) >= MaxPasskeysPerAccount {
+		utils.WriteError(w, http.StatusBadRequest, "You can register up to "+strconv.Itoa(MaxPasskeysPerAccount)+" passkeys")
+		return
+	}
+
+	creation, session, err := wa.BeginRegistration(user,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+	)
+	if err != nil {
+		log.Printf("Error beginning passkey registration for account %d: %v", userID, err)
+		utils.WriteError(w, http.StatusInternalServerError, "Error starting passkey registration")
+		return
+	}
+
+	ceremony, err := saveCeremony(ctx, userID, ceremonyRegistration, session)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error starting passkey registration")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Passkey registration started", map[string]interface{}{
+		"ceremony": ceremony,
+		"options":  creation,
+	})
+}
+
+// FinishPasskeyRegistrationHandler verifies the new credential and stores it on the account
+func FinishPasskeyRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	wa, err := getWebAuthn()
+	if err != nil {
+		utils.WriteError(w, http.StatusServiceUnavailable, "Passkeys are not available")
+		return
+	}
+
+	var req FinishPasskeyRegistrationRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	req.Name = utils.SanitizeString(req.Name, maxPasskeyNameLength)
+	if req.Name == "" {
+		req.Name = "Passkey"
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	accountID, session, err := takeCeremony(ctx, req.Ceremony, ceremonyRegistration)
+	if err != nil || accountID != userID {
+		if err != nil && !errors.Is(err, errCeremonyNotFound) {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+		}
+		utils.WriteError(w, http.StatusBadRequest, "Passkey registration expired. Please try again")
+		return
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid passkey response")
+		return
+	}
+
+	user, err := loadPasskeyUser(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error registering passkey")
+		return
+	}
+
+	credential, err := wa.CreateCredential(user, *session, parsed)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "The passkey could not be verified")
+		return
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+
+	var exists bool
+	if err := database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) > 0 FROM account_passkeys WHERE credential_id = ?",
+		credentialID,
+	).Scan(&exists); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error registering passkey")
+		return
+	}
+	if exists {
+		utils.WriteError(w, http.StatusConflict, "This passkey is already registered")
+		return
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error registering passkey")
+		return
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		"INSERT INTO account_passkeys (account_id, credential_id, name, credential, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, credentialID, req.Name, string(data), time.Now().Unix(),
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error registering passkey")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, "Passkey added successfully", nil)
+}
+
+// DeletePasskeyHandler removes a passkey from the logged in account
+func DeletePasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	passkeyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || passkeyID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid passkey ID")
+		return
+	}
+
+	var req DeletePasskeyRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Password == "" || len(req.Password) > utils.MaxPasswordLength {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+	if len(req.Token) > maxTwoFactorTokenLength {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid 2FA token format")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword, secret string
+	if err := database.DB.QueryRowContext(ctx, "SELECT password, COALESCE(secret, '') FROM accounts WHERE id = ?", userID).Scan(&storedPassword, &secret); err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	// Checked last so a backup code isn't used up by a request that fails anyway
+	if secret != "" {
+		if req.Token == "" {
+			utils.WriteError(w, http.StatusBadRequest, "2FA token is required")
+			return
+		}
+		if !verifyTwoFactor(ctx, userID, secret, req.Token) {
+			utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
+			return
+		}
+	}
+
+	result, err := database.DB.ExecContext(ctx, "DELETE FROM account_passkeys WHERE id = ? AND account_id = ?", passkeyID, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error removing passkey")
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		utils.WriteError(w, http.StatusNotFound, "Passkey not found")
+		return
+	}
+
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+	revokeAccountSessions(ctx, userID, currentSessionID)
+
+	utils.WriteSuccess(w, http.StatusOK, "Passkey removed successfully", nil)
+}
+
+// BeginPasskeyLoginHandler returns the options for navigator.credentials.get(). The same assertion can be
+// used for a passwordless login or as the second factor of a password login.
+func BeginPasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	wa, err := getWebAuthn()
+	if err != nil {
+		utils.WriteError(w, http.StatusServiceUnavailable, "Passkeys are not available")
+		return
+	}
+
+	assertion, session, err := wa.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		log.Printf("Error beginning passkey login: %v", err)
+		utils.WriteError(w, http.StatusInternalServerError, "Error starting passkey login")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	ceremony, err := saveCeremony(ctx, 0, ceremonyLogin, session)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error starting passkey login")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Passkey login started", map[string]interface{}{
+		"ceremony": ceremony,
+		"options":  assertion,
+	})
+}
+
+// PasskeyLoginHandler signs in with a passkey alone, without the password or a 2FA token
+func PasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req PasskeyAssertion
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	user, err := validatePasskeyAssertion(ctx, &req)
+	if err != nil {
+		if errors.Is(err, errCeremonyNotFound) {
+			utils.WriteError(w, http.StatusBadRequest, "Passkey login expired. Please try again")
+			return
+		}
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid passkey")
+		return
+	}
+
+	if retryAfter := middleware.CheckLoginAllowed(r); retryAfter > 0 {
+		writeLoginLockedOut(w, retryAfter)
+		return
+	}
+
+	middleware.RecordLoginSuccess(user.email)
+
+	jwtToken, err := issueSession(ctx, w, r, user.id)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error generating token")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, LoginResponse{
+		Token:   jwtToken,
+		Message: "Login successful",
+	})
+}
+
+// verifyPasskeySecondFactor checks a passkey assertion made for the given account during a password login
+func verifyPasskeySecondFactor(ctx context.Context, accountID int, assertion *PasskeyAssertion) bool {
+	user, err := validatePasskeyAssertion(ctx, assertion)
+	if err != nil {
+		return false
+	}
+	return user.id == accountID
+}
+
+// validatePasskeyAssertion consumes the login ceremony, verifies the assertion against the stored
+// credential and saves the updated sign counter. It returns the account the passkey belongs to.
+func validatePasskeyAssertion(ctx context.Context, assertion *PasskeyAssertion) (*passkeyUser, error) {
+	wa, err := getWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	_, session, err := takeCeremony(ctx, assertion.Ceremony, ceremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(assertion.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	userHandler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		accountID, err := strconv.Atoi(string(userHandle))
+		if err != nil || accountID <= 0 {
+			return nil, errors.New("invalid user handle")
+		}
+		return loadPasskeyUser(ctx, accountID)
+	}
+
+	found, credential, err := wa.ValidatePasskeyLogin(userHandler, *session, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	user := found.(*passkeyUser)
+	if credential.Authenticator.CloneWarning {
+		log.Printf("⚠️  Rejected passkey login for account %d: the sign counter went backwards (cloned authenticator?)", user.id)
+		return nil, errors.New("passkey sign counter mismatch")
+	}
+
+	data, err := json.Marshal(credential)
+	if err == nil {
+		_, err = database.DB.ExecContext(ctx,
+			"UPDATE account_passkeys SET credential = ?, last_used_at = ? WHERE account_id = ? AND credential_id = ?",
+			string(data), time.Now().Unix(), user.id, base64.RawURLEncoding.EncodeToString(credential.ID),
+		)
+	}
+	if err != nil {
+		log.Printf("Error updating passkey of account %d: %v", user.id, err)
+	}
+
+	return user, nil
+}
+
+func loadPasskeyUser(ctx context.Context, accountID int) (*passkeyUser, error) {
+	user := &passkeyUser{id: accountID}
+	if err := database.DB.QueryRowContext(ctx, "SELECT email FROM accounts WHERE id = ?", accountID).Scan(&user.email); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.QueryContext(ctx, "SELECT credential FROM account_passkeys WHERE account_id = ?", accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var credential webauthn.Credential
+		if err := json.Unmarshal([]byte(data), &credential); err != nil {
+			log.Printf("Skipping unreadable passkey of account %d: %v", accountID, err)
+			continue
+		}
+		user.credentials = append(user.credentials, credential)
+	}
+
+	return user, rows.Err()
+}
+
+func countPasskeys(ctx context.Context, accountID int) (int, error) {
+	var count int
+	err := database.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM account_passkeys WHERE account_id = ?", accountID).Scan(&count)
+	return count, err
+}
+
+// deleteAccountPasskeys removes the passkeys of an account and its pending passkey registrations.
+// Account recovery calls it so a passkey added by whoever held the password can't sign in afterwards.
+func deleteAccountPasskeys(ctx context.Context, tx *sql.Tx, accountID int) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM account_passkeys WHERE account_id = ?", accountID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "DELETE FROM webauthn_ceremonies WHERE account_id = ?", accountID)
+	return err
+}
+
+// saveCeremony stores the WebAuthn session data between the begin and finish requests and returns
+// the token the client sends back. accountID is 0 for logins, where the account isn't known yet.
+func saveCeremony(ctx context.Context, accountID int, kind string, session *webauthn.SessionData) (string, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	var account interface{}
+	if accountID > 0 {
+		account = accountID
+	}
+
+	_, err = database.DB.ExecContext(ctx,
+		"INSERT INTO webauthn_ceremonies (token_hash, account_id, kind, session_data, expires_at) VALUES (?, ?, ?, ?, ?)",
+		utils.HashSHA256(token), account, kind, string(data), time.Now().Add(passkeyCeremonyTTL).Unix(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// takeCeremony loads and deletes a pending ceremony, so each one can only be finished once
+func takeCeremony(ctx context.Context, token, kind string) (int, *webauthn.SessionData, error) {
+	if token == "" || len(token) > 128 {
+		return 0, nil, errCeremonyNotFound
+	}
+	tokenHash := utils.HashSHA256(token)
+
+	var accountID sql.NullInt64
+	var data string
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT account_id, session_data FROM webauthn_ceremonies WHERE token_hash = ? AND kind = ? AND expires_at > ?",
+		tokenHash, kind, time.Now().Unix(),
+	).Scan(&accountID, &data)
+	if err == sql.ErrNoRows {
+		return 0, nil, errCeremonyNotFound
+	} else if err != nil {
+		return 0, nil, err
+	}
+
+	result, err := database.DB.ExecContext(ctx, "DELETE FROM webauthn_ceremonies WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return 0, nil, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		// Another request finished it first
+		return 0, nil, errCeremonyNotFound
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return 0, nil, err
+	}
+
+	return int(accountID.Int64), &session, nil
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+	Token           string `json:"token"` // 2FA token, required when 2FA is enabled
+}
+
+// ChangePasswordHandler changes the password of the logged in account and signs out its other sessions
+func ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.CurrentPassword == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Current password is required")
+		return
+	}
+
+	if len(req.CurrentPassword) > utils.MaxPasswordLength {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword, secret string
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT password, COALESCE(secret, '') FROM accounts WHERE id = ?",
+		userID,
+	).Scan(&storedPassword, &secret)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.CurrentPassword, storedPassword); !valid {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
+		return
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		utils.WriteError(w, http.StatusBadRequest, "The new password must be different from the current one")
+		return
+	}
+
+	if valid, msg := utils.ValidatePassword(req.NewPassword, auth.MaxPasswordLength()); !valid {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Checked last so a backup code isn't used up by a request that fails anyway
+	if secret != "" {
+		if req.Token == "" {
+			utils.WriteError(w, http.StatusBadRequest, "2FA token is required")
+			return
+		}
+		if !verifyTwoFactor(ctx, userID, secret, req.Token) {
+			utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
+			return
+		}
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if _, err := database.DB.ExecContext(ctx, "UPDATE accounts SET password = ? WHERE id = ?", hashedPassword, userID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+	revokeAccountSessions(ctx, userID, currentSessionID)
+
+	utils.WriteSuccess(w, http.StatusOK, "Password changed successfully. Your other sessions have been signed out", nil)
+}
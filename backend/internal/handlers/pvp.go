@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/config"
+	"codexaac-backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	FragPeriodDay   = "day"
+	FragPeriodWeek  = "week"
+	FragPeriodMonth = "month"
+)
+
+// FragCount is the number of unjustified kills in a skull period next to the kills that give a red skull (0 = disabled)
+type FragCount struct {
+	Frags           int `json:"frags"`
+	KillsToRedSkull int `json:"killsToRedSkull"`
+}
+
+type PvPVictim struct {
+	Name  string `json:"name"`
+	Kills int    `json:"kills"`
+}
+
+type CharacterPvPStats struct {
+	Name             string      `json:"name"`
+	Kills            int         `json:"kills"`
+	UnjustifiedKills int         `json:"unjustifiedKills"`
+	Deaths           int         `json:"deaths"`
+	PvPDeaths        int         `json:"pvpDeaths"`
+	ActiveFrags      int         `json:"activeFrags"`
+	FragDuration     int         `json:"fragDuration"`
+	Day              FragCount   `json:"day"`
+	Week             FragCount   `json:"week"`
+	Month            FragCount   `json:"month"`
+	MostKilled       []PvPVictim `json:"mostKilled"`
+}
+
+type TopFragger struct {
+	Rank             int    `json:"rank"`
+	Name             string `json:"name"`
+	Vocation         string `json:"vocation"`
+	Level            int    `json:"level"`
+	Kills            int    `json:"kills"`
+	UnjustifiedKills int    `json:"unjustifiedKills"`
+	LookType         int    `json:"lookType"`
+	LookHead         int    `json:"lookHead"`
+	LookBody         int    `json:"lookBody"`
+	LookLegs         int    `json:"lookLegs"`
+	LookFeet         int    `json:"lookFeet"`
+	LookAddons       int    `json:"lookAddons"`
+}
+
+type TopFraggersResponse struct {
+	Period          string       `json:"period"`
+	Since           int64        `json:"since"`
+	KillsToRedSkull int          `json:"killsToRedSkull"`
+	Players         []TopFragger `json:"players"`
+}
+
+// fragPeriodStart returns when a skull period starts; the server counts frags over the last 24 hours, 7 days and 30 days
+func fragPeriodStart(period string, now time.Time) int64 {
+	switch period {
+	case FragPeriodWeek:
+		return now.AddDate(0, 0, -7).Unix()
+	case FragPeriodMonth:
+		return now.AddDate(0, 0, -30).Unix()
+	default:
+		return now.Add(-24 * time.Hour).Unix()
+	}
+}
+
+// killsToRedSkull returns the red skull threshold of a skull period from config.lua
+func killsToRedSkull(period string) int {
+	serverConfig := config.GetServerConfig()
+	switch period {
+	case FragPeriodWeek:
+		return serverConfig.WeekKillsToRedSkull
+	case FragPeriodMonth:
+		return serverConfig.MonthKillsToRedSkull
+	default:
+		return serverConfig.DayKillsToRedSkull
+	}
+}
+
+// GetCharacterPvPHandler returns the kills, deaths and frags of a character. player_deaths stores killers by
+// name, so kills made under a former name are counted too as long as no other character carries it now.
+func GetCharacterPvPHandler(w http.ResponseWriter, r *http.Request) {
+	characterName := utils.SanitizeString(mux.Vars(r)["name"], 255)
+	if characterName == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Character name is required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var playerID int
+	stats := CharacterPvPStats{MostKilled: []PvPVictim{}}
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT p.id, p.name FROM players p WHERE "+characterByNameCondition+" AND p.deletion = 0 AND "+visiblePlayerCondition,
+		characterName, characterName,
+	).Scan(&playerID, &stats.Name)
+	if err == sql.ErrNoRows {
+		utils.WriteError(w, http.StatusNotFound, "Character not found")
+		return
+	}
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching character")
+		return
+	}
+
+	if err := loadCharacterPvPStats(ctx, playerID, &stats); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching PvP statistics")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "PvP statistics retrieved successfully", stats)
+}
+
+func loadCharacterPvPStats(ctx context.Context, playerID int, stats *CharacterPvPStats) error {
+	names, err := loadKillerNames(ctx, playerID, stats.Name)
+	if err != nil {
+		return err
+	}
+
+	// A death counts as a kill for the player who killed and for the one who did the most damage
+	placeholders := placeholderList(len(names))
+	killedBy := "(pd.is_player = 1 AND pd.killed_by IN (" + placeholders + "))"
+	mostDamageBy := "(pd.mostdamage_is_player = 1 AND pd.mostdamage_by IN (" + placeholders + "))"
+	unjustified := "((pd.unjustified = 1 AND " + killedBy + ") OR (pd.mostdamage_unjustified = 1 AND " + mostDamageBy + "))"
+	killCondition := "(" + killedBy + " OR " + mostDamageBy + ")"
+
+	now := time.Now()
+	stats.FragDuration = config.GetServerConfig().FragDuration
+	fragsSince := now.Unix()
+	if stats.FragDuration > 0 {
+		fragsSince = now.Add(-time.Duration(stats.FragDuration) * time.Hour).Unix()
+	}
+
+	args := make([]interface{}, 0, len(names)*12+4)
+	for _, since := range []int64{
+		fragPeriodStart(FragPeriodDay, now),
+		fragPeriodStart(FragPeriodWeek, now),
+		fragPeriodStart(FragPeriodMonth, now),
+		fragsSince,
+	} {
+		args = append(args, since)
+		args = appendNames(args, names, 2)
+	}
+	args = appendNames(args, names, 4)
+
+	err = database.DB.QueryRowContext(ctx,
+		`SELECT
+			COUNT(*),
+			COALESCE(SUM(pd.time >= ? AND `+unjustified+`), 0),
+			COALESCE(SUM(pd.time >= ? AND `+unjustified+`), 0),
+			COALESCE(SUM(pd.time >= ? AND `+unjustified+`), 0),
+			COALESCE(SUM(pd.time >= ? AND `+unjustified+`), 0),
+			COALESCE(SUM(`+unjustified+`), 0)
+		 FROM player_deaths pd
+		 WHERE `+killCondition,
+		args...,
+	).Scan(&stats.Kills, &stats.Day.Frags, &stats.Week.Frags, &stats.Month.Frags, &stats.ActiveFrags, &stats.UnjustifiedKills)
+	if err != nil {
+		return err
+	}
+
+	stats.Day.KillsToRedSkull = killsToRedSkull(FragPeriodDay)
+	stats.Week.KillsToRedSkull = killsToRedSkull(FragPeriodWeek)
+	stats.Month.KillsToRedSkull = killsToRedSkull(FragPeriodMonth)
+
+	err = database.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(is_player = 1 OR mostdamage_is_player = 1), 0)
+		 FROM player_deaths WHERE player_id = ?`,
+		playerID,
+	).Scan(&stats.Deaths, &stats.PvPDeaths)
+	if err != nil {
+		return err
+	}
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT p.name, COUNT(*) as kills
+		 FROM player_deaths pd
+		 INNER JOIN players p ON p.id = pd.player_id
+		 WHERE `+killCondition+` AND p.deletion = 0 AND `+visiblePlayerCondition+`
+		 GROUP BY p.id, p.name
+		 ORDER BY kills DESC, p.name ASC
+		 LIMIT 5`,
+		appendNames(nil, names, 2)...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var victim PvPVictim
+		if err := rows.Scan(&victim.Name, &victim.Kills); err != nil {
+			continue
+		}
+		stats.MostKilled = append(stats.MostKilled, victim)
+	}
+
+	return rows.Err()
+}
+
+// loadKillerNames returns the current name of a character and the former names nobody else carries now
+func loadKillerNames(ctx context.Context, playerID int, name string) ([]string, error) {
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT DISTINCT pfn.name FROM player_former_names pfn
+		 WHERE pfn.player_id = ? AND NOT EXISTS (SELECT 1 FROM players o WHERE o.name = pfn.name)`,
+		playerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{name}
+	for rows.Next() {
+		var formerName string
+		if err := rows.Scan(&formerName); err != nil {
+			continue
+		}
+		names = append(names, formerName)
+	}
+
+	return names, rows.Err()
+}
+
+// appendNames appends the killer names once for each of the given number of IN lists
+func appendNames(args []interface{}, names []string, times int) []interface{} {
+	for i := 0; i < times; i++ {
+		for _, name := range names {
+			args = append(args, name)
+		}
+	}
+	return args
+}
+
+// fragsByKillerQuery counts player kills per killer name since a time, which it binds twice.
+// Killers are only known by the name they had then; GetTopFraggersHandler maps them to characters
+// the way loadKillerNames does.
+const fragsByKillerQuery = `SELECT k.killer, COUNT(*) as kills, SUM(k.unjustified) as unjustified
+				FROM (
+					SELECT pd.killed_by as killer, pd.unjustified
+					FROM player_deaths pd
+					WHERE pd.is_player = 1 AND pd.time >= ?
+					UNION ALL
+					SELECT pd.mostdamage_by, pd.mostdamage_unjustified
+					FROM player_deaths pd
+					WHERE pd.mostdamage_is_player = 1 AND pd.mostdamage_by != pd.killed_by AND pd.time >= ?
+				) k
+				GROUP BY k.killer`
+
+// GetTopFraggersHandler ranks the characters with the most player kills over the last day, week or month,
+// the same periods the server uses for skulls. Kills made under a former name count for the character that had it.
+func GetTopFraggersHandler(w http.ResponseWriter, r *http.Request) {
+	period := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("period")))
+	if period != FragPeriodWeek && period != FragPeriodMonth {
+		period = FragPeriodDay
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	since := fragPeriodStart(period, time.Now())
+
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT
+			p.name,
+			p.level,
+			p.vocation,
+			f.kills,
+			f.unjustified,
+			COALESCE(NULLIF(p.looktype, 0), 128) as looktype,
+			COALESCE(p.lookhead, 0) as lookhead,
+			COALESCE(p.lookbody, 0) as lookbody,
+			COALESCE(p.looklegs, 0) as looklegs,
+			COALESCE(p.lookfeet, 0) as lookfeet,
+			COALESCE(p.lookaddons, 0) as lookaddons
+		 FROM (
+			SELECT c.player_id, SUM(c.kills) as kills, SUM(c.unjustified) as unjustified
+			FROM (
+				SELECT p.id as player_id, f.killer, f.kills, f.unjustified
+				FROM (`+fragsByKillerQuery+`) f
+				INNER JOIN players p ON p.name = f.killer
+				UNION
+				SELECT pfn.player_id, f.killer, f.kills, f.unjustified
+				FROM (`+fragsByKillerQuery+`) f
+				INNER JOIN player_former_names pfn ON pfn.name = f.killer
+				WHERE NOT EXISTS (SELECT 1 FROM players o WHERE o.name = pfn.name)
+			) c
+			GROUP BY c.player_id
+		 ) f
+		 INNER JOIN players p ON p.id = f.player_id
+		 WHERE p.deletion = 0 AND p.group_id < 4 AND `+visiblePlayerCondition+`
+		 ORDER BY f.kills DESC, f.unjustified DESC, p.level DESC, p.name ASC
+		 LIMIT ?`,
+		since, since, since, since, limit,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching top fraggers")
+		return
+	}
+	defer rows.Close()
+
+	players := make([]TopFragger, 0, limit)
+	for rows.Next() {
+		var player TopFragger
+		var vocationID int
+
+		if err := rows.Scan(
+			&player.Name,
+			&player.Level,
+			&vocationID,
+			&player.Kills,
+			&player.UnjustifiedKills,
+			&player.LookType,
+			&player.LookHead,
+			&player.LookBody,
+			&player.LookLegs,
+			&player.LookFeet,
+			&player.LookAddons,
+		); err != nil {
+			continue
+		}
+
+		player.Rank = len(players) + 1
+		player.Vocation = config.GetVocationName(vocationID)
+		players = append(players, player)
+	}
+
+	if err = rows.Err(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing top fraggers")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Top fraggers retrieved successfully", TopFraggersResponse{
+		Period:          period,
+		Since:           since,
+		KillsToRedSkull: killsToRedSkull(period),
+		Players:         players,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"codexaac-backend/internal/database"
 	"codexaac-backend/pkg/config"
@@ -76,6 +77,9 @@ func GetRankingHandler(w http.ResponseWriter, r *http.Request) {
 
 	var valueField string
 	var orderBy string
+	// Rankings on data outside the players table join it in; see the cases below
+	var extraJoin string
+	var joinArgs []interface{}
 
 	switch rankingType {
 	case "level":
@@ -108,6 +112,27 @@ func GetRankingHandler(w http.ResponseWriter, r *http.Request) {
 	case "fishing":
 		valueField = "p.skill_fishing"
 		orderBy = "p.skill_fishing DESC, p.level DESC"
+	case "achievements":
+		extraJoin, joinArgs = achievementPointsJoin()
+		if extraJoin == "" {
+			utils.WriteError(w, http.StatusNotFound, "Achievements are not configured")
+			return
+		}
+		valueField = "ap.points"
+		orderBy = "ap.points DESC, p.level DESC"
+	case "gainedtoday", "gainedweek":
+		// Compares against the last snapshot of the experience history job before the period
+		periodStart := time.Now()
+		if rankingType == "gainedweek" {
+			periodStart = periodStart.AddDate(0, 0, -6)
+		}
+		valueField = "p.experience - eh.experience"
+		orderBy = "p.experience - eh.experience DESC, p.level DESC"
+		extraJoin = `
+		INNER JOIN player_experience_history eh ON eh.player_id = p.id AND eh.day = (
+			SELECT MAX(h.day) FROM player_experience_history h WHERE h.player_id = p.id AND h.day < ?
+		) AND p.experience > eh.experience`
+		joinArgs = []interface{}{periodStart.Format(HistoryDayFormat)}
 	default:
 		valueField = "p.level"
 		orderBy = "p.level DESC"
@@ -125,11 +150,12 @@ func GetRankingHandler(w http.ResponseWriter, r *http.Request) {
 			COALESCE(p.lookfeet, 0) as lookfeet,
 			COALESCE(p.lookaddons, 0) as lookaddons,
 			` + valueField + ` as value
-		FROM players p
-		WHERE p.deletion = 0 AND p.group_id < 4
+		FROM players p` + extraJoin + `
+		WHERE p.deletion = 0 AND p.group_id < 4 AND ` + visiblePlayerCondition + `
 	`
 
-	args := make([]interface{}, 0, 4)
+	args := make([]interface{}, 0, 5)
+	args = append(args, joinArgs...)
 
 	if vocation != "" && vocation != "all" {
 		vocationID := config.GetVocationID(vocation)
@@ -186,10 +212,11 @@ func GetRankingHandler(w http.ResponseWriter, r *http.Request) {
 
 	countQuery := `
 		SELECT COUNT(*)
-		FROM players p
-		WHERE p.deletion = 0 AND p.group_id < 4
+		FROM players p` + extraJoin + `
+		WHERE p.deletion = 0 AND p.group_id < 4 AND ` + visiblePlayerCondition + `
 	`
-	countArgs := make([]interface{}, 0, 2)
+	countArgs := make([]interface{}, 0, 3)
+	countArgs = append(countArgs, joinArgs...)
 
 	if vocation != "" && vocation != "all" {
 		vocationID := config.GetVocationID(vocation)
@@ -0,0 +1,524 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/mail"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+type RecoveryRequest struct {
+	Email         string `json:"email"`
+	CharacterName string `json:"characterName"`
+	Username      string `json:"username"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type RecoveryKeyResetRequest struct {
+	Email       string `json:"email"`
+	RecoveryKey string `json:"recoveryKey"`
+	Password    string `json:"password"`
+}
+
+type GenerateRecoveryKeyRequest struct {
+	Password string `json:"password"`
+}
+
+const (
+	RecoveryPurposePasswordReset   = "password_reset"
+	DefaultRecoveryTokenTTLMinutes = 60
+
+	// recoveryRequestCooldown is how long an account waits between two reset emails
+	recoveryRequestCooldown = 5 * time.Minute
+
+	recoveryKeyAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	recoveryKeyGroups   = 4
+	recoveryKeyGroupLen = 5
+)
+
+// recoveryRequestedMessage is returned whether or not an account matched, so the endpoint can't be used to probe emails
+const recoveryRequestedMessage = "If the information matches an account, recovery instructions have been sent to the email address"
+
+func getRecoveryTokenTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("RECOVERY_TOKEN_TTL_MINUTES"))
+	if err != nil || minutes < 1 {
+		minutes = DefaultRecoveryTokenTTLMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func getSiteURL() string {
+	siteURL := os.Getenv("SITE_URL")
+	if siteURL == "" {
+		siteURL = "http://localhost:3000"
+	}
+	return strings.TrimRight(siteURL, "/")
+}
+
+// RequestRecoveryHandler emails a single-use password reset link to the account owner
+func RequestRecoveryHandler(w http.ResponseWriter, r *http.Request) {
+	var req RecoveryRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Email = utils.SanitizeString(req.Email, 255)
+	if !utils.IsValidEmail(req.Email) {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid email")
+		return
+	}
+
+	req.CharacterName = strings.TrimSpace(req.CharacterName)
+	req.Username = strings.TrimSpace(req.Username)
+	if len(req.CharacterName) > 255 || len(req.Username) > 255 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var accountID int
+	var err error
+	switch {
+	case req.CharacterName != "":
+		err = database.DB.QueryRowContext(ctx,
+			`SELECT a.id FROM accounts a
+			 INNER JOIN players p ON p.account_id = a.id
+			 WHERE a.email = ? AND p.name = ?
+			 LIMIT 1`,
+			req.Email, req.CharacterName,
+		).Scan(&accountID)
+	case req.Username != "":
+		err = database.DB.QueryRowContext(ctx,
+			"SELECT id FROM accounts WHERE email = ? AND name = ?",
+			req.Email, req.Username,
+		).Scan(&accountID)
+	default:
+		err = database.DB.QueryRowContext(ctx,
+			"SELECT id FROM accounts WHERE email = ?",
+			req.Email,
+		).Scan(&accountID)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteSuccess(w, http.StatusOK, recoveryRequestedMessage, nil)
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing recovery request")
+		return
+	}
+
+	// Answer as usual during the cooldown so repeated requests can't be used to flood the owner's inbox
+	var recentTokens int
+	err = database.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM account_recovery_tokens WHERE account_id = ? AND purpose = ? AND created_at > NOW() - INTERVAL ? SECOND",
+		accountID, RecoveryPurposePasswordReset, int(recoveryRequestCooldown.Seconds()),
+	).Scan(&recentTokens)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing recovery request")
+		return
+	}
+	if recentTokens > 0 {
+		utils.WriteSuccess(w, http.StatusOK, recoveryRequestedMessage, nil)
+		return
+	}
+
+	token, err := createRecoveryToken(ctx, accountID, RecoveryPurposePasswordReset, utils.GetClientIP(r))
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error processing recovery request")
+		return
+	}
+
+	ttl := getRecoveryTokenTTL()
+	msg := mail.Message{
+		To:      req.Email,
+		Subject: "Account recovery",
+		Body: fmt.Sprintf(
+			"A password reset was requested for your account.\n\n"+
+				"Open the link below to choose a new password:\n%s/account/recover/reset?token=%s\n\n"+
+				"The link expires in %d minutes and can only be used once.\n"+
+				"If you did not request this, you can ignore this email.",
+			getSiteURL(), token, int(ttl.Minutes()),
+		),
+	}
+
+	// Send in the background so response time doesn't reveal whether the account exists
+	go func() {
+		if err := mail.Send(msg); err != nil {
+			log.Printf("Error sending recovery email for account %d: %v", accountID, err)
+		}
+	}()
+
+	utils.WriteSuccess(w, http.StatusOK, recoveryRequestedMessage, nil)
+}
+
+// ResetPasswordHandler sets a new password using an emailed recovery token
+func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid or expired recovery token")
+		return
+	}
+
+	if valid, msg := utils.ValidatePassword(req.Password, auth.MaxPasswordLength()); !valid {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+	defer tx.Rollback()
+
+	accountID, err := consumeRecoveryToken(ctx, tx, req.Token, RecoveryPurposePasswordReset)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid or expired recovery token")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET password = ? WHERE id = ?", hashedPassword, accountID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	// Any other outstanding reset links for this account stop working once one has been used
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE account_recovery_tokens SET used_at = ? WHERE account_id = ? AND purpose = ? AND used_at IS NULL",
+		time.Now().Unix(), accountID, RecoveryPurposePasswordReset,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if err := deleteAccountPasskeys(ctx, tx, accountID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	revokeAccountSessions(ctx, accountID, "")
+
+	utils.WriteSuccess(w, http.StatusOK, "Password updated successfully. You can now log in with your new password", nil)
+}
+
+// RecoveryKeyResetHandler sets a new password using the account recovery key
+func RecoveryKeyResetHandler(w http.ResponseWriter, r *http.Request) {
+	var req RecoveryKeyResetRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Email = utils.SanitizeString(req.Email, 255)
+	if !utils.IsValidEmail(req.Email) {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid email")
+		return
+	}
+
+	recoveryKey := normalizeRecoveryKey(req.RecoveryKey)
+	if recoveryKey == "" || len(recoveryKey) > 64 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid email or recovery key")
+		return
+	}
+
+	if valid, msg := utils.ValidatePassword(req.Password, auth.MaxPasswordLength()); !valid {
+		utils.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var accountID int
+	err = database.DB.QueryRowContext(ctx,
+		"SELECT id FROM accounts WHERE email = ? AND recovery_key = ?",
+		req.Email, utils.HashSHA256(recoveryKey),
+	).Scan(&accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid email or recovery key")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET password = ? WHERE id = ?", hashedPassword, accountID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if err := deleteAccountPasskeys(ctx, tx, accountID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating password")
+		return
+	}
+
+	revokeAccountSessions(ctx, accountID, "")
+
+	utils.WriteSuccess(w, http.StatusOK, "Password updated successfully. You can now log in with your new password", nil)
+}
+
+// GenerateRecoveryKeyHandler creates a new recovery key for the logged in account.
+// The key is only shown once; any previous key stops working.
+func GenerateRecoveryKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req GenerateRecoveryKeyRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	if req.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	if len(req.Password) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Password too long")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var storedPassword string
+	err := database.DB.QueryRowContext(ctx, "SELECT password FROM accounts WHERE id = ?", userID).Scan(&storedPassword)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying account")
+		return
+	}
+
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid password")
+		return
+	}
+
+	recoveryKey, err := generateRecoveryKey()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error generating recovery key")
+		return
+	}
+
+	_, err = database.DB.ExecContext(ctx,
+		"UPDATE accounts SET recovery_key = ? WHERE id = ?",
+		utils.HashSHA256(normalizeRecoveryKey(recoveryKey)), userID,
+	)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error saving recovery key")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Recovery key generated. Store it in a safe place, it will not be shown again", map[string]string{
+		"recoveryKey": recoveryKey,
+	})
+}
+
+// createRecoveryToken stores the hash of a new random token and returns the plain token
+func createRecoveryToken(ctx context.Context, accountID int, purpose, requestIP string) (string, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = database.DB.ExecContext(ctx,
+		"INSERT INTO account_recovery_tokens (account_id, purpose, token_hash, expires_at, request_ip) VALUES (?, ?, ?, ?, ?)",
+		accountID, purpose, utils.HashSHA256(token), time.Now().Add(getRecoveryTokenTTL()).Unix(), requestIP,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeRecoveryToken marks a valid, unexpired token as used and returns its account.
+// Returns sql.ErrNoRows when the token is unknown, expired or already used.
+func consumeRecoveryToken(ctx context.Context, tx *sql.Tx, token, purpose string) (int, error) {
+	var tokenID, accountID int
+	err := tx.QueryRowContext(ctx,
+		`SELECT id, account_id FROM account_recovery_tokens
+		 WHERE token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?
+		 FOR UPDATE`,
+		utils.HashSHA256(token), purpose, time.Now().Unix(),
+	).Scan(&tokenID, &accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE account_recovery_tokens SET used_at = ? WHERE id = ?", time.Now().Unix(), tokenID); err != nil {
+		return 0, err
+	}
+
+	return accountID, nil
+}
+
+// generateRecoveryKey returns a key like ABCDE-FGH23-JKLMN-PQRS4 (100 bits of entropy)
+func generateRecoveryKey() (string, error) {
+	return generateGroupedCode(recoveryKeyGroups)
+}
+
+// generateGroupedCode returns groups of recoveryKeyGroupLen random characters joined by dashes
+func generateGroupedCode(groupCount int) (string, error) {
+	groups := make([]string, groupCount)
+	max := big.NewInt(int64(len(recoveryKeyAlphabet)))
+
+	for i := range groups {
+		group := make([]byte, recoveryKeyGroupLen)
+		for j := range group {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			group[j] = recoveryKeyAlphabet[n.Int64()]
+		}
+		groups[i] = string(group)
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+func normalizeRecoveryKey(key string) string {
+	key = strings.ToUpper(key)
+	key = strings.ReplaceAll(key, "-", "")
+	key = strings.ReplaceAll(key, " ", "")
+	return key
+}
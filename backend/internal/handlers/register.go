@@ -3,7 +3,6 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"os"
 	"time"
 
 	"codexaac-backend/internal/database"
@@ -45,7 +44,7 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if valid, msg := utils.ValidatePassword(req.Password); !valid {
+	if valid, msg := utils.ValidatePassword(req.Password, auth.MaxPasswordLength()); !valid {
 		utils.WriteError(w, http.StatusBadRequest, msg)
 		return
 	}
@@ -68,16 +67,26 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hashedPassword := utils.HashSHA1(req.Password)
-	
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Error creating account")
+		return
+	}
+
 	accountName := req.Email
+
+	status := AccountStatusActive
+	if EmailVerificationRequired() {
+		status = AccountStatusUnverified
+	}
+	creation := time.Now()
 	
 	query := `
-		INSERT INTO accounts (name, password, email, creation, premdays, type) 
-		VALUES (?, ?, ?, ?, 0, 1)
+		INSERT INTO accounts (name, password, email, creation, premdays, type, status) 
+		VALUES (?, ?, ?, ?, 0, 1, ?)
 	`
 	
-	result, err := database.DB.ExecContext(ctx, query, accountName, hashedPassword, req.Email, time.Now().Unix())
+	result, err := database.DB.ExecContext(ctx, query, accountName, hashedPassword, req.Email, creation.Unix(), status)
 	if err != nil {
 		if utils.HandleDBError(w, err) {
 			return
@@ -92,17 +101,20 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jwtToken, err := auth.GenerateToken(int(accountID))
+	message := "Account created successfully"
+	if status == AccountStatusUnverified {
+		sendVerificationEmail(int(accountID), req.Email, creation)
+		message = "Account created. Check your email and open the link to activate it"
+	}
+
+	jwtToken, err := issueSession(ctx, w, r, int(accountID))
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Error generating token")
 		return
 	}
 
-	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
-	utils.SetAuthCookie(w, jwtToken, isSecure)
-
 	utils.WriteJSON(w, http.StatusCreated, RegisterResponse{
 		Token:   jwtToken,
-		Message: "Account created successfully",
+		Message: message,
 	})
 }
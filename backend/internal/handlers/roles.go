@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+type AccountRolesResponse struct {
+	AccountID int      `json:"accountId"`
+	Roles     []string `json:"roles"`
+}
+
+type UpdateAccountRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// GetRolesHandler lists the staff roles and the permissions they grant
+func GetRolesHandler(w http.ResponseWriter, r *http.Request) {
+	utils.WriteSuccess(w, http.StatusOK, "Roles retrieved successfully", auth.Roles)
+}
+
+// GetAccountRolesHandler returns the roles assigned to an account
+func GetAccountRolesHandler(w http.ResponseWriter, r *http.Request) {
+	accountID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || accountID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	roles, err := auth.GetAccountRoles(ctx, accountID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusNotFound, "Account not found")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Account roles retrieved successfully", AccountRolesResponse{
+		AccountID: accountID,
+		Roles:     roles,
+	})
+}
+
+// UpdateAccountRolesHandler replaces the roles assigned to an account
+func UpdateAccountRolesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	accountID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || accountID <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	if accountID == userID {
+		utils.WriteError(w, http.StatusBadRequest, "You cannot change your own roles")
+		return
+	}
+
+	var req UpdateAccountRolesRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		}
+		return
+	}
+
+	roles := []string{}
+	seen := map[string]bool{}
+	for _, role := range req.Roles {
+		if _, ok := auth.GetRole(role); !ok {
+			utils.WriteError(w, http.StatusBadRequest, "Unknown role: "+utils.SanitizeString(role, 32))
+			return
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var exists bool
+	err = database.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE id = ?)", accountID).Scan(&exists)
+	if err != nil || !exists {
+		utils.WriteError(w, http.StatusNotFound, "Account not found")
+		return
+	}
+
+	before, _ := auth.GetAccountRoles(ctx, accountID)
+
+	if err := auth.SetAccountRoles(ctx, accountID, roles, userID); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error updating roles")
+		return
+	}
+
+	recordAdminAction(r, AuditEntry{
+		Action:     "account.roles.update",
+		TargetType: "account",
+		TargetID:   strconv.Itoa(accountID),
+		Before:     map[string]interface{}{"roles": before},
+		After:      map[string]interface{}{"roles": roles},
+	})
+
+	utils.WriteSuccess(w, http.StatusOK, "Account roles updated successfully", AccountRolesResponse{
+		AccountID: accountID,
+		Roles:     roles,
+	})
+}
@@ -1,15 +1,33 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 
 	"codexaac-backend/pkg/config"
 	"codexaac-backend/pkg/utils"
 )
 
+// ServerInfoResponse is the public server configuration with the online record kept by the online poller
+type ServerInfoResponse struct {
+	config.PublicServerConfig
+	OnlineRecord *OnlineRecord `json:"onlineRecord"`
+}
+
 func GetServerConfigHandler(w http.ResponseWriter, r *http.Request) {
-	publicConfig := config.GetPublicServerConfig()
-	utils.WriteSuccess(w, http.StatusOK, "Server configuration retrieved successfully", publicConfig)
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	response := ServerInfoResponse{PublicServerConfig: config.GetPublicServerConfig()}
+
+	// The configuration is still useful without the record, so a failure here is only logged
+	record, err := loadOnlineRecord(ctx)
+	if err != nil {
+		log.Printf("Error fetching online record: %v", err)
+	}
+	response.OnlineRecord = record
+
+	utils.WriteSuccess(w, http.StatusOK, "Server configuration retrieved successfully", response)
 }
 
 func GetStagesConfigHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+type RefreshResponse struct {
+	Token   string `json:"token,omitempty"` // Access token (for development - localStorage)
+	Message string `json:"message"`
+}
+
+// issueSession starts a web session and sets the access and refresh cookies. Returns the access token.
+func issueSession(ctx context.Context, w http.ResponseWriter, r *http.Request, accountID int) (string, error) {
+	tokens, err := auth.CreateSession(ctx, accountID, utils.GetClientIP(r), r.UserAgent())
+	if err != nil {
+		return "", err
+	}
+
+	setSessionCookies(w, r, tokens)
+	return tokens.AccessToken, nil
+}
+
+func setSessionCookies(w http.ResponseWriter, r *http.Request, tokens *auth.TokenPair) {
+	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
+	utils.SetAuthCookie(w, tokens.AccessToken, isSecure)
+	utils.SetRefreshCookie(w, tokens.RefreshToken, int(time.Until(tokens.RefreshExpiresAt).Seconds()), isSecure)
+}
+
+// revokeAccountSessions signs an account out everywhere after a credential change.
+// keepSessionID lets the session that made the change stay logged in; game client sessions are always dropped.
+func revokeAccountSessions(ctx context.Context, accountID int, keepSessionID string) {
+	if _, err := auth.RevokeAllSessions(ctx, accountID, keepSessionID); err != nil {
+		log.Printf("Error revoking web sessions for account %d: %v", accountID, err)
+	}
+
+	if _, err := database.DB.ExecContext(ctx, "DELETE FROM account_sessions WHERE account_id = ?", accountID); err != nil {
+		log.Printf("Error revoking client sessions for account %d: %v", accountID, err)
+	}
+}
+
+// RefreshTokenHandler rotates the refresh token cookie and issues a new access token
+func RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
+
+	refreshToken, err := utils.GetRefreshCookie(r)
+	if err != nil || refreshToken == "" || len(refreshToken) > 128 {
+		utils.WriteError(w, http.StatusUnauthorized, "Invalid or expired session")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tokens, err := auth.RefreshSession(ctx, refreshToken, utils.GetClientIP(r), r.UserAgent())
+	if err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			utils.ClearAuthCookie(w, isSecure)
+			utils.ClearRefreshCookie(w, isSecure)
+			utils.WriteError(w, http.StatusUnauthorized, "Invalid or expired session")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error refreshing session")
+		return
+	}
+
+	setSessionCookies(w, r, tokens)
+
+	utils.WriteJSON(w, http.StatusOK, RefreshResponse{
+		Token:   tokens.AccessToken,
+		Message: "Session refreshed",
+	})
+}
+
+// GetSessionsHandler lists the active web sessions of the logged in account
+func GetSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	sessions, err := auth.ListSessions(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error fetching sessions")
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSessionHandler revokes one web session of the logged in account
+func RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+
+	sessionID := mux.Vars(r)["id"]
+	if sessionID == "" || len(sessionID) > 64 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	revoked, err := auth.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error revoking session")
+		return
+	}
+
+	if !revoked {
+		utils.WriteError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if sessionID == currentSessionID {
+		isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
+		utils.ClearAuthCookie(w, isSecure)
+		utils.ClearRefreshCookie(w, isSecure)
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeAllSessionsHandler revokes every web session of the logged in account, including the current one
+func RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	revokedCount, err := auth.RevokeAllSessions(ctx, userID, "")
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error revoking sessions")
+		return
+	}
+
+	isSecure := r.TLS != nil || os.Getenv("ENV") == "production"
+	utils.ClearAuthCookie(w, isSecure)
+	utils.ClearRefreshCookie(w, isSecure)
+
+	utils.WriteSuccess(w, http.StatusOK, "All sessions revoked successfully", map[string]int64{
+		"revoked": revokedCount,
+	})
+}
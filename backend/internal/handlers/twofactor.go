@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/middleware"
 	"codexaac-backend/pkg/twofactor"
 	"codexaac-backend/pkg/utils"
@@ -20,6 +21,7 @@ type Enable2FAResponse struct {
 	Secret    string `json:"secret"`     // The secret key (for manual entry)
 	QRCode    string `json:"qrCode"`     // Base64 encoded QR code image
 	OTPAuthURL string `json:"otpauthUrl"` // otpauth:// URL for manual entry
+	BackupCodes []string `json:"backupCodes"` // Single-use codes accepted instead of a token, shown once
 	Message   string `json:"message"`
 }
 
@@ -29,7 +31,7 @@ type Verify2FARequest struct {
 
 type Disable2FARequest struct {
 	Password string `json:"password"` // Password confirmation required
-	Token    string `json:"token"`    // 2FA token confirmation required
+	Token    string `json:"token"`    // 2FA token or backup code confirmation required
 }
 
 func Enable2FAHandler(w http.ResponseWriter, r *http.Request) {
@@ -74,8 +76,7 @@ func Enable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hashedPassword := utils.HashSHA1(req.Password)
-	if storedPassword != hashedPassword {
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
 		utils.WriteError(w, http.StatusUnauthorized, "Invalid password")
 		return
 	}
@@ -123,11 +124,24 @@ func Enable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	backupCodes, err := generateBackupCodes(ctx, userID)
+	if err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Failed to generate backup codes")
+		return
+	}
+
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+	revokeAccountSessions(ctx, userID, currentSessionID)
+
 	utils.WriteJSON(w, http.StatusOK, Enable2FAResponse{
-		Secret:     secret,
-		QRCode:     qrCodeBase64,
-		OTPAuthURL: otpauthURL,
-		Message:    "Scan the QR code with your authenticator app, then verify with a token to complete setup.",
+		Secret:      secret,
+		QRCode:      qrCodeBase64,
+		OTPAuthURL:  otpauthURL,
+		BackupCodes: backupCodes,
+		Message:     "Scan the QR code with your authenticator app, then verify with a token to complete setup. Store the backup codes in a safe place.",
 	})
 }
 
@@ -208,7 +222,7 @@ func Disable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusBadRequest, "Password too long")
 		return
 	}
-	if len(req.Token) > 6 {
+	if len(req.Token) > maxTwoFactorTokenLength {
 		utils.WriteError(w, http.StatusBadRequest, "Invalid 2FA token format")
 		return
 	}
@@ -231,8 +245,7 @@ func Disable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hashedPassword := utils.HashSHA1(req.Password)
-	if storedPassword != hashedPassword {
+	if valid, _ := auth.VerifyPassword(req.Password, storedPassword); !valid {
 		utils.WriteError(w, http.StatusUnauthorized, "Invalid password")
 		return
 	}
@@ -242,7 +255,7 @@ func Disable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !twofactor.ValidateToken(secret.String, req.Token) {
+	if !verifyTwoFactor(ctx, userID, secret.String, req.Token) {
 		utils.WriteError(w, http.StatusUnauthorized, "Invalid 2FA token")
 		return
 	}
@@ -256,6 +269,11 @@ func Disable2FAHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	deleteBackupCodes(ctx, userID)
+
+	currentSessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+	revokeAccountSessions(ctx, userID, currentSessionID)
+
 	utils.WriteSuccess(w, http.StatusOK, "Two-factor authentication has been successfully disabled.", nil)
 }
 
@@ -284,8 +302,22 @@ func Get2FAStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	enabled := secret.Valid && secret.String != ""
+
+	backupCodesRemaining := 0
+	if enabled {
+		backupCodesRemaining, err = countBackupCodes(ctx, userID)
+		if err != nil {
+			if utils.HandleDBError(w, err) {
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+	}
+
 	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"enabled": enabled,
+		"enabled":              enabled,
+		"backupCodesRemaining": backupCodesRemaining,
 	})
 }
 
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/mail"
+	"codexaac-backend/pkg/middleware"
+	"codexaac-backend/pkg/utils"
+)
+
+const DefaultEmailVerificationTTLHours = 48
+
+type VerifyEmailRequest struct {
+	ID        int    `json:"id"`
+	Expires   int64  `json:"expires"`
+	Signature string `json:"signature"`
+}
+
+// EmailVerificationRequired reports whether new accounts must verify their email (EMAIL_VERIFICATION_REQUIRED=true).
+// Turning it off lifts the restrictions on accounts that are still unverified.
+func EmailVerificationRequired() bool {
+	return os.Getenv("EMAIL_VERIFICATION_REQUIRED") == "true"
+}
+
+// GetEmailVerificationTTL returns how long a new account has to verify its email before it is purged
+func GetEmailVerificationTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TTL_HOURS"))
+	if err != nil || hours < 1 {
+		hours = DefaultEmailVerificationTTLHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// isAccountUnverified reports whether an account is blocked until its email is verified
+func isAccountUnverified(status string) bool {
+	return status == AccountStatusUnverified && EmailVerificationRequired()
+}
+
+// sendVerificationEmail emails a signed activation link that stays valid until the account would be purged
+func sendVerificationEmail(accountID int, email string, creation time.Time) {
+	expiresAt := creation.Add(GetEmailVerificationTTL()).Unix()
+	link := fmt.Sprintf("%s/account/verify?id=%d&expires=%d&signature=%s",
+		getSiteURL(), accountID, expiresAt, url.QueryEscape(auth.SignEmailVerification(accountID, email, expiresAt)))
+
+	msg := mail.Message{
+		To:      email,
+		Subject: "Activate your account",
+		Body: fmt.Sprintf(
+			"Welcome! Open the link below to verify your email address and activate your account:\n%s\n\n"+
+				"Until then you can't create characters or log in to the game. "+
+				"Accounts that are not activated by %s are deleted.\n"+
+				"If you did not create an account, you can ignore this email.",
+			link, time.Unix(expiresAt, 0).UTC().Format("Jan 2, 2006, 15:04 MST"),
+		),
+	}
+
+	go func() {
+		if err := mail.Send(msg); err != nil {
+			log.Printf("Error sending verification email for account %d: %v", accountID, err)
+		}
+	}()
+}
+
+// VerifyEmailHandler activates an account from the signed link emailed at registration
+func VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := utils.DecodeJSON(r, &req); err != nil {
+		if errors.Is(err, utils.ErrBodyTooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else if errors.Is(err, utils.ErrInvalidContentType) {
+			utils.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		} else {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid data")
+		}
+		return
+	}
+
+	req.Signature = strings.TrimSpace(req.Signature)
+	if req.ID <= 0 || req.Signature == "" || len(req.Signature) > 128 {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid verification link")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var email, status string
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT email, COALESCE(status, 'active') FROM accounts WHERE id = ?",
+		req.ID,
+	).Scan(&email, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusBadRequest, "Invalid verification link")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying email")
+		return
+	}
+
+	if !auth.VerifyEmailVerification(req.ID, email, req.Expires, req.Signature) {
+		utils.WriteError(w, http.StatusBadRequest, "Invalid verification link")
+		return
+	}
+
+	if status != AccountStatusUnverified {
+		utils.WriteSuccess(w, http.StatusOK, "Your email address is already verified", nil)
+		return
+	}
+
+	if req.Expires <= time.Now().Unix() {
+		utils.WriteError(w, http.StatusBadRequest, "This verification link has expired. Log in to request a new one")
+		return
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		"UPDATE accounts SET status = ? WHERE id = ? AND status = ?",
+		AccountStatusActive, req.ID, AccountStatusUnverified,
+	); err != nil {
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error verifying email")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, "Your email address has been verified and your account is now active", nil)
+}
+
+// ResendVerificationHandler emails a new activation link to the logged in account
+func ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var email, status string
+	var creation int64
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT email, COALESCE(status, 'active'), creation FROM accounts WHERE id = ?",
+		userID,
+	).Scan(&email, &status, &creation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		if utils.HandleDBError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "Error sending verification email")
+		return
+	}
+
+	if status != AccountStatusUnverified {
+		utils.WriteError(w, http.StatusBadRequest, "Your email address is already verified")
+		return
+	}
+
+	sendVerificationEmail(userID, email, time.Unix(creation, 0))
+
+	utils.WriteSuccess(w, http.StatusOK, "A new verification link has been sent to "+email, nil)
+}
@@ -10,6 +10,42 @@ import (
 	"codexaac-backend/pkg/utils"
 )
 
+// accountTables hold rows that belong to an account and are useless once it is gone
+var accountTables = []string{
+	"account_web_sessions",
+	"account_sessions",
+	"account_recovery_tokens",
+	"account_backup_codes",
+	"account_passkeys",
+	"account_email_changes",
+	"account_roles",
+	"webauthn_ceremonies",
+}
+
+// CleanupOrphanedAccountRows removes the sessions, tokens, passkeys and other rows of accounts that were deleted
+func CleanupOrphanedAccountRows() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	var totalDeletedCount int64
+	for _, table := range accountTables {
+		result, err := database.DB.ExecContext(ctx,
+			"DELETE t FROM "+table+" t LEFT JOIN accounts a ON a.id = t.account_id WHERE t.account_id IS NOT NULL AND a.id IS NULL",
+		)
+		if err != nil {
+			return err
+		}
+		deletedCount, _ := result.RowsAffected()
+		totalDeletedCount += deletedCount
+	}
+
+	if totalDeletedCount > 0 {
+		log.Printf("✅ Removed %d rows of deleted accounts", totalDeletedCount)
+	}
+
+	return nil
+}
+
 func CleanupDeletedAccounts() error {
 	ctx, cancel := utils.NewDBContext()
 	defer cancel()
@@ -127,13 +163,13 @@ func CleanupDeletedAccounts() error {
 		}
 	}
 
-	if totalDeletedCount > 0 {
-		log.Printf("✅ Cleaned up %d deleted accounts total", totalDeletedCount)
-	} else {
+	if totalDeletedCount == 0 {
 		log.Printf("ℹ️  No accounts to clean up")
+		return nil
 	}
 
-	return nil
+	log.Printf("✅ Cleaned up %d deleted accounts total", totalDeletedCount)
+	return CleanupOrphanedAccountRows()
 }
 
 func RunCleanupJob() {
@@ -0,0 +1,183 @@
+package jobs
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/internal/handlers"
+	"codexaac-backend/pkg/utils"
+)
+
+// SettleFinishedAuctions closes the bazaar auctions that have ended. A sold character moves to the
+// highest bidder's account and the held coins go to the seller in the same transaction.
+// Auctions whose character is online are left for a later run.
+func SettleFinishedAuctions() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	rows, err := database.DB.QueryContext(ctx,
+		"SELECT id FROM character_auctions WHERE status = ? AND ends_at <= ? ORDER BY ends_at LIMIT 500",
+		handlers.AuctionStatusActive, time.Now().Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	var auctionIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("Error scanning auction to settle: %v", err)
+			continue
+		}
+		auctionIDs = append(auctionIDs, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(auctionIDs) == 0 {
+		log.Printf("ℹ️  No auctions to settle")
+		return nil
+	}
+
+	settled := make(map[string]int)
+	for _, id := range auctionIDs {
+		status, err := settleAuction(id)
+		if err != nil {
+			log.Printf("❌ Error settling auction %d: %v", id, err)
+			continue
+		}
+		if status != "" {
+			settled[status]++
+		}
+	}
+
+	log.Printf("✅ Settled auctions: %d sold, %d expired, %d failed",
+		settled[handlers.AuctionStatusSold], settled[handlers.AuctionStatusExpired], settled[handlers.AuctionStatusFailed])
+
+	return nil
+}
+
+// settleAuction closes one finished auction and returns its new status, or "" if it has to wait
+func settleAuction(auctionID int) (string, error) {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var playerID, sellerID, currentBid, bidderID int
+	err = tx.QueryRowContext(ctx,
+		`SELECT player_id, seller_account_id, current_bid, COALESCE(highest_bidder_account_id, 0)
+		 FROM character_auctions WHERE id = ? AND status = ? FOR UPDATE`,
+		auctionID, handlers.AuctionStatusActive,
+	).Scan(&playerID, &sellerID, &currentBid, &bidderID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	status, event := handlers.AuctionStatusSold, handlers.AuctionEventSold
+	if bidderID == 0 {
+		status, event = handlers.AuctionStatusExpired, handlers.AuctionEventExpired
+	} else {
+		var online bool
+		if err := tx.QueryRowContext(ctx,
+			"SELECT EXISTS(SELECT 1 FROM players_online WHERE player_id = ?)", playerID,
+		).Scan(&online); err != nil {
+			return "", err
+		}
+		if online {
+			log.Printf("⚠️  Auction %d is waiting for its character to log out", auctionID)
+			return "", nil
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE players SET account_id = ?
+			 WHERE id = ? AND account_id = ? AND deletion = 0
+			 AND EXISTS (SELECT 1 FROM accounts WHERE id = ?)
+			 AND NOT EXISTS (SELECT 1 FROM guilds WHERE ownerid = players.id)`,
+			bidderID, playerID, sellerID, bidderID,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		if moved, _ := result.RowsAffected(); moved == 1 {
+			if _, err := tx.ExecContext(ctx, "UPDATE accounts SET coins = coins + ? WHERE id = ?", currentBid, sellerID); err != nil {
+				return "", err
+			}
+			// The hidden flag and the profile were the seller's choice
+			if _, err := tx.ExecContext(ctx, "DELETE FROM player_site_settings WHERE player_id = ?", playerID); err != nil {
+				return "", err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM player_profiles WHERE player_id = ?", playerID); err != nil {
+				return "", err
+			}
+			// The guild rank and invites were the seller's too, so the buyer gets the character guildless
+			if _, err := tx.ExecContext(ctx, "DELETE FROM guild_membership WHERE player_id = ?", playerID); err != nil {
+				return "", err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM guild_invites WHERE player_id = ?", playerID); err != nil {
+				return "", err
+			}
+		} else {
+			// The character or the bidder's account is gone, or the character leads a guild by now; the held coins go back to the bidder
+			status, event = handlers.AuctionStatusFailed, handlers.AuctionEventFailed
+			if _, err := tx.ExecContext(ctx, "UPDATE accounts SET coins = coins + ? WHERE id = ?", currentBid, bidderID); err != nil {
+				return "", err
+			}
+			if err := handlers.RecordAuctionEvent(ctx, tx, handlers.AuctionEvent{
+				AuctionID:  auctionID,
+				AccountID:  bidderID,
+				Event:      handlers.AuctionEventRefund,
+				FromStatus: handlers.AuctionStatusActive,
+				ToStatus:   handlers.AuctionStatusActive,
+				Amount:     currentBid,
+			}); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE character_auctions SET status = ?, settled_at = ? WHERE id = ?",
+		status, time.Now().Unix(), auctionID,
+	); err != nil {
+		return "", err
+	}
+
+	if err := handlers.RecordAuctionEvent(ctx, tx, handlers.AuctionEvent{
+		AuctionID:  auctionID,
+		Event:      event,
+		FromStatus: handlers.AuctionStatusActive,
+		ToStatus:   status,
+		Amount:     currentBid,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+func RunBazaarJob() {
+	log.Println("🧹 Starting bazaar settlement job...")
+	if err := SettleFinishedAuctions(); err != nil {
+		log.Printf("❌ Error settling auctions: %v", err)
+	}
+	log.Println("✅ Bazaar settlement job completed")
+}
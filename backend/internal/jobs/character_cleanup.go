@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+)
+
+// DeleteScheduledCharacters deletes characters whose deletion grace period is over. Like the game server,
+// a deleted character keeps its row with players.deletion set, so it disappears from the client and the site.
+// Characters that are online or lead a guild are left for a later run.
+func DeleteScheduledCharacters() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx,
+		`UPDATE players p
+		 INNER JOIN player_site_settings pss ON pss.player_id = p.id
+		 SET p.deletion = pss.deletion_scheduled_at
+		 WHERE pss.deletion_scheduled_at IS NOT NULL AND pss.deletion_scheduled_at <= ?
+		 AND p.deletion = 0
+		 AND NOT EXISTS (SELECT 1 FROM players_online po WHERE po.player_id = p.id)
+		 AND NOT EXISTS (SELECT 1 FROM guilds g WHERE g.ownerid = p.id)`,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	deletedCount, _ := result.RowsAffected()
+	if deletedCount > 0 {
+		log.Printf("✅ Deleted %d characters scheduled for deletion", deletedCount)
+	} else {
+		log.Printf("ℹ️  No characters to delete")
+	}
+
+	// Deleted characters leave their guild and pending invites
+	if _, err := database.DB.ExecContext(ctx,
+		"DELETE gm FROM guild_membership gm INNER JOIN players p ON p.id = gm.player_id WHERE p.deletion <> 0",
+	); err != nil {
+		return err
+	}
+	if _, err := database.DB.ExecContext(ctx,
+		"DELETE gi FROM guild_invites gi INNER JOIN players p ON p.id = gi.player_id WHERE p.deletion <> 0",
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CleanupPlayerSiteSettings drops the settings and profiles of characters that no longer exist
+func CleanupPlayerSiteSettings() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx,
+		`DELETE pss FROM player_site_settings pss
+		 LEFT JOIN players p ON p.id = pss.player_id AND p.deletion = 0
+		 WHERE p.id IS NULL OR (pss.hidden = 0 AND pss.deletion_scheduled_at IS NULL)`,
+	)
+	if err != nil {
+		return err
+	}
+
+	if removedCount, _ := result.RowsAffected(); removedCount > 0 {
+		log.Printf("✅ Removed %d unused character settings", removedCount)
+	}
+
+	result, err = database.DB.ExecContext(ctx,
+		`DELETE pp FROM player_profiles pp
+		 LEFT JOIN players p ON p.id = pp.player_id AND p.deletion = 0
+		 WHERE p.id IS NULL`,
+	)
+	if err != nil {
+		return err
+	}
+
+	if removedCount, _ := result.RowsAffected(); removedCount > 0 {
+		log.Printf("✅ Removed %d profiles of deleted characters", removedCount)
+	}
+
+	return nil
+}
+
+func RunCharacterCleanupJob() {
+	log.Println("🧹 Starting character cleanup job...")
+	if err := DeleteScheduledCharacters(); err != nil {
+		log.Printf("❌ Error deleting scheduled characters: %v", err)
+	}
+	if err := CleanupPlayerSiteSettings(); err != nil {
+		log.Printf("❌ Error cleaning up character settings: %v", err)
+	}
+	log.Println("✅ Character cleanup job completed")
+}
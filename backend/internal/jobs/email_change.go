@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"log"
+
+	"codexaac-backend/internal/handlers"
+)
+
+// RunEmailChangeJob applies the email changes whose cancellation window has passed
+func RunEmailChangeJob() {
+	log.Println("📧 Starting email change job...")
+	applied, err := handlers.ApplyDueEmailChanges()
+	if err != nil {
+		log.Printf("❌ Error applying email changes: %v", err)
+		return
+	}
+	if applied > 0 {
+		log.Printf("✅ Applied %d email changes", applied)
+	} else {
+		log.Printf("ℹ️  No email changes to apply")
+	}
+}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/internal/handlers"
+	"codexaac-backend/pkg/utils"
+)
+
+// SnapshotExperience records today's level and experience of every character. A row is only written when
+// the experience differs from the character's latest snapshot, and running it again on the same day
+// updates today's row, so the job can run as often as the cleanup job does.
+func SnapshotExperience() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	today := time.Now().Format(handlers.HistoryDayFormat)
+
+	result, err := database.DB.ExecContext(ctx,
+		`INSERT INTO player_experience_history (player_id, day, level, experience)
+		 SELECT p.id, ?, p.level, p.experience
+		 FROM players p
+		 LEFT JOIN (
+			SELECT h.player_id, h.experience
+			FROM player_experience_history h
+			INNER JOIN (
+				SELECT player_id, MAX(day) as day FROM player_experience_history GROUP BY player_id
+			) latest ON latest.player_id = h.player_id AND latest.day = h.day
+		 ) prev ON prev.player_id = p.id
+		 WHERE p.deletion = 0 AND (prev.player_id IS NULL OR prev.experience <> p.experience)
+		 ON DUPLICATE KEY UPDATE level = VALUES(level), experience = VALUES(experience)`,
+		today,
+	)
+	if err != nil {
+		return err
+	}
+
+	if changed, _ := result.RowsAffected(); changed > 0 {
+		log.Printf("✅ Recorded experience of %d characters", changed)
+	} else {
+		log.Printf("ℹ️  No experience changes to record")
+	}
+
+	return nil
+}
+
+// PruneExperienceHistory removes snapshots older than the retention period, keeping the latest snapshot
+// of each character as the baseline for the experience gained rankings, and the history of deleted characters
+func PruneExperienceHistory() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	cutoff := time.Now().AddDate(0, 0, -handlers.GetExperienceHistoryRetentionDays()).Format(handlers.HistoryDayFormat)
+
+	result, err := database.DB.ExecContext(ctx,
+		`DELETE h FROM player_experience_history h
+		 INNER JOIN (
+			SELECT player_id, MAX(day) as day FROM player_experience_history GROUP BY player_id
+		 ) latest ON latest.player_id = h.player_id
+		 WHERE h.day < ? AND h.day < latest.day`,
+		cutoff,
+	)
+	if err != nil {
+		return err
+	}
+
+	if removedCount, _ := result.RowsAffected(); removedCount > 0 {
+		log.Printf("✅ Removed %d old experience snapshots", removedCount)
+	}
+
+	result, err = database.DB.ExecContext(ctx,
+		`DELETE h FROM player_experience_history h
+		 LEFT JOIN players p ON p.id = h.player_id AND p.deletion = 0
+		 WHERE p.id IS NULL`,
+	)
+	if err != nil {
+		return err
+	}
+
+	if removedCount, _ := result.RowsAffected(); removedCount > 0 {
+		log.Printf("✅ Removed %d experience snapshots of deleted characters", removedCount)
+	}
+
+	return nil
+}
+
+func RunExperienceHistoryJob() {
+	log.Println("🧹 Starting experience history job...")
+	if err := SnapshotExperience(); err != nil {
+		log.Printf("❌ Error recording experience: %v", err)
+	}
+	if err := PruneExperienceHistory(); err != nil {
+		log.Printf("❌ Error pruning experience history: %v", err)
+	}
+	log.Println("✅ Experience history job completed")
+}
@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/internal/handlers"
+	"codexaac-backend/pkg/utils"
+)
+
+type onlineDayKey struct {
+	playerID int
+	day      string
+}
+
+// SampleOnlinePlayers compares players_online with the open sessions. Characters still online get the time since
+// the previous sample added to their session and daily total, new ones open a session and the ones that left
+// are logged out at the time they were last seen. A gap longer than a few intervals (the poller or the database
+// was down) closes the session instead of counting the gap as online time.
+func SampleOnlinePlayers(interval time.Duration) error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	now := time.Now().Unix()
+	maxGap := int64((3 * interval).Seconds())
+
+	rows, err := database.DB.QueryContext(ctx, "SELECT player_id FROM players_online")
+	if err != nil {
+		return err
+	}
+
+	online := make(map[int]bool)
+	for rows.Next() {
+		var playerID int
+		if err := rows.Scan(&playerID); err != nil {
+			continue
+		}
+		online[playerID] = true
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	type openSession struct {
+		id         int64
+		playerID   int
+		lastSeenAt int64
+	}
+
+	sessionRows, err := tx.QueryContext(ctx,
+		"SELECT id, player_id, last_seen_at FROM player_online_sessions WHERE logout_at IS NULL FOR UPDATE",
+	)
+	if err != nil {
+		return err
+	}
+
+	var sessions []openSession
+	for sessionRows.Next() {
+		var session openSession
+		if err := sessionRows.Scan(&session.id, &session.playerID, &session.lastSeenAt); err != nil {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	sessionRows.Close()
+
+	if err = sessionRows.Err(); err != nil {
+		return err
+	}
+
+	continued := make(map[int]bool)
+	daily := make(map[onlineDayKey]int64)
+
+	for _, session := range sessions {
+		if online[session.playerID] && !continued[session.playerID] && now-session.lastSeenAt <= maxGap {
+			if _, err := tx.ExecContext(ctx,
+				"UPDATE player_online_sessions SET last_seen_at = ? WHERE id = ?", now, session.id,
+			); err != nil {
+				return err
+			}
+			addOnlineTime(daily, session.playerID, session.lastSeenAt, now)
+			continued[session.playerID] = true
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE player_online_sessions SET logout_at = last_seen_at WHERE id = ?", session.id,
+		); err != nil {
+			return err
+		}
+	}
+
+	for playerID := range online {
+		if continued[playerID] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO player_online_sessions (player_id, login_at, last_seen_at) VALUES (?, ?, ?)",
+			playerID, now, now,
+		); err != nil {
+			return err
+		}
+	}
+
+	for key, seconds := range daily {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO player_online_daily (player_id, day, seconds) VALUES (?, ?, ?)
+			 ON DUPLICATE KEY UPDATE seconds = seconds + VALUES(seconds)`,
+			key.playerID, key.day, seconds,
+		); err != nil {
+			return err
+		}
+	}
+
+	// recorded_at is assigned first so it still compares against the old peak
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO server_online_peaks (day, players, recorded_at) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE
+			recorded_at = IF(VALUES(players) > players, VALUES(recorded_at), recorded_at),
+			players = GREATEST(players, VALUES(players))`,
+		time.Unix(now, 0).Format(handlers.HistoryDayFormat), len(online), now,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addOnlineTime adds the time between two samples to the days it falls on
+func addOnlineTime(daily map[onlineDayKey]int64, playerID int, from, to int64) {
+	for from < to {
+		start := time.Unix(from, 0)
+		nextDay := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location()).Unix()
+
+		end := to
+		if nextDay < end {
+			end = nextDay
+		}
+
+		daily[onlineDayKey{playerID: playerID, day: start.Format(handlers.HistoryDayFormat)}] += end - from
+		from = end
+	}
+}
+
+// PruneOnlineHistory removes sessions and daily online time older than the retention period and those of deleted characters
+func PruneOnlineHistory() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	cutoff := time.Now().AddDate(0, 0, -handlers.GetOnlineHistoryRetentionDays())
+
+	result, err := database.DB.ExecContext(ctx,
+		"DELETE FROM player_online_sessions WHERE logout_at IS NOT NULL AND logout_at < ?",
+		cutoff.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if removedCount, _ := result.RowsAffected(); removedCount > 0 {
+		log.Printf("✅ Removed %d old online sessions", removedCount)
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		"DELETE FROM player_online_daily WHERE day < ?",
+		cutoff.Format(handlers.HistoryDayFormat),
+	); err != nil {
+		return err
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		`DELETE s FROM player_online_sessions s
+		 LEFT JOIN players p ON p.id = s.player_id AND p.deletion = 0
+		 WHERE p.id IS NULL`,
+	); err != nil {
+		return err
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		`DELETE d FROM player_online_daily d
+		 LEFT JOIN players p ON p.id = d.player_id AND p.deletion = 0
+		 WHERE p.id IS NULL`,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunOnlinePoller samples players_online every interval until the process exits. It runs inside the API server
+// and must only be enabled on one instance, otherwise online time is counted twice.
+func RunOnlinePoller(interval time.Duration) {
+	log.Printf("👥 Online poller started (every %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := SampleOnlinePlayers(interval); err != nil {
+			log.Printf("❌ Error sampling online players: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+func RunOnlineHistoryJob() {
+	log.Println("🧹 Starting online history cleanup job...")
+	if err := PruneOnlineHistory(); err != nil {
+		log.Printf("❌ Error pruning online history: %v", err)
+	}
+	log.Println("✅ Online history cleanup job completed")
+}
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+)
+
+func CleanupExpiredClientSessions() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx, "DELETE FROM account_sessions WHERE expires <= ?", time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	deletedCount, _ := result.RowsAffected()
+	if deletedCount > 0 {
+		log.Printf("✅ Removed %d expired client sessions", deletedCount)
+	} else {
+		log.Printf("ℹ️  No expired client sessions to remove")
+	}
+
+	return nil
+}
+
+// CleanupExpiredWebSessions removes expired website sessions and revoked ones older than a day
+func CleanupExpiredWebSessions() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	now := time.Now()
+	result, err := database.DB.ExecContext(ctx,
+		"DELETE FROM account_web_sessions WHERE expires_at <= ? OR (revoked_at IS NOT NULL AND revoked_at <= ?)",
+		now.Unix(), now.Add(-24*time.Hour).Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	deletedCount, _ := result.RowsAffected()
+	if deletedCount > 0 {
+		log.Printf("✅ Removed %d expired web sessions", deletedCount)
+	} else {
+		log.Printf("ℹ️  No expired web sessions to remove")
+	}
+
+	return nil
+}
+
+// CleanupExpiredWebAuthnCeremonies removes passkey registrations and logins that were never finished
+func CleanupExpiredWebAuthnCeremonies() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx, "DELETE FROM webauthn_ceremonies WHERE expires_at <= ?", time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	deletedCount, _ := result.RowsAffected()
+	if deletedCount > 0 {
+		log.Printf("✅ Removed %d expired passkey ceremonies", deletedCount)
+	} else {
+		log.Printf("ℹ️  No expired passkey ceremonies to remove")
+	}
+
+	return nil
+}
+
+// CleanupExpiredRecoveryTokens removes password reset tokens that have expired, used or not
+func CleanupExpiredRecoveryTokens() error {
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	result, err := database.DB.ExecContext(ctx, "DELETE FROM account_recovery_tokens WHERE expires_at <= ?", time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	deletedCount, _ := result.RowsAffected()
+	if deletedCount > 0 {
+		log.Printf("✅ Removed %d expired recovery tokens", deletedCount)
+	} else {
+		log.Printf("ℹ️  No expired recovery tokens to remove")
+	}
+
+	return nil
+}
+
+func RunSessionCleanupJob() {
+	log.Println("🧹 Starting session cleanup job...")
+	if err := CleanupExpiredClientSessions(); err != nil {
+		log.Printf("❌ Error cleaning up client sessions: %v", err)
+	}
+	if err := CleanupExpiredWebSessions(); err != nil {
+		log.Printf("❌ Error cleaning up web sessions: %v", err)
+	}
+	if err := CleanupExpiredWebAuthnCeremonies(); err != nil {
+		log.Printf("❌ Error cleaning up passkey ceremonies: %v", err)
+	}
+	if err := CleanupExpiredRecoveryTokens(); err != nil {
+		log.Printf("❌ Error cleaning up recovery tokens: %v", err)
+	}
+	log.Println("✅ Session cleanup job completed")
+}
@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/internal/handlers"
+	"codexaac-backend/pkg/utils"
+)
+
+// PurgeUnverifiedAccounts deletes accounts that did not verify their email within EMAIL_VERIFICATION_TTL_HOURS.
+// Unverified accounts can't create characters; their sessions and tokens are removed afterwards.
+func PurgeUnverifiedAccounts() error {
+	if !handlers.EmailVerificationRequired() {
+		log.Printf("ℹ️  Email verification is disabled, skipping unverified accounts")
+		return nil
+	}
+
+	ctx, cancel := utils.NewDBContext()
+	defer cancel()
+
+	cutoff := time.Now().Add(-handlers.GetEmailVerificationTTL()).Unix()
+
+	const deleteBatchSize = 500
+	var totalDeletedCount int64
+	for {
+		result, err := database.DB.ExecContext(ctx,
+			`DELETE FROM accounts
+			 WHERE status = ? AND creation <= ?
+			 AND NOT EXISTS (SELECT 1 FROM players WHERE players.account_id = accounts.id)
+			 LIMIT ?`,
+			handlers.AccountStatusUnverified, cutoff, deleteBatchSize,
+		)
+		if err != nil {
+			return err
+		}
+
+		deletedCount, _ := result.RowsAffected()
+		totalDeletedCount += deletedCount
+		if deletedCount < deleteBatchSize {
+			break
+		}
+	}
+
+	if totalDeletedCount == 0 {
+		log.Printf("ℹ️  No unverified accounts to purge")
+		return nil
+	}
+
+	log.Printf("✅ Purged %d unverified accounts", totalDeletedCount)
+	return CleanupOrphanedAccountRows()
+}
+
+func RunUnverifiedCleanupJob() {
+	log.Println("🧹 Starting unverified account cleanup job...")
+	if err := PurgeUnverifiedAccounts(); err != nil {
+		log.Printf("❌ Error purging unverified accounts: %v", err)
+	} else {
+		log.Println("✅ Unverified account cleanup job completed")
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
@@ -25,16 +26,29 @@ func getJWTKey() []byte {
 	return jwtKey
 }
 
+const DefaultAccessTokenTTLMinutes = 15
+
+// Claims carries the session ID in the standard jti claim (RegisteredClaims.ID)
 type Claims struct {
 	UserID int `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+func getAccessTokenTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("JWT_ACCESS_TOKEN_TTL_MINUTES"))
+	if err != nil || minutes < 1 {
+		minutes = DefaultAccessTokenTTLMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GenerateToken issues a short-lived access token bound to a web session
+func GenerateToken(userID int, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(getAccessTokenTTL())
 	claims := &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
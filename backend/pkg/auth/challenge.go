@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SignChallenge signs the payload of a proof-of-work challenge so the server doesn't have to store issued challenges
+func SignChallenge(payload string) string {
+	mac := hmac.New(sha256.New, getJWTKey())
+	mac.Write([]byte("challenge:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyChallenge checks a signature created by SignChallenge
+func VerifyChallenge(payload, signature string) bool {
+	expected := SignChallenge(payload)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
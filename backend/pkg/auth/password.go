@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"codexaac-backend/pkg/utils"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HashModeSHA1     = "sha1"
+	HashModeBcrypt   = "bcrypt"
+	HashModeArgon2id = "argon2id"
+
+	// bcryptMaxPasswordLength is the longest password bcrypt accepts, in bytes
+	bcryptMaxPasswordLength = 72
+)
+
+// PasswordHasher hashes and verifies account passwords for a single algorithm
+type PasswordHasher interface {
+	Name() string
+	Hash(password string) (string, error)
+	Verify(password, hash string) bool
+	// Matches reports whether the stored hash was produced by this hasher
+	Matches(hash string) bool
+	// Outdated reports whether a hash of this algorithm was made with other cost parameters than the current ones
+	Outdated(hash string) bool
+	// MaxLength is the longest password, in bytes, this hasher can hash
+	MaxLength() int
+}
+
+var (
+	hashers = map[string]PasswordHasher{
+		HashModeSHA1:     sha1Hasher{},
+		HashModeBcrypt:   bcryptHasher{cost: bcrypt.DefaultCost},
+		HashModeArgon2id: argon2idHasher{time: 3, memory: 64 * 1024, threads: 2, keyLen: 32, saltLen: 16},
+	}
+
+	// hasherStrength ranks the algorithms so a login only ever moves a password to a stronger one
+	hasherStrength = map[string]int{
+		HashModeSHA1:     0,
+		HashModeBcrypt:   1,
+		HashModeArgon2id: 2,
+	}
+
+	activeHasher     PasswordHasher
+	activeHasherOnce sync.Once
+)
+
+// GetPasswordHasher returns the hasher selected by PASSWORD_HASH_MODE.
+// SHA-1 is the default so game servers that verify passwords themselves keep working.
+func GetPasswordHasher() PasswordHasher {
+	activeHasherOnce.Do(func() {
+		mode := strings.ToLower(strings.TrimSpace(os.Getenv("PASSWORD_HASH_MODE")))
+		hasher, ok := hashers[mode]
+		if !ok {
+			hasher = hashers[HashModeSHA1]
+		}
+		activeHasher = hasher
+	})
+	return activeHasher
+}
+
+// MaxPasswordLength returns the longest password the configured hasher accepts.
+// Handlers pass it to utils.ValidatePassword when a new password is set.
+func MaxPasswordLength() int {
+	return GetPasswordHasher().MaxLength()
+}
+
+// HashPassword hashes a password with the configured hasher
+func HashPassword(password string) (string, error) {
+	return GetPasswordHasher().Hash(password)
+}
+
+// VerifyPassword checks a password against any supported stored hash.
+// needsRehash is true when the password is valid but was stored with a weaker algorithm than the configured one,
+// or with the configured one and other cost parameters. Switching to a weaker algorithm never downgrades stored hashes.
+func VerifyPassword(password, storedHash string) (valid bool, needsRehash bool) {
+	active := GetPasswordHasher()
+
+	for _, hasher := range []PasswordHasher{active, hashers[HashModeBcrypt], hashers[HashModeArgon2id], hashers[HashModeSHA1]} {
+		if !hasher.Matches(storedHash) {
+			continue
+		}
+		if !hasher.Verify(password, storedHash) {
+			return false, false
+		}
+		// bcrypt cannot hash longer passwords, so those keep their current hash
+		if len(password) > active.MaxLength() {
+			return true, false
+		}
+		if hasher.Name() == active.Name() {
+			return true, hasher.Outdated(storedHash)
+		}
+		return true, hasherStrength[active.Name()] > hasherStrength[hasher.Name()]
+	}
+
+	return false, false
+}
+
+type sha1Hasher struct{}
+
+func (sha1Hasher) Name() string { return HashModeSHA1 }
+
+func (sha1Hasher) Hash(password string) (string, error) {
+	return utils.HashSHA1(password), nil
+}
+
+func (h sha1Hasher) Verify(password, hash string) bool {
+	expected, _ := h.Hash(password)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(hash))) == 1
+}
+
+func (sha1Hasher) Matches(hash string) bool {
+	if len(hash) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func (sha1Hasher) Outdated(hash string) bool { return false }
+
+func (sha1Hasher) MaxLength() int { return utils.MaxPasswordLength }
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (bcryptHasher) Name() string { return HashModeBcrypt }
+
+func (h bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func (bcryptHasher) Verify(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (bcryptHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h bcryptHasher) Outdated(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost != h.cost
+}
+
+func (bcryptHasher) MaxLength() int { return bcryptMaxPasswordLength }
+
+type argon2idHasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	keyLen  uint32
+	saltLen int
+}
+
+func (argon2idHasher) Name() string { return HashModeArgon2id }
+
+// Hash encodes the result in the PHC string format: $argon2id$v=19$m=...,t=...,p=...$salt$key
+func (h argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, h.keyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.memory, h.time, h.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (argon2idHasher) Verify(password, hash string) bool {
+	params, salt, expected, ok := parseArgon2idHash(hash)
+	if !ok {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(expected)))
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+func (argon2idHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (h argon2idHasher) Outdated(hash string) bool {
+	params, salt, key, ok := parseArgon2idHash(hash)
+	if !ok {
+		return false
+	}
+	return params.time != h.time || params.memory != h.memory || params.threads != h.threads ||
+		uint32(len(key)) != h.keyLen || len(salt) != h.saltLen
+}
+
+func (argon2idHasher) MaxLength() int { return utils.MaxPasswordLength }
+
+// parseArgon2idHash splits a PHC string into its cost parameters, salt and key
+func parseArgon2idHash(hash string) (params argon2idHasher, salt, key []byte, ok bool) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, false
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, false
+	}
+
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, false
+	}
+
+	return params, salt, key, true
+}
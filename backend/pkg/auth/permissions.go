@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"codexaac-backend/internal/database"
+)
+
+// Permission is checked by the admin routes declared in cmd/server/main.go
+type Permission string
+
+const (
+	PermDashboardView    Permission = "dashboard.view"
+	PermAccountsView     Permission = "accounts.view"
+	PermAccountsEdit     Permission = "accounts.edit"
+	PermPlayersView      Permission = "players.view"
+	PermPlayersEdit      Permission = "players.edit"
+	PermSQLExecute       Permission = "sql.execute"
+	PermMaintenance      Permission = "maintenance.manage"
+	PermNewsManage       Permission = "news.manage"
+	PermCommentsModerate Permission = "comments.moderate"
+	PermChangelogsManage Permission = "changelogs.manage"
+	PermPagesManage      Permission = "pages.manage"
+	PermLogsView         Permission = "logs.view"
+	PermLockoutsManage   Permission = "lockouts.manage"
+	PermRolesManage      Permission = "roles.manage"
+	PermAuditView        Permission = "audit.view"
+)
+
+const RoleSuperAdmin = "super_admin"
+
+// Role groups the permissions granted to staff accounts
+type Role struct {
+	Name        string       `json:"name"`
+	Label       string       `json:"label"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Roles are the roles that can be assigned from the admin panel.
+// Accounts with the legacy page_access = 1 flag are treated as super admins.
+var Roles = []Role{
+	{
+		Name:  RoleSuperAdmin,
+		Label: "Super Admin",
+		Permissions: []Permission{
+			PermDashboardView, PermAccountsView, PermAccountsEdit, PermPlayersView, PermPlayersEdit,
+			PermSQLExecute, PermMaintenance, PermNewsManage, PermCommentsModerate, PermChangelogsManage,
+			PermPagesManage, PermLogsView, PermLockoutsManage, PermRolesManage, PermAuditView,
+		},
+	},
+	{
+		Name:  "support",
+		Label: "Support",
+		Permissions: []Permission{
+			PermDashboardView, PermAccountsView, PermPlayersView, PermLockoutsManage, PermLogsView,
+		},
+	},
+	{
+		Name:  "news_editor",
+		Label: "News Editor",
+		Permissions: []Permission{
+			PermDashboardView, PermNewsManage, PermCommentsModerate, PermChangelogsManage, PermPagesManage,
+		},
+	},
+	{
+		Name:        "comment_moderator",
+		Label:       "Comment Moderator",
+		Permissions: []Permission{PermDashboardView, PermCommentsModerate},
+	},
+}
+
+// PermissionSet holds the permissions of an account
+type PermissionSet map[Permission]bool
+
+// Has reports whether the set contains perm
+func (p PermissionSet) Has(perm Permission) bool {
+	return p[perm]
+}
+
+// List returns the permissions sorted by name
+func (p PermissionSet) List() []string {
+	list := make([]string, 0, len(p))
+	for perm := range p {
+		list = append(list, string(perm))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// GetRole looks up a role by name
+func GetRole(name string) (Role, bool) {
+	for _, role := range Roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return Role{}, false
+}
+
+// GetAccountRoles returns the roles assigned to an account, including super_admin for page_access = 1
+func GetAccountRoles(ctx context.Context, accountID int) ([]string, error) {
+	var pageAccess int
+	if err := database.DB.QueryRowContext(ctx,
+		"SELECT COALESCE(page_access, 0) FROM accounts WHERE id = ?",
+		accountID,
+	).Scan(&pageAccess); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.QueryContext(ctx, "SELECT role FROM account_roles WHERE account_id = ? ORDER BY role", accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	hasSuperAdmin := false
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		if role == RoleSuperAdmin {
+			hasSuperAdmin = true
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if pageAccess == 1 && !hasSuperAdmin {
+		roles = append([]string{RoleSuperAdmin}, roles...)
+	}
+
+	return roles, nil
+}
+
+// GetAccountPermissions resolves the permissions granted by all roles of an account
+func GetAccountPermissions(ctx context.Context, accountID int) (PermissionSet, error) {
+	roles, err := GetAccountRoles(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := PermissionSet{}
+	for _, name := range roles {
+		role, ok := GetRole(name)
+		if !ok {
+			continue
+		}
+		for _, perm := range role.Permissions {
+			permissions[perm] = true
+		}
+	}
+
+	return permissions, nil
+}
+
+// SetAccountRoles replaces the roles of an account. The legacy page_access flag follows super_admin
+// so that game servers and older tools that read it keep working.
+func SetAccountRoles(ctx context.Context, accountID int, roles []string, grantedBy int) error {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM account_roles WHERE account_id = ?", accountID); err != nil {
+		return err
+	}
+
+	pageAccess := 0
+	now := time.Now().Unix()
+	for _, role := range roles {
+		if role == RoleSuperAdmin {
+			pageAccess = 1
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO account_roles (account_id, role, granted_by, created_at) VALUES (?, ?, ?, ?)",
+			accountID, role, grantedBy, now,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET page_access = ? WHERE id = ?", pageAccess, accountID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
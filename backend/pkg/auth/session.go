@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	DefaultRefreshTokenTTLDays = 7
+
+	// lastSeenInterval limits how often authenticated requests write last_seen_at
+	lastSeenInterval   = 60
+	maxUserAgentLength = 255
+)
+
+var ErrSessionNotFound = errors.New("session not found, expired or revoked")
+
+// Session is a web login tracked in account_web_sessions.
+// Its ID is the jti of every access token issued for it.
+type Session struct {
+	ID         string `json:"id"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"userAgent"`
+	CreatedAt  int64  `json:"createdAt"`
+	LastSeenAt int64  `json:"lastSeenAt"`
+	ExpiresAt  int64  `json:"expiresAt"`
+	Current    bool   `json:"current"`
+}
+
+// TokenPair is returned when a session is created or refreshed
+type TokenPair struct {
+	SessionID        string
+	AccessToken      string
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// GetRefreshTokenTTL returns how long a session can go without being refreshed
+func GetRefreshTokenTTL() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_DAYS"))
+	if err != nil || days < 1 {
+		days = DefaultRefreshTokenTTLDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// CreateSession starts a new web session and issues its first token pair
+func CreateSession(ctx context.Context, accountID int, ip, userAgent string) (*TokenPair, error) {
+	sessionID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(GetRefreshTokenTTL())
+
+	_, err = database.DB.ExecContext(ctx,
+		`INSERT INTO account_web_sessions (id, account_id, refresh_token_hash, ip, user_agent, created_at, last_seen_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		sessionID, accountID, utils.HashSHA256(refreshToken), ip, truncateUserAgent(userAgent), now.Unix(), now.Unix(), refreshExpiresAt.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := GenerateToken(accountID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		SessionID:        sessionID,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// RefreshSession rotates the refresh token of an active session and issues a new access token.
+// The presented refresh token stops working as soon as it has been used.
+func RefreshSession(ctx context.Context, refreshToken, ip, userAgent string) (*TokenPair, error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	var sessionID string
+	var accountID int
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, account_id FROM account_web_sessions
+		 WHERE refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?
+		 FOR UPDATE`,
+		utils.HashSHA256(refreshToken), now.Unix(),
+	).Scan(&sessionID, &accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	newRefreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresAt := now.Add(GetRefreshTokenTTL())
+	_, err = tx.ExecContext(ctx,
+		`UPDATE account_web_sessions
+		 SET refresh_token_hash = ?, ip = ?, user_agent = ?, last_seen_at = ?, expires_at = ?
+		 WHERE id = ?`,
+		utils.HashSHA256(newRefreshToken), ip, truncateUserAgent(userAgent), now.Unix(), refreshExpiresAt.Unix(), sessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := GenerateToken(accountID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		SessionID:        sessionID,
+		AccessToken:      accessToken,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// IsSessionActive reports whether the session behind an access token is still valid and records activity
+func IsSessionActive(ctx context.Context, sessionID string, accountID int) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	now := time.Now().Unix()
+
+	var lastSeenAt int64
+	err := database.DB.QueryRowContext(ctx,
+		"SELECT last_seen_at FROM account_web_sessions WHERE id = ? AND account_id = ? AND revoked_at IS NULL AND expires_at > ?",
+		sessionID, accountID, now,
+	).Scan(&lastSeenAt)
+	if err != nil {
+		return false
+	}
+
+	if now-lastSeenAt >= lastSeenInterval {
+		_, _ = database.DB.ExecContext(ctx, "UPDATE account_web_sessions SET last_seen_at = ? WHERE id = ?", now, sessionID)
+	}
+
+	return true
+}
+
+// ListSessions returns the active web sessions of an account, most recently used first
+func ListSessions(ctx context.Context, accountID int) ([]Session, error) {
+	rows, err := database.DB.QueryContext(ctx,
+		`SELECT id, COALESCE(ip, ''), COALESCE(user_agent, ''), created_at, last_seen_at, expires_at
+		 FROM account_web_sessions
+		 WHERE account_id = ? AND revoked_at IS NULL AND expires_at > ?
+		 ORDER BY last_seen_at DESC`,
+		accountID, time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []Session{}
+	for rows.Next() {
+		var session Session
+		if err := rows.Scan(&session.ID, &session.IP, &session.UserAgent, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// RevokeSession revokes a single session of an account. Returns false when no active session matched.
+func RevokeSession(ctx context.Context, accountID int, sessionID string) (bool, error) {
+	result, err := database.DB.ExecContext(ctx,
+		"UPDATE account_web_sessions SET revoked_at = ? WHERE id = ? AND account_id = ? AND revoked_at IS NULL",
+		time.Now().Unix(), sessionID, accountID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// RevokeSessionByRefreshToken revokes the session a refresh token belongs to
+func RevokeSessionByRefreshToken(ctx context.Context, refreshToken string) error {
+	_, err := database.DB.ExecContext(ctx,
+		"UPDATE account_web_sessions SET revoked_at = ? WHERE refresh_token_hash = ? AND revoked_at IS NULL",
+		time.Now().Unix(), utils.HashSHA256(refreshToken),
+	)
+	return err
+}
+
+// RevokeAllSessions revokes every session of an account except exceptSessionID (pass "" to revoke all)
+func RevokeAllSessions(ctx context.Context, accountID int, exceptSessionID string) (int64, error) {
+	result, err := database.DB.ExecContext(ctx,
+		"UPDATE account_web_sessions SET revoked_at = ? WHERE account_id = ? AND id <> ? AND revoked_at IS NULL",
+		time.Now().Unix(), accountID, exceptSessionID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxUserAgentLength {
+		return userAgent[:maxUserAgentLength]
+	}
+	return userAgent
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ItemType holds the display data of an item from items.xml
+type ItemType struct {
+	ID       int    `json:"id"`
+	ClientID int    `json:"clientId"`
+	Name     string `json:"name"`
+	Article  string `json:"article,omitempty"`
+	Plural   string `json:"plural,omitempty"`
+}
+
+type itemsXML struct {
+	Items []itemXML `xml:"item"`
+}
+
+type itemXML struct {
+	ID       int    `xml:"id,attr"`
+	FromID   int    `xml:"fromid,attr"`
+	ToID     int    `xml:"toid,attr"`
+	ClientID int    `xml:"clientid,attr"`
+	Name     string `xml:"name,attr"`
+	Article  string `xml:"article,attr"`
+	Plural   string `xml:"plural,attr"`
+}
+
+// maxItemRange guards against a broken fromid/toid pair filling the catalog
+const maxItemRange = 10000
+
+var (
+	itemsCatalog      map[int]ItemType
+	itemsCatalogMutex sync.RWMutex
+	itemsFilePath     string
+)
+
+// InitItemsConfig initializes the item catalog from items.xml
+func InitItemsConfig(itemsPath string) error {
+	itemsFilePath = itemsPath
+	return ReloadItemsConfig()
+}
+
+func ReloadItemsConfig() error {
+	if itemsFilePath == "" {
+		serverPath := os.Getenv("SERVER_PATH")
+		if serverPath == "" {
+			return fmt.Errorf("SERVER_PATH not configured")
+		}
+
+		itemsFilePath = filepath.Join(serverPath, "data", "items", "items.xml")
+	}
+
+	content, err := os.ReadFile(itemsFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read items.xml: %w", err)
+	}
+
+	var parsed itemsXML
+	if err := xml.Unmarshal(content, &parsed); err != nil {
+		return fmt.Errorf("error parsing items.xml: %w", err)
+	}
+
+	catalog := make(map[int]ItemType, len(parsed.Items))
+	for _, item := range parsed.Items {
+		fromID, toID := item.ID, item.ID
+		if item.ID == 0 {
+			fromID, toID = item.FromID, item.ToID
+		}
+		if fromID <= 0 || toID < fromID || toID-fromID > maxItemRange {
+			continue
+		}
+
+		for id := fromID; id <= toID; id++ {
+			// Newer servers use the client id as item id, so clientid is only set where they differ
+			clientID := item.ClientID
+			if clientID == 0 {
+				clientID = id
+			} else if id != fromID {
+				clientID += id - fromID
+			}
+
+			catalog[id] = ItemType{
+				ID:       id,
+				ClientID: clientID,
+				Name:     item.Name,
+				Article:  item.Article,
+				Plural:   item.Plural,
+			}
+		}
+	}
+
+	itemsCatalogMutex.Lock()
+	itemsCatalog = catalog
+	itemsCatalogMutex.Unlock()
+
+	return nil
+}
+
+// GetItemType returns the catalog entry of an item. Unknown items get their id as client id and no name.
+func GetItemType(id int) ItemType {
+	itemsCatalogMutex.RLock()
+	defer itemsCatalogMutex.RUnlock()
+
+	if item, ok := itemsCatalog[id]; ok {
+		return item
+	}
+
+	return ItemType{ID: id, ClientID: id}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Achievement is completed when its value reaches Value (1 when not set)
+type Achievement struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Points      int    `json:"points"`
+	Secret      bool   `json:"secret"`
+	Storage     int    `json:"storage"`
+	KVKey       string `json:"kvKey"`
+	Value       int    `json:"value"`
+}
+
+// BestiaryEntry counts the kills of a creature; it is completed at Kills
+type BestiaryEntry struct {
+	Name    string `json:"name"`
+	Storage int    `json:"storage"`
+	KVKey   string `json:"kvKey"`
+	Kills   int    `json:"kills"`
+}
+
+// Charm is unlocked when its value is at least 1
+type Charm struct {
+	Name    string `json:"name"`
+	Storage int    `json:"storage"`
+	KVKey   string `json:"kvKey"`
+}
+
+// ProgressConfig maps player storages and KV store keys to achievements, bestiary entries and charms.
+// Every entry reads its player storage (Storage) or, when KVKey is set, a key of the server's KV store.
+// KVKey is relative to the player's scope: "achievements.points" reads kv_store key "player.<id>.achievements.points".
+type ProgressConfig struct {
+	Achievements []Achievement   `json:"achievements"`
+	Bestiary     []BestiaryEntry `json:"bestiary"`
+	Charms       []Charm         `json:"charms"`
+}
+
+var (
+	progressConfig      *ProgressConfig
+	progressConfigMutex sync.RWMutex
+	progressFilePath    string
+)
+
+// InitProgressConfig initializes the storage mapping from a JSON file
+func InitProgressConfig(progressPath string) error {
+	progressFilePath = progressPath
+	return ReloadProgressConfig()
+}
+
+func ReloadProgressConfig() error {
+	if progressFilePath == "" {
+		progressFilePath = os.Getenv("PROGRESS_MAPPING_FILE")
+		if progressFilePath == "" {
+			return fmt.Errorf("PROGRESS_MAPPING_FILE not configured")
+		}
+	}
+
+	content, err := os.ReadFile(progressFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read progress mapping: %w", err)
+	}
+
+	config := &ProgressConfig{}
+	if err := json.Unmarshal(content, config); err != nil {
+		return fmt.Errorf("error parsing progress mapping: %w", err)
+	}
+
+	for i := range config.Achievements {
+		if config.Achievements[i].Value == 0 {
+			config.Achievements[i].Value = 1
+		}
+	}
+	for _, entry := range config.Bestiary {
+		if entry.Kills < 1 {
+			return fmt.Errorf("bestiary entry %q needs a kill count", entry.Name)
+		}
+	}
+
+	progressConfigMutex.Lock()
+	progressConfig = config
+	progressConfigMutex.Unlock()
+
+	return nil
+}
+
+// GetProgressConfig returns the current storage mapping. It is replaced, never changed, on reload, so callers must not modify it.
+func GetProgressConfig() *ProgressConfig {
+	progressConfigMutex.RLock()
+	defer progressConfigMutex.RUnlock()
+
+	if progressConfig == nil {
+		return &ProgressConfig{}
+	}
+
+	return progressConfig
+}
+
+// StorageKeys returns the distinct player storages used by the mapping
+func (c *ProgressConfig) StorageKeys() []int {
+	seen := make(map[int]bool)
+	keys := make([]int, 0, len(c.Achievements)+len(c.Bestiary)+len(c.Charms))
+
+	add := func(key int, kvKey string) {
+		if kvKey == "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for _, achievement := range c.Achievements {
+		add(achievement.Storage, achievement.KVKey)
+	}
+	for _, entry := range c.Bestiary {
+		add(entry.Storage, entry.KVKey)
+	}
+	for _, charm := range c.Charms {
+		add(charm.Storage, charm.KVKey)
+	}
+
+	return keys
+}
+
+// KVKeys returns the distinct KV keys, relative to the player's scope, used by the mapping
+func (c *ProgressConfig) KVKeys() []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+
+	add := func(kvKey string) {
+		if kvKey != "" && !seen[kvKey] {
+			seen[kvKey] = true
+			keys = append(keys, kvKey)
+		}
+	}
+
+	for _, achievement := range c.Achievements {
+		add(achievement.KVKey)
+	}
+	for _, entry := range c.Bestiary {
+		add(entry.KVKey)
+	}
+	for _, charm := range c.Charms {
+		add(charm.KVKey)
+	}
+
+	return keys
+}
@@ -0,0 +1,147 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+	DriverLog  = "log"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers outgoing emails
+type Sender interface {
+	Send(msg Message) error
+}
+
+var (
+	activeSender     Sender
+	activeSenderOnce sync.Once
+)
+
+// GetSender returns the sender selected by MAIL_DRIVER (smtp, file or log).
+// Defaults to log so local setups work without a mail server.
+func GetSender() Sender {
+	activeSenderOnce.Do(func() {
+		switch strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DRIVER"))) {
+		case DriverSMTP:
+			activeSender = SMTPSender{
+				Host:     os.Getenv("SMTP_HOST"),
+				Port:     getEnvDefault("SMTP_PORT", "587"),
+				Username: os.Getenv("SMTP_USERNAME"),
+				Password: os.Getenv("SMTP_PASSWORD"),
+				From:     getFromAddress(),
+			}
+		case DriverFile:
+			activeSender = FileSender{
+				Path: getEnvDefault("MAIL_FILE_PATH", "mail.log"),
+				From: getFromAddress(),
+			}
+		default:
+			activeSender = LogSender{}
+		}
+	})
+	return activeSender
+}
+
+// Send delivers a message with the configured sender
+func Send(msg Message) error {
+	return GetSender().Send(msg)
+}
+
+// SMTPSender sends emails through an SMTP server using PLAIN auth when credentials are set
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s SMTPSender) Send(msg Message) error {
+	if s.Host == "" {
+		return fmt.Errorf("SMTP_HOST is not configured")
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	addr := net.JoinHostPort(s.Host, s.Port)
+	if err := smtp.SendMail(addr, auth, s.From, []string{msg.To}, buildMessage(s.From, msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// FileSender appends emails to a file instead of delivering them, useful for local testing
+type FileSender struct {
+	Path string
+	From string
+}
+
+var fileSenderMu sync.Mutex
+
+func (s FileSender) Send(msg Message) error {
+	fileSenderMu.Lock()
+	defer fileSenderMu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(buildMessage(s.From, msg), "\r\n\r\n"...)); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+// LogSender writes emails to the server log
+type LogSender struct{}
+
+func (LogSender) Send(msg Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func getFromAddress() string {
+	return getEnvDefault("MAIL_FROM", "no-reply@localhost")
+}
+
+func getEnvDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
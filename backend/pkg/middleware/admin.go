@@ -1,13 +1,18 @@
 package middleware
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 
-	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/utils"
 )
 
+const PermissionsKey contextKey = "permissions"
+
+// AdminMiddleware lets through accounts that hold at least one staff role and stores their permissions in the context.
+// Each route then declares the permission it needs with RequirePermission.
 func AdminMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := r.Context().Value(UserIDKey).(int)
@@ -19,12 +24,7 @@ func AdminMiddleware(next http.Handler) http.Handler {
 		ctx, cancel := utils.NewDBContext()
 		defer cancel()
 
-		var pageAccess int
-		err := database.DB.QueryRowContext(ctx,
-			"SELECT page_access FROM accounts WHERE id = ?",
-			userID,
-		).Scan(&pageAccess)
-
+		permissions, err := auth.GetAccountPermissions(ctx, userID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				utils.WriteError(w, http.StatusNotFound, "Page not found")
@@ -37,12 +37,29 @@ func AdminMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if pageAccess != 1 {
+		if len(permissions) == 0 {
 			utils.WriteError(w, http.StatusNotFound, "Page not found")
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), PermissionsKey, permissions)))
+	})
+}
+
+// RequirePermission wraps an admin handler so it only runs for accounts with perm
+func RequirePermission(perm auth.Permission, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !HasPermission(r, perm) {
+			utils.WriteError(w, http.StatusForbidden, "You don't have permission to do this")
+			return
+		}
+
+		handler(w, r)
 	})
 }
 
+// HasPermission reports whether the account behind an admin request holds perm
+func HasPermission(r *http.Request, perm auth.Permission) bool {
+	permissions, ok := r.Context().Value(PermissionsKey).(auth.PermissionSet)
+	return ok && permissions.Has(perm)
+}
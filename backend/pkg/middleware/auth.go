@@ -12,7 +12,10 @@ import (
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey    contextKey = "userID"
+	SessionIDKey contextKey = "sessionID"
+)
 
 func extractToken(r *http.Request) (string, error) {
 	tokenString, err := utils.GetAuthCookie(r)
@@ -39,7 +42,15 @@ func addUserIDToContext(r *http.Request, tokenString string) (*http.Request, boo
 		return r, false
 	}
 
+	// A valid signature isn't enough: the session behind the token may have been revoked
+	dbCtx, cancel := utils.NewDBContext()
+	defer cancel()
+	if !auth.IsSessionActive(dbCtx, claims.ID, claims.UserID) {
+		return r, false
+	}
+
 	ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, SessionIDKey, claims.ID)
 	return r.WithContext(ctx), true
 }
 
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"log"
+	"math/bits"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"codexaac-backend/pkg/auth"
+	"codexaac-backend/pkg/utils"
+)
+
+const (
+	// ChallengeTokenHeader and ChallengeSolutionHeader carry a solved challenge with the retried request
+	ChallengeTokenHeader    = "X-Challenge-Token"
+	ChallengeSolutionHeader = "X-Challenge-Solution"
+
+	DefaultProofOfWorkDifficulty = 16
+	maxProofOfWorkDifficulty     = 28
+	proofOfWorkTTL               = 10 * time.Minute
+)
+
+// Challenge is a test a client must pass before a protected request is accepted, e.g. a proof-of-work
+// puzzle or a CAPTCHA. Proof of work is the default; another implementation can be plugged in with SetChallenge.
+type Challenge interface {
+	// Issue creates a new challenge for the client to solve
+	Issue(r *http.Request) (*ChallengeInfo, error)
+	// Verify checks the solution sent for a challenge token. A solved challenge can only be used once.
+	Verify(r *http.Request, token, solution string) bool
+}
+
+// ChallengeInfo is sent to the client with a 428 response; Params depend on the challenge type
+type ChallengeInfo struct {
+	Type   string                 `json:"type"`
+	Token  string                 `json:"token"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// ChallengePolicy lets Threshold requests per client IP through without a challenge, refilling one every Refill.
+// A Threshold of 0 requires a challenge on every request.
+type ChallengePolicy struct {
+	Name      string
+	Threshold int
+	Refill    time.Duration
+}
+
+var (
+	challenge     Challenge
+	challengeOnce sync.Once
+	challengeSet  bool
+)
+
+// GetChallenge returns the configured challenge, or nil when challenges are disabled (CHALLENGE_PROVIDER=none)
+func GetChallenge() Challenge {
+	challengeOnce.Do(func() {
+		if challengeSet {
+			return
+		}
+
+		switch provider := strings.ToLower(os.Getenv("CHALLENGE_PROVIDER")); provider {
+		case "none", "off":
+			challenge = nil
+		case "", "pow":
+			difficulty, err := strconv.Atoi(os.Getenv("CHALLENGE_POW_DIFFICULTY"))
+			if err != nil || difficulty < 1 {
+				difficulty = DefaultProofOfWorkDifficulty
+			}
+			challenge = NewProofOfWorkChallenge(difficulty)
+		default:
+			log.Printf("⚠️  Unknown CHALLENGE_PROVIDER %q, using proof of work", provider)
+			challenge = NewProofOfWorkChallenge(DefaultProofOfWorkDifficulty)
+		}
+	})
+	return challenge
+}
+
+// SetChallenge replaces the challenge (nil disables it). Call it before the server starts handling requests.
+func SetChallenge(c Challenge) {
+	challenge = c
+	challengeSet = true
+}
+
+// ChallengeMiddleware asks clients that exceeded the policy threshold to solve a challenge first.
+// The client retries the request with the token and its solution in the X-Challenge-* headers.
+func ChallengeMiddleware(policy ChallengePolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := GetChallenge()
+			if r.Method == http.MethodOptions || c == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if token := r.Header.Get(ChallengeTokenHeader); token != "" {
+				if c.Verify(r, token, r.Header.Get(ChallengeSolutionHeader)) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				writeChallengeRequired(w, r, c, "Invalid or expired challenge solution. Please try again.")
+				return
+			}
+
+			if policy.Threshold > 0 {
+				key := "challenge:" + policy.Name + ":ip:" + utils.GetClientIP(r)
+				if allowed, _ := GetRateLimitStore().Take(key, policy.Threshold, policy.Refill); allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			writeChallengeRequired(w, r, c, "Please complete the challenge to continue.")
+		})
+	}
+}
+
+// RequireChallenge asks the client for a solved challenge whatever the threshold, for handlers that only
+// need one in some cases. Returns false after writing the response. Passes when challenges are disabled.
+func RequireChallenge(w http.ResponseWriter, r *http.Request) bool {
+	c := GetChallenge()
+	if c == nil {
+		return true
+	}
+
+	if token := r.Header.Get(ChallengeTokenHeader); token != "" {
+		if c.Verify(r, token, r.Header.Get(ChallengeSolutionHeader)) {
+			return true
+		}
+		writeChallengeRequired(w, r, c, "Invalid or expired challenge solution. Please try again.")
+		return false
+	}
+
+	writeChallengeRequired(w, r, c, "Please complete the challenge to continue.")
+	return false
+}
+
+func writeChallengeRequired(w http.ResponseWriter, r *http.Request, c Challenge, message string) {
+	info, err := c.Issue(r)
+	if err != nil {
+		log.Printf("Error issuing challenge: %v", err)
+		utils.WriteError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusPreconditionRequired, map[string]interface{}{
+		"message": message,
+		"data": map[string]interface{}{
+			"challenge": info,
+		},
+	})
+}
+
+// ProofOfWorkChallenge is a self-hosted challenge: the client searches for a solution whose
+// SHA-256 hash of "<token>:<solution>" starts with Difficulty zero bits.
+// Tokens are signed instead of stored; used ones are remembered in the rate limit store until they expire.
+type ProofOfWorkChallenge struct {
+	Difficulty int
+}
+
+// NewProofOfWorkChallenge creates a proof-of-work challenge. Every extra bit of difficulty doubles the expected work.
+func NewProofOfWorkChallenge(difficulty int) *ProofOfWorkChallenge {
+	return &ProofOfWorkChallenge{Difficulty: min(difficulty, maxProofOfWorkDifficulty)}
+}
+
+func (p *ProofOfWorkChallenge) Issue(r *http.Request) (*ChallengeInfo, error) {
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := nonce + ":" + strconv.FormatInt(time.Now().Add(proofOfWorkTTL).Unix(), 10) + ":" + strconv.Itoa(p.Difficulty)
+	return &ChallengeInfo{
+		Type:  "pow",
+		Token: payload + ":" + auth.SignChallenge(payload),
+		Params: map[string]interface{}{
+			"algorithm":  "sha256",
+			"difficulty": p.Difficulty,
+		},
+	}, nil
+}
+
+func (p *ProofOfWorkChallenge) Verify(r *http.Request, token, solution string) bool {
+	if solution == "" || len(solution) > 32 || len(token) > 256 {
+		return false
+	}
+
+	// nonce:expires:difficulty:signature
+	parts := strings.Split(token, ":")
+	if len(parts) != 4 {
+		return false
+	}
+
+	payload := strings.Join(parts[:3], ":")
+	if !auth.VerifyChallenge(payload, parts[3]) {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || expires <= time.Now().Unix() {
+		return false
+	}
+
+	difficulty, err := strconv.Atoi(parts[2])
+	if err != nil || leadingZeroBits(sha256.Sum256([]byte(token+":"+solution))) < difficulty {
+		return false
+	}
+
+	// The token is spent by the first request that uses it; the entry outlives the token's expiry
+	allowed, _ := GetRateLimitStore().Take("challenge:used:"+parts[0], 1, 24*time.Hour)
+	return allowed
+}
+
+func leadingZeroBits(hash [sha256.Size]byte) int {
+	count := 0
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"codexaac-backend/internal/database"
+	"codexaac-backend/pkg/auth"
 	"codexaac-backend/pkg/utils"
 )
 
@@ -17,6 +18,7 @@ func MaintenanceMiddleware(next http.Handler) http.Handler {
 		path := r.URL.Path
 		if path == "/api/health" || path == "/api" || path == "/maintenance" || 
 		   path == "/api/maintenance/status" || path == "/login.php" || path == "/login" ||
+		   path == "/api/auth/refresh" ||
 		   strings.HasPrefix(path, "/api/admin") {
 			next.ServeHTTP(w, r)
 			return
@@ -42,13 +44,9 @@ func MaintenanceMiddleware(next http.Handler) http.Handler {
 		if enabled {
 			userID, ok := r.Context().Value(UserIDKey).(int)
 			if ok && userID > 0 {
-				var pageAccess int
-				err := database.DB.QueryRowContext(ctx,
-					"SELECT page_access FROM accounts WHERE id = ?",
-					userID,
-				).Scan(&pageAccess)
-
-				if err == nil && pageAccess == 1 {
+				// Any staff role may browse the site during maintenance
+				permissions, err := auth.GetAccountPermissions(ctx, userID)
+				if err == nil && len(permissions) > 0 {
 					next.ServeHTTP(w, r)
 					return
 				}
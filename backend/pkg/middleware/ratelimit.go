@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"codexaac-backend/pkg/utils"
+)
+
+// RateLimit configures a token bucket: Capacity requests in a burst, then one more every Refill
+type RateLimit struct {
+	Name     string
+	Capacity int
+	Refill   time.Duration
+	// OnLimited writes the rejection; defaults to a 429 JSON error. Lets /login answer in the client's format.
+	OnLimited func(w http.ResponseWriter, retryAfter time.Duration)
+}
+
+// LockoutPolicy configures progressive lockouts: every MaxFailures consecutive failures lock the key,
+// for BaseDuration the first time and twice as long on each following lockout, up to MaxDuration
+type LockoutPolicy struct {
+	MaxFailures  int
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+	// ResetAfter forgets failures and the lockout history when a key has been quiet this long
+	ResetAfter time.Duration
+}
+
+// Lockout describes a key that is currently locked
+type Lockout struct {
+	Key         string    `json:"key"`
+	Scope       string    `json:"scope,omitempty"`
+	Identifier  string    `json:"identifier,omitempty"`
+	Failures    int       `json:"failures"`
+	Lockouts    int       `json:"lockouts"`
+	LockedUntil time.Time `json:"lockedUntil"`
+	LastFailure time.Time `json:"lastFailure"`
+}
+
+// RateLimitStore keeps token buckets and failure counters. The in-memory store is the default;
+// a shared backend (e.g. Redis) can be plugged in with SetRateLimitStore when running several instances.
+type RateLimitStore interface {
+	// Take removes a token from the bucket and returns how long to wait when it is empty
+	Take(key string, capacity int, refill time.Duration) (allowed bool, retryAfter time.Duration)
+	// AddFailure records a failure and returns the time the key is locked until (zero if not locked)
+	AddFailure(key string, policy LockoutPolicy) time.Time
+	// Lock locks key until the given time, unless it is already locked for longer
+	Lock(key string, until time.Time, policy LockoutPolicy)
+	// ClearFailures resets the failure counter and lifts any lockout
+	ClearFailures(key string)
+	// LockedUntil returns when the lockout of key ends (zero if not locked)
+	LockedUntil(key string) time.Time
+	// Lockouts lists the keys that are currently locked
+	Lockouts() []Lockout
+}
+
+var (
+	rateLimitStore     RateLimitStore
+	rateLimitStoreOnce sync.Once
+)
+
+// GetRateLimitStore returns the store used by the rate limiter and login lockouts
+func GetRateLimitStore() RateLimitStore {
+	rateLimitStoreOnce.Do(func() {
+		if rateLimitStore == nil {
+			rateLimitStore = NewMemoryRateLimitStore()
+		}
+	})
+	return rateLimitStore
+}
+
+// SetRateLimitStore replaces the store. Call it before the server starts handling requests.
+func SetRateLimitStore(store RateLimitStore) {
+	rateLimitStore = store
+}
+
+// RateLimitMiddleware limits requests per client IP with a token bucket
+func RateLimitMiddleware(limit RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := "rate:" + limit.Name + ":ip:" + utils.GetClientIP(r)
+			if allowed, retryAfter := GetRateLimitStore().Take(key, limit.Capacity, limit.Refill); !allowed {
+				writeRateLimited(w, limit.OnLimited, retryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeRateLimited(w http.ResponseWriter, onLimited func(http.ResponseWriter, time.Duration), retryAfter time.Duration) {
+	if onLimited != nil {
+		onLimited(w, retryAfter)
+		return
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	utils.WriteError(w, http.StatusTooManyRequests, "Too many requests. Please try again later.")
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type failureRecord struct {
+	failures    int
+	lockouts    int
+	lockedUntil time.Time
+	lastFailure time.Time
+	resetAfter  time.Duration
+}
+
+// MemoryRateLimitStore is a process-local RateLimitStore
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	failures map[string]*failureRecord
+}
+
+// NewMemoryRateLimitStore creates an in-memory store and starts a janitor that drops idle entries
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	store := &MemoryRateLimitStore{
+		buckets:  make(map[string]*tokenBucket),
+		failures: make(map[string]*failureRecord),
+	}
+	go store.janitor(time.Minute)
+	return store
+}
+
+func (s *MemoryRateLimitStore) Take(key string, capacity int, refill time.Duration) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(capacity), updated: now}
+		s.buckets[key] = bucket
+	} else {
+		bucket.tokens = math.Min(float64(capacity), bucket.tokens+float64(now.Sub(bucket.updated))/float64(refill))
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) * float64(refill))
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+func (s *MemoryRateLimitStore) AddFailure(key string, policy LockoutPolicy) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, ok := s.failures[key]
+	if !ok || (policy.ResetAfter > 0 && now.Sub(record.lastFailure) > policy.ResetAfter) {
+		record = &failureRecord{}
+		s.failures[key] = record
+	}
+
+	record.failures++
+	record.lastFailure = now
+	record.resetAfter = policy.ResetAfter
+
+	if policy.MaxFailures > 0 && record.failures >= policy.MaxFailures {
+		record.lockouts++
+		record.failures = 0
+
+		duration := policy.BaseDuration * time.Duration(1<<uint(min(record.lockouts-1, 16)))
+		if policy.MaxDuration > 0 && duration > policy.MaxDuration {
+			duration = policy.MaxDuration
+		}
+		record.lockedUntil = now.Add(duration)
+	}
+
+	if record.lockedUntil.After(now) {
+		return record.lockedUntil
+	}
+	return time.Time{}
+}
+
+func (s *MemoryRateLimitStore) Lock(key string, until time.Time, policy LockoutPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, ok := s.failures[key]
+	if !ok {
+		record = &failureRecord{}
+		s.failures[key] = record
+	}
+
+	record.lastFailure = now
+	record.resetAfter = policy.ResetAfter
+	if until.After(record.lockedUntil) {
+		record.lockedUntil = until
+	}
+}
+
+func (s *MemoryRateLimitStore) ClearFailures(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+}
+
+func (s *MemoryRateLimitStore) LockedUntil(key string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.failures[key]; ok && record.lockedUntil.After(time.Now()) {
+		return record.lockedUntil
+	}
+	return time.Time{}
+}
+
+func (s *MemoryRateLimitStore) Lockouts() []Lockout {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	lockouts := []Lockout{}
+	for key, record := range s.failures {
+		if !record.lockedUntil.After(now) {
+			continue
+		}
+		lockouts = append(lockouts, Lockout{
+			Key:         key,
+			Failures:    record.failures,
+			Lockouts:    record.lockouts,
+			LockedUntil: record.lockedUntil,
+			LastFailure: record.lastFailure,
+		})
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].LockedUntil.After(lockouts[j].LockedUntil)
+	})
+	return lockouts
+}
+
+func (s *MemoryRateLimitStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		s.mu.Lock()
+		for key, bucket := range s.buckets {
+			// An idle bucket is full again after a while, so dropping it changes nothing
+			if now.Sub(bucket.updated) > time.Hour {
+				delete(s.buckets, key)
+			}
+		}
+		for key, record := range s.failures {
+			if record.lockedUntil.After(now) {
+				continue
+			}
+			if record.resetAfter > 0 && now.Sub(record.lastFailure) > record.resetAfter {
+				delete(s.failures, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
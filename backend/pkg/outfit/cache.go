@@ -0,0 +1,182 @@
+package outfit
+
+import (
+	"bytes"
+	"container/list"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Cache keeps rendered outfits as PNG files in a folder and evicts the least recently used ones
+// once the files add up to more than maxBytes
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	name string
+	size int64
+}
+
+// NewCache opens the cache folder, picking up the files a previous run left in it
+func NewCache(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type cachedFile struct {
+		entry   cacheEntry
+		modTime int64
+	}
+	existing := make([]cachedFile, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		// Leftovers of writes that were interrupted
+		if strings.HasSuffix(file.Name(), ".tmp") {
+			os.Remove(filepath.Join(dir, file.Name()))
+			continue
+		}
+		if !strings.HasSuffix(file.Name(), ".png") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		existing = append(existing, cachedFile{
+			entry:   cacheEntry{name: file.Name(), size: info.Size()},
+			modTime: info.ModTime().UnixNano(),
+		})
+	}
+
+	// Oldest first, so the newest files end up at the front
+	sort.Slice(existing, func(i, j int) bool { return existing[i].modTime < existing[j].modTime })
+
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, len(existing)),
+	}
+	for _, file := range existing {
+		c.entries[file.entry.name] = c.order.PushFront(&file.entry)
+		c.size += file.entry.size
+	}
+
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+
+	return c, nil
+}
+
+// Get returns the cached PNG of an outfit
+func (c *Cache) Get(o Outfit) ([]byte, bool) {
+	name := o.Normalize().CacheName()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[name]
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		// Removed from outside, e.g. after changing sprites
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return data, true
+}
+
+// Put encodes a rendered outfit and stores it. The PNG is returned even when it could not be stored.
+func (c *Cache) Put(o Outfit, img image.Image) ([]byte, error) {
+	data, err := Encode(img)
+	if err != nil {
+		return nil, err
+	}
+	name := o.Normalize().CacheName()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[name]; ok {
+		c.order.MoveToFront(element)
+		return data, nil
+	}
+
+	// Written under a temporary name and renamed, so a crash never leaves a half written image behind
+	file, err := os.CreateTemp(c.dir, "outfit-*.tmp")
+	if err != nil {
+		return data, err
+	}
+	tmpName := file.Name()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return data, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpName)
+		return data, err
+	}
+	if err := os.Rename(tmpName, filepath.Join(c.dir, name)); err != nil {
+		os.Remove(tmpName)
+		return data, err
+	}
+
+	c.entries[name] = c.order.PushFront(&cacheEntry{name: name, size: int64(len(data))})
+	c.size += int64(len(data))
+	c.evict()
+
+	return data, nil
+}
+
+// Encode returns an image as PNG
+func Encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// evict drops the least recently used files until the cache fits in maxBytes. Requires c.mu.
+func (c *Cache) evict() {
+	for c.size > c.maxBytes && c.order.Len() > 0 {
+		element := c.order.Back()
+		os.Remove(filepath.Join(c.dir, element.Value.(*cacheEntry).name))
+		c.remove(element)
+	}
+}
+
+// remove forgets an entry without touching its file. Requires c.mu.
+func (c *Cache) remove(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+}
@@ -0,0 +1,237 @@
+package outfit
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// PaletteSize is the number of outfit colors of the Tibia client (0-132)
+	PaletteSize = 133
+
+	// direction and frame of the sprite that is rendered: standing, facing south
+	direction = 3
+	frame     = 1
+)
+
+// ErrUnknownLookType is returned when the sprite folder has no sprites for a look type
+var ErrUnknownLookType = errors.New("unknown look type")
+
+// Outfit is what the game server stores in players.look*
+type Outfit struct {
+	LookType int
+	Addons   int
+	Head     int
+	Body     int
+	Legs     int
+	Feet     int
+}
+
+// Normalize clamps the colors to the palette and the addons to the two addon bits
+func (o Outfit) Normalize() Outfit {
+	clampColor := func(c int) int {
+		if c < 0 || c >= PaletteSize {
+			return 0
+		}
+		return c
+	}
+
+	o.Addons &= 3
+	o.Head = clampColor(o.Head)
+	o.Body = clampColor(o.Body)
+	o.Legs = clampColor(o.Legs)
+	o.Feet = clampColor(o.Feet)
+	return o
+}
+
+// CacheName returns the file name of the rendered outfit in the cache folder
+func (o Outfit) CacheName() string {
+	return fmt.Sprintf("%d_%d_%d_%d_%d_%d.png", o.LookType, o.Addons, o.Head, o.Body, o.Legs, o.Feet)
+}
+
+// Render builds the image of an outfit from the sprites in spritesPath. The sprites of a look type are in a
+// folder named after it, one PNG per layer as {layer}_1_{direction}_{frame}.png (layer 1 is the outfit, 2 and 3
+// the addons) with an optional {layer}_1_{direction}_{frame}_template.png whose yellow, red, green and blue
+// parts are tinted with the head, body, legs and feet colors.
+func Render(spritesPath string, o Outfit) (*image.NRGBA, error) {
+	o = o.Normalize()
+	folder := filepath.Join(spritesPath, fmt.Sprint(o.LookType))
+
+	layers := []int{1}
+	if o.Addons&1 != 0 {
+		layers = append(layers, 2)
+	}
+	if o.Addons&2 != 0 {
+		layers = append(layers, 3)
+	}
+
+	var canvas *image.NRGBA
+	for _, layer := range layers {
+		name := fmt.Sprintf("%d_1_%d_%d", layer, direction, frame)
+
+		sprite, err := loadPNG(filepath.Join(folder, name+".png"))
+		if err != nil {
+			if layer == 1 && errors.Is(err, os.ErrNotExist) {
+				return nil, ErrUnknownLookType
+			}
+			if errors.Is(err, os.ErrNotExist) {
+				// Outfits without addon sprites are drawn without them
+				continue
+			}
+			return nil, err
+		}
+
+		template, err := loadPNG(filepath.Join(folder, name+"_template.png"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if template != nil {
+			colorize(sprite, template, o)
+		}
+
+		if canvas == nil {
+			canvas = image.NewNRGBA(sprite.Bounds())
+		}
+		// Sprites grow up and to the left, so layers of a different size are aligned bottom right
+		offset := canvas.Bounds().Max.Sub(sprite.Bounds().Max)
+		draw.Draw(canvas, sprite.Bounds().Add(offset), sprite, sprite.Bounds().Min, draw.Over)
+	}
+
+	return canvas, nil
+}
+
+// colorize multiplies the parts of the sprite marked in the template with the outfit colors
+func colorize(sprite, template *image.NRGBA, o Outfit) {
+	head, body, legs, feet := PaletteColor(o.Head), PaletteColor(o.Body), PaletteColor(o.Legs), PaletteColor(o.Feet)
+
+	bounds := sprite.Bounds().Intersect(template.Bounds())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			mask := template.NRGBAAt(x, y)
+			if mask.A == 0 {
+				continue
+			}
+
+			var tint color.NRGBA
+			switch {
+			case mask.R > 0 && mask.G > 0 && mask.B == 0:
+				tint = head
+			case mask.R > 0 && mask.G == 0 && mask.B == 0:
+				tint = body
+			case mask.R == 0 && mask.G > 0 && mask.B == 0:
+				tint = legs
+			case mask.R == 0 && mask.G == 0 && mask.B > 0:
+				tint = feet
+			default:
+				continue
+			}
+
+			pixel := sprite.NRGBAAt(x, y)
+			pixel.R = uint8(int(pixel.R) * int(tint.R) / 255)
+			pixel.G = uint8(int(pixel.G) * int(tint.G) / 255)
+			pixel.B = uint8(int(pixel.B) * int(tint.B) / 255)
+			sprite.SetNRGBA(x, y, pixel)
+		}
+	}
+}
+
+// PaletteColor returns an outfit color of the client palette: 19 hues in 7 saturation and brightness steps,
+// with the grays in the first column
+func PaletteColor(index int) color.NRGBA {
+	const hueSteps, siValues = 19, 7
+
+	if index < 0 || index >= hueSteps*siValues {
+		index = 0
+	}
+
+	var hue, saturation, intensity float64
+	if index%hueSteps == 0 {
+		saturation = 0
+		intensity = 1 - float64(index)/hueSteps/siValues
+	} else {
+		hue = float64(index%hueSteps) / 18
+		saturation, intensity = 1, 1
+
+		switch index / hueSteps {
+		case 0:
+			saturation, intensity = 0.25, 1
+		case 1:
+			saturation, intensity = 0.25, 0.75
+		case 2:
+			saturation, intensity = 0.5, 0.75
+		case 3:
+			saturation, intensity = 0.667, 0.75
+		case 4:
+			saturation, intensity = 1, 1
+		case 5:
+			saturation, intensity = 1, 0.75
+		case 6:
+			saturation, intensity = 1, 0.5
+		}
+	}
+
+	if intensity == 0 {
+		return color.NRGBA{A: 255}
+	}
+	if saturation == 0 {
+		gray := uint8(intensity * 255)
+		return color.NRGBA{R: gray, G: gray, B: gray, A: 255}
+	}
+
+	var r, g, b float64
+	switch {
+	case hue < 1.0/6:
+		r = intensity
+		b = intensity * (1 - saturation)
+		g = b + (intensity-b)*6*hue
+	case hue < 2.0/6:
+		g = intensity
+		b = intensity * (1 - saturation)
+		r = g - (intensity-b)*(6*hue-1)
+	case hue < 3.0/6:
+		g = intensity
+		r = intensity * (1 - saturation)
+		b = r + (intensity-r)*(6*hue-2)
+	case hue < 4.0/6:
+		b = intensity
+		r = intensity * (1 - saturation)
+		g = b - (intensity-r)*(6*hue-3)
+	case hue < 5.0/6:
+		b = intensity
+		g = intensity * (1 - saturation)
+		r = g + (intensity-g)*(6*hue-4)
+	default:
+		r = intensity
+		g = intensity * (1 - saturation)
+		b = r - (intensity-g)*(6*hue-5)
+	}
+
+	return color.NRGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+}
+
+func loadPNG(path string) (*image.NRGBA, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", filepath.Base(path), err)
+	}
+
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba, nil
+	}
+
+	nrgba := image.NewNRGBA(img.Bounds())
+	draw.Draw(nrgba, nrgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nrgba, nil
+}
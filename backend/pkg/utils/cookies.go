@@ -8,6 +8,9 @@ import (
 const (
 	TokenCookieName = "auth_token"
 	TokenCookieMaxAge = 24 * 60 * 60
+	RefreshCookieName = "refresh_token"
+	// RefreshCookiePath keeps the refresh token off every request except the API
+	RefreshCookiePath = "/api"
 )
 
 func SetAuthCookie(w http.ResponseWriter, token string, isSecure bool) {
@@ -50,3 +53,42 @@ func GetAuthCookie(r *http.Request) (string, error) {
 	return cookie.Value, nil
 }
 
+func SetRefreshCookie(w http.ResponseWriter, token string, maxAge int, isSecure bool) {
+	cookie := &http.Cookie{
+		Name:     RefreshCookieName,
+		Value:    token,
+		Path:     RefreshCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isSecure,
+	}
+
+	if isSecure {
+		cookie.SameSite = http.SameSiteNoneMode
+	} else {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(w, cookie)
+}
+
+func ClearRefreshCookie(w http.ResponseWriter, isSecure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshCookieName,
+		Value:    "",
+		Path:     RefreshCookiePath,
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+		Secure:   isSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func GetRefreshCookie(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(RefreshCookieName)
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
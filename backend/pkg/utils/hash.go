@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 )
 
@@ -11,3 +13,17 @@ func HashSHA1(input string) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
+// HashSHA256 is used to store lookup hashes of random tokens instead of the tokens themselves
+func HashSHA256(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateRandomToken returns byteLength cryptographically random bytes as a hex string
+func GenerateRandomToken(byteLength int) (string, error) {
+	buf := make([]byte, byteLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	DefaultPasswordMinLength = 6
+	MaxPasswordLength        = 128
+)
+
+// PasswordPolicy is read from the environment:
+// PASSWORD_MIN_LENGTH, PASSWORD_MIN_CHARACTER_CLASSES (lowercase, uppercase, digits, symbols) and
+// PASSWORD_BREACHED_LIST_FILE, a text file with one known leaked password per line.
+// The password hasher can lower MaxLength further, see ValidatePassword.
+type PasswordPolicy struct {
+	MinLength           int `json:"minLength"`
+	MaxLength           int `json:"maxLength"`
+	MinCharacterClasses int `json:"minCharacterClasses"`
+	breached            map[string]struct{}
+}
+
+var (
+	passwordPolicy     *PasswordPolicy
+	passwordPolicyOnce sync.Once
+)
+
+// GetPasswordPolicy returns the policy, loading the breached password list on first use
+func GetPasswordPolicy() *PasswordPolicy {
+	passwordPolicyOnce.Do(func() {
+		policy := &PasswordPolicy{MinLength: DefaultPasswordMinLength, MaxLength: MaxPasswordLength}
+
+		if minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && minLength > 0 && minLength <= policy.MaxLength {
+			policy.MinLength = minLength
+		}
+		if classes, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_CHARACTER_CLASSES")); err == nil && classes >= 0 && classes <= 4 {
+			policy.MinCharacterClasses = classes
+		}
+
+		if path := strings.TrimSpace(os.Getenv("PASSWORD_BREACHED_LIST_FILE")); path != "" {
+			breached, err := loadBreachedPasswords(path)
+			if err != nil {
+				log.Printf("Error loading breached password list %s: %v", path, err)
+			} else {
+				policy.breached = breached
+				log.Printf("Loaded %d breached passwords from %s", len(breached), path)
+			}
+		}
+
+		passwordPolicy = policy
+	})
+	return passwordPolicy
+}
+
+// ValidatePassword checks a new password against the configured policy.
+// maxLength is the longest password the password hasher accepts (auth.MaxPasswordLength).
+func ValidatePassword(password string, maxLength int) (bool, string) {
+	policy := GetPasswordPolicy()
+	maxLength = min(maxLength, policy.MaxLength)
+
+	if len(password) < policy.MinLength {
+		return false, fmt.Sprintf("Password must be at least %d characters", policy.MinLength)
+	}
+
+	if len(password) > maxLength {
+		return false, fmt.Sprintf("Password must be at most %d characters", maxLength)
+	}
+
+	if policy.MinCharacterClasses > 0 && countCharacterClasses(password) < policy.MinCharacterClasses {
+		return false, fmt.Sprintf("Password must mix at least %d of: lowercase letters, uppercase letters, digits and symbols", policy.MinCharacterClasses)
+	}
+
+	if _, found := policy.breached[strings.ToLower(password)]; found {
+		return false, "This password appears in a list of leaked passwords. Please choose another one"
+	}
+
+	return true, ""
+}
+
+func countCharacterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// loadBreachedPasswords reads one password per line; matching is case-insensitive
+func loadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	breached := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || len(line) > MaxPasswordLength {
+			continue
+		}
+		breached[strings.ToLower(line)] = struct{}{}
+	}
+
+	return breached, scanner.Err()
+}
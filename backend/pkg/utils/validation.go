@@ -3,6 +3,7 @@ package utils
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
@@ -37,15 +38,27 @@ func SanitizeString(input string, maxLength int) string {
 	return input
 }
 
-func ValidatePassword(password string) (bool, string) {
-	if len(password) < 6 {
-		return false, "Password must be at least 6 characters"
+// SanitizeText cleans free text written by players, like profile comments. Unlike SanitizeString it keeps
+// line breaks and punctuation; control characters and angle brackets are removed and at most
+// maxLength bytes are kept without cutting a character in half.
+func SanitizeText(input string, maxLength int) string {
+	input = strings.TrimSpace(strings.ReplaceAll(input, "\r\n", "\n"))
+
+	var builder strings.Builder
+	for _, r := range input {
+		if (r >= 32 || r == '\n') && r != 127 && r != '<' && r != '>' && r != utf8.RuneError {
+			builder.WriteRune(r)
+		}
 	}
-	
-	if len(password) > 128 {
-		return false, "Password must be at most 128 characters"
+	input = builder.String()
+
+	if len(input) > maxLength {
+		cut := maxLength
+		for cut > 0 && !utf8.RuneStart(input[cut]) {
+			cut--
+		}
+		input = input[:cut]
 	}
-	
-	return true, ""
-}
 
+	return input
+}
@@ -0,0 +1,164 @@
+'use client'
+
+import { useState, useEffect, useRef, Suspense } from 'react'
+import Link from 'next/link'
+import { useSearchParams } from 'next/navigation'
+import { api } from '../../../services/api'
+
+interface EmailChangeStatus {
+  newEmail: string
+  oldConfirmed: boolean
+  newConfirmed: boolean
+  applyAfter?: number
+  appliedAt?: number
+  confirmedEmail?: string
+}
+
+function ConfirmEmailChange() {
+  const searchParams = useSearchParams()
+  const token = searchParams.get('token') || ''
+  const cancel = searchParams.get('cancel') === '1'
+
+  const [message, setMessage] = useState('')
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(!cancel)
+  const [cancelled, setCancelled] = useState(false)
+  const [pending, setPending] = useState<EmailChangeStatus | null>(null)
+  const looked = useRef(false)
+
+  // Only look the change up here: mail scanners open links too, so confirming waits for a click
+  useEffect(() => {
+    if (cancel || looked.current) {
+      return
+    }
+    looked.current = true
+
+    if (!token) {
+      setError('Invalid or expired confirmation link')
+      setLoading(false)
+      return
+    }
+
+    api.post<{ data: EmailChangeStatus }>('/account/email/lookup', { token }, { public: true })
+      .then(response => setPending(response.data))
+      .catch((err: any) => setError(err.message || 'Error loading email change'))
+      .finally(() => setLoading(false))
+  }, [token, cancel])
+
+  const handleConfirm = async () => {
+    setLoading(true)
+    setError('')
+    try {
+      const response = await api.post<{ message: string, data: EmailChangeStatus }>('/account/email/confirm', { token }, { public: true })
+      setMessage(response.message)
+      setPending(null)
+    } catch (err: any) {
+      setError(err.message || 'Error confirming email change')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const handleCancel = async () => {
+    setLoading(true)
+    setError('')
+    try {
+      await api.post('/account/email/cancel', { token }, { public: true })
+      setCancelled(true)
+    } catch (err: any) {
+      setError(err.message || 'Error cancelling email change')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <div>
+        <main className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+          <div className="max-w-2xl mx-auto">
+            {/* Header */}
+            <div className="text-center mb-8">
+              <h1 className="text-3xl sm:text-4xl font-bold mb-2">
+                <span className="text-[#ffd700]">Email</span>
+                <span className="text-[#3b82f6]"> Change</span>
+              </h1>
+            </div>
+
+            <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 sm:p-8 shadow-2xl ring-2 ring-[#ffd700]/10">
+              {error && (
+                <div className="mb-4 p-3 bg-red-900/30 border border-red-700 rounded text-red-300 text-sm">
+                  {error}
+                </div>
+              )}
+
+              {loading && <p className="text-[#d0d0d0] text-sm">Please wait...</p>}
+
+              {!loading && message && (
+                <div className="p-3 bg-green-900/30 border border-green-700 rounded text-green-300 text-sm">
+                  {message}
+                </div>
+              )}
+
+              {!loading && !cancel && pending && (
+                <div className="space-y-5">
+                  <p className="text-[#d0d0d0] text-sm">
+                    Confirm changing your account email to <span className="text-[#ffd700] font-medium">{pending.newEmail}</span>?
+                    If you did not request it, do not confirm and change your password instead.
+                  </p>
+                  {pending.confirmedEmail && (
+                    <p className="text-[#888] text-sm">
+                      This link confirms the address {pending.confirmedEmail}. Both the current and the new address have to confirm.
+                    </p>
+                  )}
+                  <button
+                    onClick={handleConfirm}
+                    className="w-full bg-[#3b82f6] hover:bg-[#2563eb] text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg"
+                  >
+                    Confirm Email Change
+                  </button>
+                </div>
+              )}
+
+              {!loading && cancel && !cancelled && (
+                <div className="space-y-5">
+                  <p className="text-[#d0d0d0] text-sm">
+                    Cancel the requested change of your account email? If you did not request it, change your password afterwards.
+                  </p>
+                  <button
+                    onClick={handleCancel}
+                    className="w-full bg-red-700 hover:bg-red-600 text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg"
+                  >
+                    Cancel Email Change
+                  </button>
+                </div>
+              )}
+
+              {cancelled && (
+                <div className="p-3 bg-green-900/30 border border-green-700 rounded text-green-300 text-sm">
+                  The email change has been cancelled. Your account keeps its current email.
+                </div>
+              )}
+
+              <div className="mt-6 pt-6 border-t border-[#404040]/40">
+                <Link
+                  href="/account"
+                  className="inline-flex items-center gap-2 text-[#d0d0d0] hover:text-[#ffd700] transition-colors text-sm"
+                >
+                  <span>←</span>
+                  <span>Back to Account</span>
+                </Link>
+              </div>
+            </div>
+          </div>
+        </main>
+    </div>
+  )
+}
+
+export default function ConfirmEmailChangePage() {
+  return (
+    <Suspense fallback={null}>
+      <ConfirmEmailChange />
+    </Suspense>
+  )
+}
@@ -6,6 +6,10 @@ import { api } from '../services/api'
 import { useAuth } from '../contexts/AuthContext'
 import { useServerName } from '../hooks/useServerName'
 import { makeOutfit } from '../utils/outfit'
+import VerificationBanner from '../components/account/VerificationBanner'
+import DeleteCharacterModal from '../components/account/DeleteCharacterModal'
+import RenameCharacterModal from '../components/account/RenameCharacterModal'
+import EditCharacterProfileModal from '../components/account/EditCharacterProfileModal'
 import type { Ticket, AccountInfo, AccountApiResponse } from '../types/account'
 import type { Character, CharactersApiResponse } from '../types/character'
 
@@ -31,6 +35,14 @@ export default function AccountManagementPage() {
 	const [loading, setLoading] = useState(true)
 	const [userLoading, setUserLoading] = useState(true)
 	const [error, setError] = useState('')
+	const [actionError, setActionError] = useState('')
+	const [deletingCharacter, setDeletingCharacter] = useState<string | null>(null)
+	const [isDeletingCharacter, setIsDeletingCharacter] = useState(false)
+	const [busyCharacter, setBusyCharacter] = useState<string | null>(null)
+	const [renamingCharacter, setRenamingCharacter] = useState<string | null>(null)
+	const [editingProfile, setEditingProfile] = useState<string | null>(null)
+	const [isRenamingCharacter, setIsRenamingCharacter] = useState(false)
+	const [servicePrices, setServicePrices] = useState({ renamePrice: 0, sexChangePrice: 0 })
 	const serverName = useServerName()
 
 	const fetchAccountInfo = useCallback(async () => {
@@ -78,8 +90,64 @@ export default function AccountManagementPage() {
 	useEffect(() => {
 		fetchAccountInfo()
 		fetchCharacters()
+		api.get<{ data: { renamePrice: number; sexChangePrice: number } }>('/account/character-services')
+			.then(response => setServicePrices(response.data))
+			.catch(() => {})
 	}, [fetchAccountInfo, fetchCharacters])
 
+	const handleDeleteCharacter = useCallback(async (password: string) => {
+		if (!deletingCharacter) return
+		setIsDeletingCharacter(true)
+		try {
+			await api.delete(`/characters/${encodeURIComponent(deletingCharacter)}`, { password })
+			setDeletingCharacter(null)
+			await fetchCharacters()
+		} finally {
+			setIsDeletingCharacter(false)
+		}
+	}, [deletingCharacter, fetchCharacters])
+
+	const runCharacterAction = useCallback(async (name: string, action: () => Promise<unknown>) => {
+		setBusyCharacter(name)
+		setActionError('')
+		try {
+			await action()
+			await fetchCharacters()
+		} catch (err: any) {
+			setActionError(err.message || 'Failed to update character')
+		} finally {
+			setBusyCharacter(null)
+		}
+	}, [fetchCharacters])
+
+	const handleCancelCharacterDeletion = useCallback((name: string) => {
+		runCharacterAction(name, () => api.post(`/characters/${encodeURIComponent(name)}/cancel-deletion`, {}))
+	}, [runCharacterAction])
+
+	const handleRenameCharacter = useCallback(async (newName: string) => {
+		if (!renamingCharacter) return
+		setIsRenamingCharacter(true)
+		try {
+			await api.post(`/characters/${encodeURIComponent(renamingCharacter)}/rename`, { newName })
+			setRenamingCharacter(null)
+			await fetchCharacters()
+		} finally {
+			setIsRenamingCharacter(false)
+		}
+	}, [renamingCharacter, fetchCharacters])
+
+	const handleChangeSex = useCallback((name: string) => {
+		const cost = servicePrices.sexChangePrice > 0 ? ` This costs ${servicePrices.sexChangePrice} coins.` : ''
+		if (!confirm(`Change the sex of ${name}? The outfit is reset to the default one.${cost}`)) {
+			return
+		}
+		runCharacterAction(name, () => api.post(`/characters/${encodeURIComponent(name)}/change-sex`, {}))
+	}, [runCharacterAction, servicePrices.sexChangePrice])
+
+	const handleToggleHidden = useCallback((char: Character) => {
+		runCharacterAction(char.name, () => api.put(`/characters/${encodeURIComponent(char.name)}/visibility`, { hidden: !char.hidden }))
+	}, [runCharacterAction])
+
 	const handleLogout = useCallback(() => {
 		logout()
 	}, [])
@@ -98,6 +166,10 @@ export default function AccountManagementPage() {
 					</p>
 				</div>
 
+				{!userLoading && user.status === 'unverified' && (
+					<VerificationBanner email={user.email} />
+				)}
+
 				<div className="grid grid-cols-1 lg:grid-cols-3 gap-6">
 					{/* Left Column - Main Content */}
 					<div className="lg:col-span-2 space-y-6">
@@ -212,6 +284,11 @@ export default function AccountManagementPage() {
 								</div>
 							) : (
 								<div className="overflow-x-auto">
+									{actionError && (
+										<div className="mb-3 bg-red-900/30 border border-red-700 rounded-lg p-3 text-red-300 text-sm">
+											{actionError}
+										</div>
+									)}
 									<table className="w-full">
 										<thead>
 											<tr className="border-b border-[#404040]/60">
@@ -222,6 +299,7 @@ export default function AccountManagementPage() {
 												<th className="text-left text-[#ffd700] text-sm font-bold py-3 px-2">Level</th>
 												<th className="text-left text-[#ffd700] text-sm font-bold py-3 px-2">World</th>
 												<th className="text-left text-[#ffd700] text-sm font-bold py-3 px-2">Status</th>
+												<th className="text-right text-[#ffd700] text-sm font-bold py-3 px-2">Actions</th>
 											</tr>
 										</thead>
 										<tbody>
@@ -267,6 +345,60 @@ export default function AccountManagementPage() {
 														>
 															{char.status === 'online' ? 'Online' : 'Offline'}
 														</span>
+														{char.hidden && (
+															<span className="ml-1 text-xs font-bold px-2 py-1 rounded bg-[#404040]/50 text-[#b0b0b0]">Hidden</span>
+														)}
+														{char.deletionScheduledAt && (
+															<span className="block mt-1 text-xs text-red-400">
+																Deleted on {new Date(char.deletionScheduledAt * 1000).toLocaleDateString()}
+															</span>
+														)}
+													</td>
+													<td className="py-3 px-2 text-right whitespace-nowrap">
+														<button
+															onClick={() => setEditingProfile(char.name)}
+															className="mr-2 text-xs font-bold py-1 px-3 rounded bg-[#404040] hover:bg-[#505050] text-white transition-all"
+														>
+															Edit
+														</button>
+														<button
+															onClick={() => setRenamingCharacter(char.name)}
+															disabled={busyCharacter === char.name || char.status === 'online'}
+															className="mr-2 text-xs font-bold py-1 px-3 rounded bg-[#404040] hover:bg-[#505050] disabled:opacity-50 text-white transition-all"
+														>
+															Rename
+														</button>
+														<button
+															onClick={() => handleChangeSex(char.name)}
+															disabled={busyCharacter === char.name || char.status === 'online'}
+															className="mr-2 text-xs font-bold py-1 px-3 rounded bg-[#404040] hover:bg-[#505050] disabled:opacity-50 text-white transition-all"
+														>
+															Change Sex
+														</button>
+														<button
+															onClick={() => handleToggleHidden(char)}
+															disabled={busyCharacter === char.name}
+															className="text-xs font-bold py-1 px-3 rounded bg-[#404040] hover:bg-[#505050] disabled:opacity-50 text-white transition-all"
+														>
+															{char.hidden ? 'Show' : 'Hide'}
+														</button>
+														{char.deletionScheduledAt ? (
+															<button
+																onClick={() => handleCancelCharacterDeletion(char.name)}
+																disabled={busyCharacter === char.name}
+																className="ml-2 text-xs font-bold py-1 px-3 rounded bg-green-700 hover:bg-green-600 disabled:opacity-50 text-white transition-all"
+															>
+																Undelete
+															</button>
+														) : (
+															<button
+																onClick={() => setDeletingCharacter(char.name)}
+																disabled={busyCharacter === char.name}
+																className="ml-2 text-xs font-bold py-1 px-3 rounded bg-red-700 hover:bg-red-600 disabled:opacity-50 text-white transition-all"
+															>
+																Delete
+															</button>
+														)}
 													</td>
 												</tr>
 											))}
@@ -329,7 +461,7 @@ export default function AccountManagementPage() {
 								>
 									Manage Account
 								</Link>
-								{user.page_access === 1 && (
+								{(user.page_access === 1 || (user.permissions?.length ?? 0) > 0) && (
 									<Link
 										href="/admin"
 										className="block w-full bg-yellow-600 hover:bg-yellow-700 text-white text-center font-bold py-2.5 px-4 rounded-lg transition-all mb-2 shadow-lg border-2 border-yellow-500"
@@ -401,6 +533,27 @@ export default function AccountManagementPage() {
 					</Link>
 				</div>
 			</main>
+
+			<DeleteCharacterModal
+				characterName={deletingCharacter}
+				onClose={() => setDeletingCharacter(null)}
+				onConfirm={handleDeleteCharacter}
+				isDeleting={isDeletingCharacter}
+			/>
+
+			<RenameCharacterModal
+				characterName={renamingCharacter}
+				price={servicePrices.renamePrice}
+				onClose={() => setRenamingCharacter(null)}
+				onConfirm={handleRenameCharacter}
+				isRenaming={isRenamingCharacter}
+			/>
+
+			<EditCharacterProfileModal
+				characterName={editingProfile}
+				onClose={() => setEditingProfile(null)}
+				onSaved={fetchCharacters}
+			/>
 		</div>
 	)
 }
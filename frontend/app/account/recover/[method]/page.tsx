@@ -4,6 +4,7 @@ import { useState, useEffect } from 'react'
 import Link from 'next/link'
 import { useParams, useRouter } from 'next/navigation'
 import { useAuth } from '../../../contexts/AuthContext'
+import { api } from '../../../services/api'
 
 type RecoveryMethod = 'character' | 'username' | 'neither'
 
@@ -73,7 +74,11 @@ export default function RecoverAccountFormPage() {
     setLoading(true)
 
     try {
-      await new Promise(resolve => setTimeout(resolve, 1500))
+      await api.post('/account/recover/request', {
+        email: formData.email,
+        characterName: method === 'character' ? formData.characterName : undefined,
+        username: method === 'username' ? formData.username : undefined,
+      }, { public: true })
 
       setSuccess(true)
       setFormData({
@@ -81,8 +86,8 @@ export default function RecoverAccountFormPage() {
         characterName: '',
         username: '',
       })
-    } catch (err) {
-      setError('Error sending recovery request. Please try again.')
+    } catch (err: any) {
+      setError(err.message || 'Error sending recovery request. Please try again.')
     } finally {
       setLoading(false)
     }
@@ -129,6 +129,13 @@ export default function RecoverAccountPage() {
                 </button>
               </form>
 
+              <p className="mt-6 text-[#d0d0d0] text-sm">
+                Have a recovery key?{' '}
+                <Link href="/account/recover/reset" className="text-[#3b82f6] hover:text-[#ffd700] transition-colors">
+                  Reset your password with it
+                </Link>
+              </p>
+
               {/* Back Button */}
               <div className="mt-6 pt-6 border-t border-[#404040]/40">
                 <Link
@@ -0,0 +1,251 @@
+'use client'
+
+import { useState, useEffect, Suspense } from 'react'
+import Link from 'next/link'
+import { useRouter, useSearchParams } from 'next/navigation'
+import { useAuth } from '../../../contexts/AuthContext'
+import { api } from '../../../services/api'
+
+function ResetPasswordForm() {
+  const router = useRouter()
+  const searchParams = useSearchParams()
+  const { isAuthenticated, isLoading } = useAuth()
+  const token = searchParams.get('token') || ''
+  const useRecoveryKey = token === ''
+
+  const [formData, setFormData] = useState({
+    email: '',
+    recoveryKey: '',
+    password: '',
+    confirmPassword: '',
+  })
+  const [error, setError] = useState('')
+  const [success, setSuccess] = useState(false)
+  const [loading, setLoading] = useState(false)
+
+  useEffect(() => {
+    if (isAuthenticated && !isLoading) {
+      router.push('/account')
+    }
+  }, [isAuthenticated, isLoading, router])
+
+  const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const { name, value } = e.target
+    setFormData(prev => ({
+      ...prev,
+      [name]: value
+    }))
+  }
+
+  const validateForm = () => {
+    if (useRecoveryKey) {
+      if (!formData.email || !formData.recoveryKey) {
+        setError('Email and recovery key are required')
+        return false
+      }
+    }
+
+    if (formData.password.length < 6) {
+      setError('Password must be at least 6 characters')
+      return false
+    }
+
+    if (formData.password !== formData.confirmPassword) {
+      setError('Passwords do not match')
+      return false
+    }
+
+    return true
+  }
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+
+    if (!validateForm()) {
+      return
+    }
+
+    setLoading(true)
+
+    try {
+      if (useRecoveryKey) {
+        await api.post('/account/recover/key', {
+          email: formData.email,
+          recoveryKey: formData.recoveryKey,
+          password: formData.password,
+        }, { public: true })
+      } else {
+        await api.post('/account/recover/reset', {
+          token,
+          password: formData.password,
+        }, { public: true })
+      }
+
+      setSuccess(true)
+      setFormData({
+        email: '',
+        recoveryKey: '',
+        password: '',
+        confirmPassword: '',
+      })
+    } catch (err: any) {
+      setError(err.message || 'Error resetting password. Please try again.')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  if (isLoading || isAuthenticated) {
+    return null
+  }
+
+  const inputClassName = "w-full bg-[#1a1a1a] border-2 border-[#404040]/60 rounded-lg px-4 py-3 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+
+  return (
+    <div>
+        <main className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+          <div className="max-w-2xl mx-auto">
+            {/* Header */}
+            <div className="text-center mb-8">
+              <h1 className="text-3xl sm:text-4xl font-bold mb-2">
+                <span className="text-[#ffd700]">Reset</span>
+                <span className="text-[#3b82f6]"> Password</span>
+              </h1>
+              <p className="text-[#d0d0d0] text-sm">
+                {useRecoveryKey
+                  ? 'Enter your email, your recovery key and a new password.'
+                  : 'Choose a new password for your account.'}
+              </p>
+            </div>
+
+            <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 sm:p-8 shadow-2xl ring-2 ring-[#ffd700]/10">
+              {error && (
+                <div className="mb-4 p-3 bg-red-900/30 border border-red-700 rounded text-red-300 text-sm">
+                  {error}
+                </div>
+              )}
+
+              {success ? (
+                <div className="space-y-5">
+                  <div className="p-3 bg-green-900/30 border border-green-700 rounded text-green-300 text-sm">
+                    Your password has been updated. You can now log in with your new password.
+                  </div>
+                  <Link
+                    href="/login"
+                    className="block w-full text-center bg-[#3b82f6] hover:bg-[#2563eb] text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg"
+                  >
+                    Go to Login
+                  </Link>
+                </div>
+              ) : (
+                <form onSubmit={handleSubmit} className="space-y-5">
+                  {useRecoveryKey && (
+                    <>
+                      <div>
+                        <label htmlFor="email" className="block text-[#e0e0e0] text-sm font-medium mb-2">
+                          Email *
+                        </label>
+                        <input
+                          id="email"
+                          name="email"
+                          type="email"
+                          value={formData.email}
+                          onChange={handleChange}
+                          className={inputClassName}
+                          placeholder="Enter your email address"
+                          disabled={loading}
+                          required
+                        />
+                      </div>
+
+                      <div>
+                        <label htmlFor="recoveryKey" className="block text-[#e0e0e0] text-sm font-medium mb-2">
+                          Recovery Key *
+                        </label>
+                        <input
+                          id="recoveryKey"
+                          name="recoveryKey"
+                          type="text"
+                          value={formData.recoveryKey}
+                          onChange={handleChange}
+                          className={`${inputClassName} font-mono uppercase`}
+                          placeholder="XXXXX-XXXXX-XXXXX-XXXXX"
+                          autoComplete="off"
+                          disabled={loading}
+                          required
+                        />
+                      </div>
+                    </>
+                  )}
+
+                  <div>
+                    <label htmlFor="password" className="block text-[#e0e0e0] text-sm font-medium mb-2">
+                      New Password *
+                    </label>
+                    <input
+                      id="password"
+                      name="password"
+                      type="password"
+                      value={formData.password}
+                      onChange={handleChange}
+                      className={inputClassName}
+                      placeholder="Enter your new password"
+                      autoComplete="new-password"
+                      disabled={loading}
+                      required
+                    />
+                  </div>
+
+                  <div>
+                    <label htmlFor="confirmPassword" className="block text-[#e0e0e0] text-sm font-medium mb-2">
+                      Confirm New Password *
+                    </label>
+                    <input
+                      id="confirmPassword"
+                      name="confirmPassword"
+                      type="password"
+                      value={formData.confirmPassword}
+                      onChange={handleChange}
+                      className={inputClassName}
+                      placeholder="Repeat your new password"
+                      autoComplete="new-password"
+                      disabled={loading}
+                      required
+                    />
+                  </div>
+
+                  <button
+                    type="submit"
+                    disabled={loading}
+                    className="w-full bg-[#3b82f6] hover:bg-[#2563eb] text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg hover:shadow-xl transform hover:scale-[1.02] disabled:opacity-50 disabled:cursor-not-allowed"
+                  >
+                    {loading ? 'Updating password...' : 'Reset Password'}
+                  </button>
+                </form>
+              )}
+
+              {/* Back Button */}
+              <div className="mt-6 pt-6 border-t border-[#404040]/40">
+                <Link
+                  href="/account/recover"
+                  className="inline-flex items-center gap-2 text-[#d0d0d0] hover:text-[#ffd700] transition-colors text-sm"
+                >
+                  <span>←</span>
+                  <span>Back to Recovery Options</span>
+                </Link>
+              </div>
+            </div>
+          </div>
+        </main>
+    </div>
+  )
+}
+
+export default function ResetPasswordPage() {
+  return (
+    <Suspense fallback={null}>
+      <ResetPasswordForm />
+    </Suspense>
+  )
+}
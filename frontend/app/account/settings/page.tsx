@@ -8,15 +8,25 @@ import DeleteAccountModal from '../../components/account/DeleteAccountModal'
 import DeletionWarningBanner from '../../components/account/DeletionWarningBanner'
 import CancelDeletionModal from '../../components/account/CancelDeletionModal'
 import TwoFactorAuth from '../../components/account/TwoFactorAuth'
+import ActiveSessions from '../../components/account/ActiveSessions'
+import ChangeEmail from '../../components/account/ChangeEmail'
+import ChangePassword from '../../components/account/ChangePassword'
+import Passkeys from '../../components/account/Passkeys'
+import CharacterBazaar from '../../components/account/CharacterBazaar'
 
-type TabType = 'general' | 'products' | 'history' | '2fa'
+type TabType = 'general' | 'products' | 'history' | 'password' | 'email' | '2fa' | 'passkeys' | 'sessions' | 'bazaar'
 
 // Constants moved outside component to avoid recreation
 const TABS = [
     { id: 'general' as TabType, label: 'General Information' },
     { id: 'products' as TabType, label: 'Products Available' },
     { id: 'history' as TabType, label: 'History' },
+    { id: 'password' as TabType, label: 'Password' },
+    { id: 'email' as TabType, label: 'Email Address' },
     { id: '2fa' as TabType, label: 'Two-Factor Authentication' },
+    { id: 'passkeys' as TabType, label: 'Passkeys' },
+    { id: 'sessions' as TabType, label: 'Sessions' },
+    { id: 'bazaar' as TabType, label: 'Character Bazaar' },
 ] as const
 
 export default function AccountSettingsPage() {
@@ -59,6 +69,13 @@ export default function AccountSettingsPage() {
         fetchAccountInfo()
     }, [fetchAccountInfo])
 
+    useEffect(() => {
+        const tab = new URLSearchParams(window.location.search).get('tab')
+        if (tab && TABS.some(t => t.id === tab)) {
+            setActiveTab(tab as TabType)
+        }
+    }, [])
+
     const handleDeleteAccount = useCallback(async (password: string) => {
         setIsDeleting(true)
         try {
@@ -348,8 +365,23 @@ export default function AccountSettingsPage() {
                         </div>
                     )}
 
+                    {/* Password Tab */}
+                    {activeTab === 'password' && <ChangePassword />}
+
+                    {/* Email Address Tab */}
+                    {activeTab === 'email' && <ChangeEmail currentEmail={userData.email} />}
+
                     {/* Two-Factor Authentication Tab */}
                     {activeTab === '2fa' && <TwoFactorAuth />}
+
+                    {/* Passkeys Tab */}
+                    {activeTab === 'passkeys' && <Passkeys />}
+
+                    {/* Sessions Tab */}
+                    {activeTab === 'sessions' && <ActiveSessions />}
+
+                    {/* Character Bazaar Tab */}
+                    {activeTab === 'bazaar' && <CharacterBazaar />}
                 </div>
 
                 {/* Back Link */}
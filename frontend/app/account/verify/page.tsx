@@ -0,0 +1,86 @@
+'use client'
+
+import { useState, useEffect, useRef, Suspense } from 'react'
+import Link from 'next/link'
+import { useSearchParams } from 'next/navigation'
+import { api } from '../../services/api'
+
+function VerifyEmail() {
+  const searchParams = useSearchParams()
+  const id = Number(searchParams.get('id') || 0)
+  const expires = Number(searchParams.get('expires') || 0)
+  const signature = searchParams.get('signature') || ''
+
+  const [message, setMessage] = useState('')
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(true)
+  const verified = useRef(false)
+
+  useEffect(() => {
+    if (verified.current) {
+      return
+    }
+    verified.current = true
+
+    if (!id || !expires || !signature) {
+      setError('Invalid verification link')
+      setLoading(false)
+      return
+    }
+
+    api.post<{ message: string }>('/account/verify', { id, expires, signature }, { public: true })
+      .then(response => setMessage(response.message))
+      .catch((err: any) => setError(err.message || 'Error verifying email'))
+      .finally(() => setLoading(false))
+  }, [id, expires, signature])
+
+  return (
+    <div>
+        <main className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+          <div className="max-w-2xl mx-auto">
+            {/* Header */}
+            <div className="text-center mb-8">
+              <h1 className="text-3xl sm:text-4xl font-bold mb-2">
+                <span className="text-[#ffd700]">Account</span>
+                <span className="text-[#3b82f6]"> Activation</span>
+              </h1>
+            </div>
+
+            <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 sm:p-8 shadow-2xl ring-2 ring-[#ffd700]/10">
+              {error && (
+                <div className="mb-4 p-3 bg-red-900/30 border border-red-700 rounded text-red-300 text-sm">
+                  {error}
+                </div>
+              )}
+
+              {loading && <p className="text-[#d0d0d0] text-sm">Verifying your email address...</p>}
+
+              {!loading && message && (
+                <div className="p-3 bg-green-900/30 border border-green-700 rounded text-green-300 text-sm">
+                  {message}
+                </div>
+              )}
+
+              <div className="mt-6 pt-6 border-t border-[#404040]/40">
+                <Link
+                  href="/account"
+                  className="inline-flex items-center gap-2 text-[#d0d0d0] hover:text-[#ffd700] transition-colors text-sm"
+                >
+                  <span>←</span>
+                  <span>Back to Account</span>
+                </Link>
+              </div>
+            </div>
+          </div>
+        </main>
+    </div>
+  )
+}
+
+export default function VerifyEmailPage() {
+  return (
+    <Suspense fallback={null}>
+      <VerifyEmail />
+    </Suspense>
+  )
+}
@@ -4,7 +4,7 @@ import { useState, useEffect, useCallback } from 'react'
 import { useRouter } from 'next/navigation'
 import { api } from '../../services/api'
 import type { ApiResponse } from '../../types/account'
-import type { AdminAccount, AdminAccountsResponse } from '../../types/admin'
+import type { AdminAccount, AdminAccountsResponse, AdminRole, AccountRoles } from '../../types/admin'
 
 const handleAdminError = (err: any, router: { replace: (path: string) => void }): boolean => {
 	const status = err.status || err.response?.status
@@ -29,8 +29,11 @@ function EditAccountModal({ account, isOpen, onClose, onSave }: EditAccountModal
 		coins: 0,
 		coinsTransferable: 0,
 		status: '',
-		isAdmin: false,
 	})
+	// Roles are only editable by accounts with the roles.manage permission; null hides the section
+	const [availableRoles, setAvailableRoles] = useState<AdminRole[] | null>(null)
+	const [roles, setRoles] = useState<string[]>([])
+	const [initialRoles, setInitialRoles] = useState<string[]>([])
 	const [saving, setSaving] = useState(false)
 	const [error, setError] = useState('')
 
@@ -41,12 +44,27 @@ function EditAccountModal({ account, isOpen, onClose, onSave }: EditAccountModal
 				coins: account.coins,
 				coinsTransferable: account.coinsTransferable,
 				status: account.status,
-				isAdmin: account.isAdmin,
 			})
 			setError('')
+
+			setAvailableRoles(null)
+			Promise.all([
+				api.get<ApiResponse<AdminRole[]>>('/admin/roles'),
+				api.get<ApiResponse<AccountRoles>>(`/admin/account/roles?id=${account.id}`),
+			])
+				.then(([rolesResponse, accountRolesResponse]) => {
+					setAvailableRoles(rolesResponse?.data || [])
+					setRoles(accountRolesResponse?.data?.roles || [])
+					setInitialRoles(accountRolesResponse?.data?.roles || [])
+				})
+				.catch(() => setAvailableRoles(null))
 		}
 	}, [account])
 
+	const toggleRole = (role: string) => {
+		setRoles(prev => prev.includes(role) ? prev.filter(r => r !== role) : [...prev, role])
+	}
+
 	if (!isOpen || !account) return null
 
 	const handleSubmit = async (e: React.FormEvent) => {
@@ -55,6 +73,10 @@ function EditAccountModal({ account, isOpen, onClose, onSave }: EditAccountModal
 		setError('')
 		try {
 			await onSave(account.id, formData)
+			const rolesChanged = roles.length !== initialRoles.length || roles.some(role => !initialRoles.includes(role))
+			if (availableRoles && rolesChanged) {
+				await api.put(`/admin/account/roles?id=${account.id}`, { roles })
+			}
 			onClose()
 		} catch (err: any) {
 			setError(err.message || 'Error saving account')
@@ -148,18 +170,30 @@ function EditAccountModal({ account, isOpen, onClose, onSave }: EditAccountModal
 							</select>
 						</div>
 
-						<div className="flex items-center gap-2">
-							<input
-								type="checkbox"
-								id="isAdmin"
-								checked={formData.isAdmin}
-								onChange={(e) => setFormData({ ...formData, isAdmin: e.target.checked })}
-								className="w-4 h-4 text-[#ffd700] bg-[#252525] border-[#404040] rounded focus:ring-[#ffd700]"
-							/>
-							<label htmlFor="isAdmin" className="text-[#e0e0e0] font-semibold">
-								Admin Access
-							</label>
-						</div>
+						{availableRoles && (
+							<div>
+								<label className="block text-[#e0e0e0] font-semibold mb-2">
+									Staff Roles
+								</label>
+								<div className="space-y-2">
+									{availableRoles.map((role) => (
+										<div key={role.name} className="flex items-center gap-2">
+											<input
+												type="checkbox"
+												id={`role-${role.name}`}
+												checked={roles.includes(role.name)}
+												onChange={() => toggleRole(role.name)}
+												className="w-4 h-4 text-[#ffd700] bg-[#252525] border-[#404040] rounded focus:ring-[#ffd700]"
+											/>
+											<label htmlFor={`role-${role.name}`} className="text-[#e0e0e0]">
+												{role.label}
+												<span className="text-xs text-[#888] ml-2">{role.permissions.join(', ')}</span>
+											</label>
+										</div>
+									))}
+								</div>
+							</div>
+						)}
 
 						<div className="flex gap-4 justify-end pt-4">
 							<button
@@ -353,7 +387,6 @@ export default function ManageAccountsPage() {
 			if (data.coins !== undefined) updateData.coins = data.coins
 			if (data.coinsTransferable !== undefined) updateData.coinsTransferable = data.coinsTransferable
 			if (data.status !== undefined) updateData.status = parseInt(data.status)
-			if (data.isAdmin !== undefined) updateData.isAdmin = data.isAdmin
 
 			await api.put<ApiResponse<any>>(`/admin/account?id=${accountId}`, updateData)
 			// Refresh accounts list
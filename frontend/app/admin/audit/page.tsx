@@ -0,0 +1,253 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { useRouter } from 'next/navigation'
+import { api } from '../../services/api'
+import type { ApiResponse } from '../../types/account'
+import type { AuditLogEntry, AuditLogResponse } from '../../types/admin'
+
+const EMPTY_FILTERS = { actor: '', action: '', targetType: '', targetId: '', from: '', to: '' }
+
+const formatValue = (value: unknown): string => {
+	if (value === undefined || value === null) return '—'
+	if (typeof value === 'string') return value.length > 200 ? value.slice(0, 200) + '…' : value
+	return JSON.stringify(value)
+}
+
+function ChangesList({ entry }: { entry: AuditLogEntry }) {
+	const fields = Object.entries(entry.changes || {})
+	if (fields.length === 0) {
+		return <span className="text-[#888]">—</span>
+	}
+
+	return (
+		<ul className="space-y-1">
+			{fields.map(([field, change]) => (
+				<li key={field} className="text-xs">
+					<span className="text-[#ffd700] font-semibold">{field}</span>{' '}
+					<span className="text-red-400 line-through break-all">{formatValue(change.before)}</span>{' '}
+					→ <span className="text-green-400 break-all">{formatValue(change.after)}</span>
+				</li>
+			))}
+		</ul>
+	)
+}
+
+export default function AuditLogPage() {
+	const router = useRouter()
+	const [entries, setEntries] = useState<AuditLogEntry[]>([])
+	const [loading, setLoading] = useState(true)
+	const [error, setError] = useState('')
+	const [filters, setFilters] = useState(EMPTY_FILTERS)
+	const [appliedFilters, setAppliedFilters] = useState(EMPTY_FILTERS)
+	const [page, setPage] = useState(1)
+	const [limit] = useState(50)
+	const [totalPages, setTotalPages] = useState(1)
+	const [total, setTotal] = useState(0)
+
+	const fetchEntries = useCallback(async () => {
+		setLoading(true)
+		setError('')
+		try {
+			const params = new URLSearchParams({
+				page: page.toString(),
+				limit: limit.toString(),
+			})
+			Object.entries(appliedFilters).forEach(([key, value]) => {
+				if (value.trim()) params.append(key, value.trim())
+			})
+
+			const response = await api.get<ApiResponse<AuditLogResponse>>(`/admin/audit?${params.toString()}`)
+			if (response && response.data) {
+				setEntries(response.data.entries)
+				setTotalPages(response.data.pagination.totalPages)
+				setTotal(response.data.pagination.total)
+			}
+		} catch (err: any) {
+			if (err.status === 404) {
+				router.replace('/not-found')
+				return
+			}
+			setError(err.message || 'Error loading audit log')
+		} finally {
+			setLoading(false)
+		}
+	}, [page, limit, appliedFilters, router])
+
+	useEffect(() => {
+		fetchEntries()
+	}, [fetchEntries])
+
+	const handleFilter = (e: React.FormEvent) => {
+		e.preventDefault()
+		setPage(1)
+		setAppliedFilters(filters)
+	}
+
+	const handleClear = () => {
+		setFilters(EMPTY_FILTERS)
+		setAppliedFilters(EMPTY_FILTERS)
+		setPage(1)
+	}
+
+	const inputClass = "px-4 py-2 bg-[#1f1f1f] border-2 border-[#404040] rounded-lg text-[#e0e0e0] focus:border-[#ffd700] focus:outline-none"
+
+	return (
+		<div className="min-h-screen">
+			<div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+				<div className="mb-8">
+					<div className="flex items-center gap-4 mb-4">
+						<div className="w-16 h-16 bg-gradient-to-br from-[#ffd700] to-[#ffed4e] rounded-xl flex items-center justify-center shadow-lg">
+							<span className="text-3xl">🧾</span>
+						</div>
+						<div>
+							<h1 className="text-4xl font-bold text-[#ffd700] mb-2">Audit Log</h1>
+							<p className="text-[#888]">Review privileged actions taken by staff</p>
+						</div>
+					</div>
+				</div>
+
+				{error && (
+					<div className="bg-red-900/30 border-2 border-red-600 rounded-lg p-4 mb-6">
+						<p className="text-red-400">{error}</p>
+					</div>
+				)}
+
+				{/* Filters */}
+				<div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 mb-6 shadow-2xl">
+					<form onSubmit={handleFilter} className="grid grid-cols-1 md:grid-cols-3 lg:grid-cols-6 gap-4">
+						<input
+							type="text"
+							value={filters.actor}
+							onChange={(e) => setFilters({ ...filters, actor: e.target.value })}
+							placeholder="Actor email or ID"
+							className={inputClass}
+						/>
+						<input
+							type="text"
+							value={filters.action}
+							onChange={(e) => setFilters({ ...filters, action: e.target.value })}
+							placeholder="Action (e.g. account.)"
+							className={inputClass}
+						/>
+						<input
+							type="text"
+							value={filters.targetType}
+							onChange={(e) => setFilters({ ...filters, targetType: e.target.value })}
+							placeholder="Target type"
+							className={inputClass}
+						/>
+						<input
+							type="text"
+							value={filters.targetId}
+							onChange={(e) => setFilters({ ...filters, targetId: e.target.value })}
+							placeholder="Target ID"
+							className={inputClass}
+						/>
+						<input
+							type="date"
+							value={filters.from}
+							onChange={(e) => setFilters({ ...filters, from: e.target.value })}
+							className={inputClass}
+						/>
+						<input
+							type="date"
+							value={filters.to}
+							onChange={(e) => setFilters({ ...filters, to: e.target.value })}
+							className={inputClass}
+						/>
+						<div className="flex gap-4 md:col-span-3 lg:col-span-6">
+							<button
+								type="submit"
+								className="px-6 py-2 bg-[#ffd700] hover:bg-[#ffd33d] rounded-lg font-bold transition-all"
+							>
+								Filter
+							</button>
+							<button
+								type="button"
+								onClick={handleClear}
+								className="px-6 py-2 bg-[#404040] hover:bg-[#505050] rounded-lg font-bold transition-all"
+							>
+								Clear
+							</button>
+						</div>
+					</form>
+				</div>
+
+				{/* Entries */}
+				<div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 shadow-2xl overflow-hidden">
+					<div className="overflow-x-auto">
+						<table className="w-full">
+							<thead className="bg-[#1f1f1f]">
+								<tr>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">Date</th>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">Actor</th>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">Action</th>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">Target</th>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">Changes</th>
+									<th className="px-6 py-4 text-left text-sm font-semibold text-[#ffd700]">IP</th>
+								</tr>
+							</thead>
+							<tbody className="divide-y divide-[#404040]">
+								{loading ? (
+									<tr>
+										<td colSpan={6} className="px-6 py-8 text-center text-[#888]">
+											Loading...
+										</td>
+									</tr>
+								) : entries.length === 0 ? (
+									<tr>
+										<td colSpan={6} className="px-6 py-8 text-center text-[#888]">
+											No entries found
+										</td>
+									</tr>
+								) : (
+									entries.map((entry) => (
+										<tr key={entry.id} className="hover:bg-[#1f1f1f]/50 transition-colors align-top">
+											<td className="px-6 py-4 text-[#888] text-sm whitespace-nowrap">{entry.createdAt}</td>
+											<td className="px-6 py-4 text-[#e0e0e0] text-sm">{entry.actorEmail || `#${entry.actorId}`}</td>
+											<td className="px-6 py-4 text-[#e0e0e0] text-sm font-mono">{entry.action}</td>
+											<td className="px-6 py-4 text-[#e0e0e0] text-sm">
+												{entry.targetType}{entry.targetId && ` #${entry.targetId}`}
+											</td>
+											<td className="px-6 py-4 max-w-xl"><ChangesList entry={entry} /></td>
+											<td className="px-6 py-4 text-[#888] text-sm font-mono">{entry.ip}</td>
+										</tr>
+									))
+								)}
+							</tbody>
+						</table>
+					</div>
+
+					{/* Pagination */}
+					{totalPages > 1 && (
+						<div className="px-6 py-4 bg-[#1f1f1f] flex items-center justify-between">
+							<div className="text-[#888] text-sm">
+								Showing {((page - 1) * limit) + 1} to {Math.min(page * limit, total)} of {total} entries
+							</div>
+							<div className="flex gap-2">
+								<button
+									onClick={() => setPage(p => Math.max(1, p - 1))}
+									disabled={page === 1}
+									className="px-4 py-2 bg-[#404040] hover:bg-[#505050] rounded-lg font-semibold transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+								>
+									Previous
+								</button>
+								<span className="px-4 py-2 text-[#e0e0e0] font-semibold">
+									Page {page} of {totalPages}
+								</span>
+								<button
+									onClick={() => setPage(p => Math.min(totalPages, p + 1))}
+									disabled={page === totalPages}
+									className="px-4 py-2 bg-[#404040] hover:bg-[#505050] rounded-lg font-semibold transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+								>
+									Next
+								</button>
+							</div>
+						</div>
+					)}
+				</div>
+			</div>
+		</div>
+	)
+}
@@ -0,0 +1,313 @@
+'use client'
+
+import { useState, useCallback, useMemo } from 'react'
+import { useRouter } from 'next/navigation'
+import { api } from '../../services/api'
+import type { ApiResponse } from '../../types/account'
+import type { BulkAssignment, BulkCondition, BulkEditResponse, BulkEditTarget } from '../../types/admin'
+
+// Mirrors the whitelists in backend/internal/handlers/bulk_edit.go
+const FIELDS: Record<BulkEditTarget, { filters: string[], editable: string[], text: string[] }> = {
+	accounts: {
+		filters: ['id', 'email', 'creation'],
+		editable: ['premdays', 'coins', 'coins_transferable'],
+		text: ['email'],
+	},
+	players: {
+		filters: ['id', 'name', 'account_id', 'vocation', 'level', 'group_id', 'town_id'],
+		editable: [
+			'vocation', 'level', 'experience', 'maglevel', 'health', 'healthmax', 'mana', 'manamax', 'soul', 'cap',
+			'town_id', 'group_id', 'balance', 'skill_fist', 'skill_club', 'skill_sword', 'skill_axe', 'skill_dist',
+			'skill_shielding', 'skill_fishing',
+		],
+		text: ['name'],
+	},
+}
+
+const CONDITION_OPS: { value: BulkCondition['op'], label: string }[] = [
+	{ value: 'eq', label: '=' },
+	{ value: 'ne', label: '≠' },
+	{ value: 'lt', label: '<' },
+	{ value: 'lte', label: '≤' },
+	{ value: 'gt', label: '>' },
+	{ value: 'gte', label: '≥' },
+	{ value: 'in', label: 'in list' },
+	{ value: 'like', label: 'like' },
+]
+
+interface Row {
+	field: string
+	op: string
+	value: string
+}
+
+const inputClass = 'px-3 py-2 bg-[#1a1a1a] border border-[#404040] rounded-lg text-white focus:outline-none focus:border-[#ffd700]'
+
+export default function BulkEditPage() {
+	const router = useRouter()
+	const [target, setTarget] = useState<BulkEditTarget>('players')
+	const [assignments, setAssignments] = useState<Row[]>([{ field: 'level', op: 'set', value: '' }])
+	const [conditions, setConditions] = useState<Row[]>([{ field: 'id', op: 'eq', value: '' }])
+	const [result, setResult] = useState<BulkEditResponse | null>(null)
+	const [submitting, setSubmitting] = useState(false)
+	const [error, setError] = useState('')
+	const [success, setSuccess] = useState('')
+
+	const fields = FIELDS[target]
+
+	const changeTarget = useCallback((next: BulkEditTarget) => {
+		setTarget(next)
+		setAssignments([{ field: FIELDS[next].editable[0], op: 'set', value: '' }])
+		setConditions([{ field: 'id', op: 'eq', value: '' }])
+		setResult(null)
+		setError('')
+		setSuccess('')
+	}, [])
+
+	const updateRow = (setter: typeof setAssignments, index: number, patch: Partial<Row>) => {
+		setter(prev => prev.map((row, i) => i === index ? { ...row, ...patch } : row))
+		setResult(null)
+	}
+
+	const parseValue = useCallback((field: string, raw: string): number | string => {
+		if (fields.text.includes(field)) return raw
+		const number = Number(raw.trim())
+		if (raw.trim() === '' || !Number.isInteger(number)) {
+			throw new Error(`Value for ${field} must be a whole number`)
+		}
+		return number
+	}, [fields])
+
+	const buildRequest = useCallback((dryRun: boolean) => {
+		const set: BulkAssignment[] = assignments.map(row => ({
+			field: row.field,
+			op: row.op as BulkAssignment['op'],
+			value: parseValue(row.field, row.value),
+		}))
+		const where: BulkCondition[] = conditions.map(row => ({
+			field: row.field,
+			op: row.op as BulkCondition['op'],
+			value: row.op === 'in'
+				? row.value.split(',').filter(v => v.trim() !== '').map(v => parseValue(row.field, v.trim()))
+				: parseValue(row.field, row.value),
+		}))
+		return { set, where, dryRun }
+	}, [assignments, conditions, parseValue])
+
+	const submit = useCallback(async (dryRun: boolean) => {
+		setError('')
+		setSuccess('')
+
+		let body
+		try {
+			body = buildRequest(dryRun)
+		} catch (err: any) {
+			setError(err.message)
+			return
+		}
+
+		if (!dryRun && !confirm(`Apply this change to ${result?.matched ?? 'all matching'} ${target}? This cannot be undone.`)) {
+			return
+		}
+
+		setSubmitting(true)
+		try {
+			const response = await api.post<ApiResponse<BulkEditResponse>>(`/admin/${target}/bulk-edit`, body)
+			if (dryRun) {
+				setResult(response.data)
+			} else {
+				setResult(null)
+				setSuccess(`${response.data.rowsAffected} of ${response.data.matched} matched ${target} changed.`)
+			}
+		} catch (err: any) {
+			if (err.status === 404) {
+				router.replace('/not-found')
+				return
+			}
+			setError(err.message || 'Error running bulk edit')
+		} finally {
+			setSubmitting(false)
+		}
+	}, [buildRequest, result, target, router])
+
+	const previewColumns = useMemo(() => assignments.map(row => row.field), [assignments])
+
+	return (
+		<div className="min-h-screen">
+			<div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+				<div className="mb-8">
+					<div className="flex items-center gap-4 mb-4">
+						<div className="w-16 h-16 bg-gradient-to-br from-[#ffd700] to-[#ffed4e] rounded-xl flex items-center justify-center shadow-lg">
+							<span className="text-3xl">🛠️</span>
+						</div>
+						<div>
+							<h1 className="text-4xl font-bold text-[#ffd700] mb-2">Bulk Edit</h1>
+							<p className="text-[#888]">Change whitelisted fields on every account or character matching a set of conditions</p>
+						</div>
+					</div>
+				</div>
+
+				{error && (
+					<div className="bg-red-900/30 border-2 border-red-600 rounded-lg p-4 mb-6">
+						<p className="text-red-400">{error}</p>
+					</div>
+				)}
+				{success && (
+					<div className="bg-green-900/30 border-2 border-green-600 rounded-lg p-4 mb-6">
+						<p className="text-green-400">{success}</p>
+					</div>
+				)}
+
+				<div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-8 shadow-2xl space-y-8">
+					<div>
+						<label className="block text-[#ffd700] font-bold mb-2">Target</label>
+						<select
+							value={target}
+							onChange={e => changeTarget(e.target.value as BulkEditTarget)}
+							className={inputClass}
+						>
+							<option value="players">Player characters</option>
+							<option value="accounts">Accounts</option>
+						</select>
+					</div>
+
+					<div>
+						<h2 className="text-2xl font-bold text-[#ffd700] mb-4">Change</h2>
+						<div className="space-y-3">
+							{assignments.map((row, index) => (
+								<div key={index} className="flex flex-wrap items-center gap-3">
+									<select value={row.field} onChange={e => updateRow(setAssignments, index, { field: e.target.value })} className={inputClass}>
+										{fields.editable.map(field => <option key={field} value={field}>{field}</option>)}
+									</select>
+									<select value={row.op} onChange={e => updateRow(setAssignments, index, { op: e.target.value })} className={inputClass}>
+										<option value="set">set to</option>
+										<option value="add">add</option>
+									</select>
+									<input
+										value={row.value}
+										onChange={e => updateRow(setAssignments, index, { value: e.target.value })}
+										placeholder={row.op === 'add' ? 'e.g. 10 or -10' : 'Value'}
+										className={inputClass}
+									/>
+									{assignments.length > 1 && (
+										<button
+											onClick={() => setAssignments(prev => prev.filter((_, i) => i !== index))}
+											className="px-3 py-2 bg-[#404040] hover:bg-[#505050] text-white rounded-lg transition-all"
+										>
+											Remove
+										</button>
+									)}
+								</div>
+							))}
+						</div>
+						<button
+							onClick={() => setAssignments(prev => [...prev, { field: fields.editable[0], op: 'set', value: '' }])}
+							className="mt-3 px-4 py-2 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all"
+						>
+							Add Field
+						</button>
+					</div>
+
+					<div>
+						<h2 className="text-2xl font-bold text-[#ffd700] mb-4">Where</h2>
+						<div className="space-y-3">
+							{conditions.map((row, index) => (
+								<div key={index} className="flex flex-wrap items-center gap-3">
+									<select value={row.field} onChange={e => updateRow(setConditions, index, { field: e.target.value })} className={inputClass}>
+										{fields.filters.map(field => <option key={field} value={field}>{field}</option>)}
+									</select>
+									<select value={row.op} onChange={e => updateRow(setConditions, index, { op: e.target.value })} className={inputClass}>
+										{CONDITION_OPS.map(op => <option key={op.value} value={op.value}>{op.label}</option>)}
+									</select>
+									<input
+										value={row.value}
+										onChange={e => updateRow(setConditions, index, { value: e.target.value })}
+										placeholder={row.op === 'in' ? 'Comma separated values' : row.op === 'like' ? 'e.g. %Knight%' : 'Value'}
+										className={inputClass}
+									/>
+									{conditions.length > 1 && (
+										<button
+											onClick={() => setConditions(prev => prev.filter((_, i) => i !== index))}
+											className="px-3 py-2 bg-[#404040] hover:bg-[#505050] text-white rounded-lg transition-all"
+										>
+											Remove
+										</button>
+									)}
+								</div>
+							))}
+						</div>
+						<button
+							onClick={() => setConditions(prev => [...prev, { field: 'id', op: 'eq', value: '' }])}
+							className="mt-3 px-4 py-2 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all"
+						>
+							Add Condition
+						</button>
+					</div>
+
+					<div className="flex gap-3">
+						<button
+							onClick={() => submit(true)}
+							disabled={submitting}
+							className="px-6 py-3 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+						>
+							{submitting ? 'Working...' : 'Preview'}
+						</button>
+						<button
+							onClick={() => submit(false)}
+							disabled={submitting || !result || result.matched === 0}
+							className="px-6 py-3 bg-gradient-to-r from-[#ffd700] to-[#ffed4e] text-black rounded-lg font-bold transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+						>
+							Apply
+						</button>
+					</div>
+
+					{result && (
+						<div>
+							<p className="text-[#e0e0e0] mb-4">
+								{result.matched} {target} match.
+								{result.matched > (result.preview?.length ?? 0) && ` Showing the first ${result.preview?.length ?? 0}.`}
+							</p>
+							{result.preview && result.preview.length > 0 && (
+								<div className="overflow-x-auto">
+									<table className="w-full text-left">
+										<thead>
+											<tr className="border-b border-[#404040] text-[#888] text-sm">
+												<th className="py-3 pr-4">ID</th>
+												<th className="py-3 pr-4">{target === 'players' ? 'Name' : 'Email'}</th>
+												{previewColumns.map(column => <th key={column} className="py-3 pr-4">{column}</th>)}
+											</tr>
+										</thead>
+										<tbody>
+											{result.preview.map(row => (
+												<tr key={row.id} className="border-b border-[#404040]/50 text-[#e0e0e0] text-sm">
+													<td className="py-3 pr-4">{row.id}</td>
+													<td className="py-3 pr-4">{row.label}</td>
+													{previewColumns.map(column => (
+														<td key={column} className="py-3 pr-4">
+															<span className="text-red-400">{row.before[column]}</span>
+															{' → '}
+															<span className="text-green-400">{row.after[column]}</span>
+														</td>
+													))}
+												</tr>
+											))}
+										</tbody>
+									</table>
+								</div>
+							)}
+						</div>
+					)}
+
+					<div>
+						<button
+							onClick={() => router.push('/admin')}
+							className="px-6 py-3 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all"
+						>
+							Back to Dashboard
+						</button>
+					</div>
+				</div>
+			</div>
+		</div>
+	)
+}
@@ -0,0 +1,150 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { useRouter } from 'next/navigation'
+import { api } from '../../services/api'
+import type { ApiResponse } from '../../types/account'
+import type { LoginLockout } from '../../types/admin'
+
+export default function LockoutsPage() {
+    const router = useRouter()
+    const [lockouts, setLockouts] = useState<LoginLockout[]>([])
+    const [loading, setLoading] = useState(true)
+    const [clearingKey, setClearingKey] = useState<string | null>(null)
+    const [error, setError] = useState('')
+
+    const fetchLockouts = useCallback(async () => {
+        try {
+            const response = await api.get<ApiResponse<LoginLockout[]>>('/admin/lockouts')
+            setLockouts(response?.data || [])
+        } catch (err: any) {
+            if (err.status === 404) {
+                router.replace('/not-found')
+                return
+            }
+            setError('Error loading lockouts')
+        } finally {
+            setLoading(false)
+        }
+    }, [router])
+
+    useEffect(() => {
+        fetchLockouts()
+    }, [fetchLockouts])
+
+    const handleClear = useCallback(async (key: string) => {
+        setClearingKey(key)
+        setError('')
+        try {
+            await api.delete('/admin/lockouts', { key })
+            setLockouts(prev => prev.filter(lockout => lockout.key !== key))
+        } catch (err: any) {
+            if (err.status === 404) {
+                router.replace('/not-found')
+                return
+            }
+            setError('Error clearing lockout')
+        } finally {
+            setClearingKey(null)
+        }
+    }, [router])
+
+    const handleBackToDashboard = useCallback(() => {
+        router.push('/admin')
+    }, [router])
+
+    if (loading) {
+        return (
+            <div className="min-h-screen flex items-center justify-center">
+                <div className="text-center">
+                    <div className="inline-block animate-spin rounded-full h-12 w-12 border-t-2 border-b-2 border-[#ffd700] mb-4"></div>
+                    <p className="text-[#888]">Loading...</p>
+                </div>
+            </div>
+        )
+    }
+
+    return (
+        <div className="min-h-screen">
+            <div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+                <div className="mb-8">
+                    <div className="flex items-center gap-4 mb-4">
+                        <div className="w-16 h-16 bg-gradient-to-br from-[#ffd700] to-[#ffed4e] rounded-xl flex items-center justify-center shadow-lg">
+                            <span className="text-3xl">🔒</span>
+                        </div>
+                        <div>
+                            <h1 className="text-4xl font-bold text-[#ffd700] mb-2">Login Lockouts</h1>
+                            <p className="text-[#888]">IPs and accounts temporarily blocked after repeated failed logins</p>
+                        </div>
+                    </div>
+                </div>
+
+                {error && (
+                    <div className="bg-red-900/30 border-2 border-red-600 rounded-lg p-4 mb-6">
+                        <p className="text-red-400">{error}</p>
+                    </div>
+                )}
+
+                <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-8 shadow-2xl">
+                    <div className="flex items-center justify-between mb-6">
+                        <h2 className="text-2xl font-bold text-[#ffd700]">Active Lockouts</h2>
+                        <button
+                            onClick={fetchLockouts}
+                            className="px-4 py-2 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all"
+                        >
+                            Refresh
+                        </button>
+                    </div>
+
+                    {lockouts.length === 0 ? (
+                        <p className="text-[#888]">No IP or account is locked out right now.</p>
+                    ) : (
+                        <div className="overflow-x-auto">
+                            <table className="w-full text-left">
+                                <thead>
+                                    <tr className="border-b border-[#404040] text-[#888] text-sm">
+                                        <th className="py-3 pr-4">Type</th>
+                                        <th className="py-3 pr-4">Identifier</th>
+                                        <th className="py-3 pr-4">Lockouts</th>
+                                        <th className="py-3 pr-4">Last Failure</th>
+                                        <th className="py-3 pr-4">Locked Until</th>
+                                        <th className="py-3"></th>
+                                    </tr>
+                                </thead>
+                                <tbody>
+                                    {lockouts.map(lockout => (
+                                        <tr key={lockout.key} className="border-b border-[#404040]/50 text-[#e0e0e0]">
+                                            <td className="py-3 pr-4 uppercase text-sm">{lockout.scope || '-'}</td>
+                                            <td className="py-3 pr-4 font-mono text-sm">{lockout.identifier || lockout.key}</td>
+                                            <td className="py-3 pr-4">{lockout.lockouts}</td>
+                                            <td className="py-3 pr-4 text-sm">{new Date(lockout.lastFailure).toLocaleString()}</td>
+                                            <td className="py-3 pr-4 text-sm">{new Date(lockout.lockedUntil).toLocaleString()}</td>
+                                            <td className="py-3 text-right">
+                                                <button
+                                                    onClick={() => handleClear(lockout.key)}
+                                                    disabled={clearingKey === lockout.key}
+                                                    className="px-4 py-2 bg-green-600 hover:bg-green-700 text-white rounded-lg font-bold transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+                                                >
+                                                    {clearingKey === lockout.key ? 'Unlocking...' : 'Unlock'}
+                                                </button>
+                                            </td>
+                                        </tr>
+                                    ))}
+                                </tbody>
+                            </table>
+                        </div>
+                    )}
+
+                    <div className="mt-6">
+                        <button
+                            onClick={handleBackToDashboard}
+                            className="px-6 py-3 bg-[#404040] hover:bg-[#505050] text-white rounded-lg font-bold transition-all"
+                        >
+                            Back to Dashboard
+                        </button>
+                    </div>
+                </div>
+            </div>
+        </div>
+    )
+}
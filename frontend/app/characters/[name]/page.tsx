@@ -6,8 +6,12 @@ import Link from 'next/link'
 import { api } from '../../services/api'
 import { formatDateTime } from '../../utils/date'
 import CharacterDetailsSection from '../../components/character/CharacterDetails'
+import CharacterProgress from '../../components/character/CharacterProgress'
+import ExperienceHistory from '../../components/character/ExperienceHistory'
+import OnlineTime from '../../components/character/OnlineTime'
 import type { JSX } from 'react'
 import type { CharacterDetails, Death, CharacterDetailsResponse } from '../../types/character'
+import type { CharacterPvPStats, FragCount } from '../../types/pvp'
 
 export default function CharacterDetailsPage() {
 	const params = useParams()
@@ -16,6 +20,7 @@ export default function CharacterDetailsPage() {
 
 	const [character, setCharacter] = useState<CharacterDetails | null>(null)
 	const [deaths, setDeaths] = useState<Death[]>([])
+	const [pvp, setPvp] = useState<CharacterPvPStats | null>(null)
 	const [loading, setLoading] = useState(true)
 	const [error, setError] = useState('')
 
@@ -39,6 +44,18 @@ export default function CharacterDetailsPage() {
 		fetchCharacterDetails()
 	}, [fetchCharacterDetails])
 
+	useEffect(() => {
+		if (!characterName) return
+
+		// The PvP statistics are optional; the page still works without them
+		api.get<{ data: CharacterPvPStats }>(`/characters/${characterName}/pvp`, { public: true })
+			.then(response => setPvp(response.data))
+			.catch(() => setPvp(null))
+	}, [characterName])
+
+	const formatFrags = (count: FragCount): string =>
+		count.killsToRedSkull > 0 ? `${count.frags} / ${count.killsToRedSkull}` : String(count.frags)
+
 
 	const formatDeathDescription = useCallback((death: Death): JSX.Element => {
 		const parts: string[] = []
@@ -143,8 +160,19 @@ export default function CharacterDetailsPage() {
 						<div className="grid grid-cols-1 md:grid-cols-2 gap-4">
 							<div>
 								<span className="text-[#888] text-sm">Name:</span>
-								<p className="text-[#e0e0e0] font-medium">{character.name}</p>
+								<p className="text-[#e0e0e0] font-medium">
+									{character.name}
+									{character.isMainCharacter && (
+										<span className="ml-2 text-xs font-bold px-2 py-0.5 rounded bg-[#ffd700]/20 text-[#ffd700]">Main Character</span>
+									)}
+								</p>
 							</div>
+							{character.formerNames && character.formerNames.length > 0 && (
+								<div>
+									<span className="text-[#888] text-sm">Former Names:</span>
+									<p className="text-[#e0e0e0] font-medium">{character.formerNames.join(', ')}</p>
+								</div>
+							)}
 							<div>
 								<span className="text-[#888] text-sm">Sex:</span>
 								<p className="text-[#e0e0e0] font-medium capitalize">{character.sex}</p>
@@ -161,6 +189,18 @@ export default function CharacterDetailsPage() {
 								<span className="text-[#888] text-sm">Residence:</span>
 								<p className="text-[#e0e0e0] font-medium">{character.residence}</p>
 							</div>
+							{character.realName && (
+								<div>
+									<span className="text-[#888] text-sm">Real Name:</span>
+									<p className="text-[#e0e0e0] font-medium">{character.realName}</p>
+								</div>
+							)}
+							{character.location && (
+								<div>
+									<span className="text-[#888] text-sm">Location:</span>
+									<p className="text-[#e0e0e0] font-medium">{character.location}</p>
+								</div>
+							)}
 							{character.guildName && (
 								<div>
 									<span className="text-[#888] text-sm">Guild Member:</span>
@@ -190,6 +230,12 @@ export default function CharacterDetailsPage() {
 								<p className="text-[#e0e0e0] font-medium">{formatDateTime(character.created)}</p>
 							</div>
 						</div>
+						{character.comment && (
+							<div className="mt-4 pt-4 border-t border-[#404040]/40">
+								<span className="text-[#888] text-sm">Comment:</span>
+								<p className="text-[#e0e0e0] whitespace-pre-line break-words mt-1">{character.comment}</p>
+							</div>
+						)}
 					</div>
 
 					{/* Character Details */}
@@ -197,6 +243,69 @@ export default function CharacterDetailsPage() {
 						<CharacterDetailsSection character={character} />
 					)}
 
+					<CharacterProgress character={character} />
+
+					<ExperienceHistory characterName={character.name} />
+
+					<OnlineTime characterName={character.name} />
+
+					{/* PvP */}
+					{pvp && (pvp.kills > 0 || pvp.pvpDeaths > 0) && (
+						<div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+							<h2 className="text-[#ffd700] text-xl sm:text-2xl font-bold mb-4 pb-3 border-b border-[#404040]/40">
+								PvP
+							</h2>
+							<div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+								<div>
+									<span className="text-[#888] text-sm">Kills:</span>
+									<p className="text-[#e0e0e0] font-medium">{pvp.kills}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Unjustified Kills:</span>
+									<p className="text-red-400 font-medium">{pvp.unjustifiedKills}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Deaths by Players:</span>
+									<p className="text-[#e0e0e0] font-medium">{pvp.pvpDeaths}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Active Frags:</span>
+									<p className="text-[#e0e0e0] font-medium">{pvp.activeFrags}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Frags Today:</span>
+									<p className="text-[#e0e0e0] font-medium">{formatFrags(pvp.day)}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Frags This Week:</span>
+									<p className="text-[#e0e0e0] font-medium">{formatFrags(pvp.week)}</p>
+								</div>
+								<div>
+									<span className="text-[#888] text-sm">Frags This Month:</span>
+									<p className="text-[#e0e0e0] font-medium">{formatFrags(pvp.month)}</p>
+								</div>
+							</div>
+							{pvp.mostKilled.length > 0 && (
+								<div className="mt-4 pt-4 border-t border-[#404040]/40">
+									<span className="text-[#888] text-sm">Most Killed:</span>
+									<p className="text-[#e0e0e0] mt-1">
+										{pvp.mostKilled.map((victim, idx) => (
+											<span key={victim.name}>
+												<Link
+													href={`/characters/${victim.name}`}
+													className="text-[#3b82f6] hover:text-[#60a5fa] hover:underline"
+												>
+													{victim.name}
+												</Link>
+												{' '}({victim.kills}){idx < pvp.mostKilled.length - 1 && ', '}
+											</span>
+										))}
+									</p>
+								</div>
+							)}
+						</div>
+					)}
+
 					{/* Deaths */}
 					{deaths.length > 0 && (
 						<div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
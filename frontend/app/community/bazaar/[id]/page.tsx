@@ -0,0 +1,199 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { useParams } from 'next/navigation'
+import Link from 'next/link'
+import { api } from '../../../services/api'
+import { useAuth } from '../../../contexts/AuthContext'
+import { formatDateTime } from '../../../utils/date'
+import CharacterDetailsSection from '../../../components/character/CharacterDetails'
+import type { AuctionDetailsResponse } from '../../../types/bazaar'
+
+export default function AuctionDetailsPage() {
+    const params = useParams()
+    const auctionId = params.id as string
+    const { isAuthenticated } = useAuth()
+
+    const [details, setDetails] = useState<AuctionDetailsResponse | null>(null)
+    const [loading, setLoading] = useState(true)
+    const [error, setError] = useState('')
+    const [bidAmount, setBidAmount] = useState('')
+    const [bidding, setBidding] = useState(false)
+    const [bidError, setBidError] = useState('')
+    const [bidSuccess, setBidSuccess] = useState('')
+
+    const fetchAuction = useCallback(async () => {
+        try {
+            setLoading(true)
+            setError('')
+            const response = await api.get<{ data: AuctionDetailsResponse }>(`/bazaar/${auctionId}`)
+            setDetails(response.data)
+        } catch (err: any) {
+            setError(err.message || 'Auction not found')
+        } finally {
+            setLoading(false)
+        }
+    }, [auctionId])
+
+    useEffect(() => {
+        fetchAuction()
+    }, [fetchAuction])
+
+    const handleBid = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        const amount = parseInt(bidAmount, 10)
+        if (!amount || amount < 1) {
+            setBidError('Enter the number of coins to bid')
+            return
+        }
+
+        try {
+            setBidding(true)
+            setBidError('')
+            const response = await api.post<{ message: string }>(`/bazaar/${auctionId}/bid`, { amount })
+            setBidSuccess(response.message)
+            setBidAmount('')
+            await fetchAuction()
+        } catch (err: any) {
+            setBidError(err.message || 'Failed to place bid')
+        } finally {
+            setBidding(false)
+        }
+    }, [auctionId, bidAmount, fetchAuction])
+
+    useEffect(() => {
+        if (bidError || bidSuccess) {
+            const timer = setTimeout(() => {
+                setBidError('')
+                setBidSuccess('')
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [bidError, bidSuccess])
+
+    const auction = details?.auction
+    const isRunning = auction?.status === 'active' && auction.endsAt > Math.floor(Date.now() / 1000)
+    const nextMinimum = auction ? (auction.bidCount > 0 ? auction.currentBid + 1 : auction.minimumBid) : 0
+
+    return (
+        <div className="min-h-screen">
+            <div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8 space-y-6">
+                <Link
+                    href="/community/bazaar"
+                    className="text-[#d0d0d0] hover:text-[#ffd700] text-sm transition-colors inline-flex items-center gap-2"
+                >
+                    <span>←</span>
+                    Back to Bazaar
+                </Link>
+
+                {loading && (
+                    <div className="text-center py-12">
+                        <div className="inline-block animate-spin rounded-full h-12 w-12 border-t-2 border-b-2 border-[#ffd700]"></div>
+                        <p className="mt-4 text-[#888]">Loading auction...</p>
+                    </div>
+                )}
+
+                {!loading && error && (
+                    <div className="bg-red-500/20 border-2 border-red-500/50 rounded-lg p-4 text-red-400">
+                        {error}
+                    </div>
+                )}
+
+                {!loading && auction && (
+                    <>
+                        <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+                            <h1 className="text-[#ffd700] text-2xl sm:text-3xl font-bold mb-6 pb-3 border-b border-[#404040]/40">
+                                Auction of {auction.name}
+                            </h1>
+                            <div className="grid grid-cols-1 md:grid-cols-4 gap-4">
+                                <div>
+                                    <span className="text-[#888] text-sm">{auction.bidCount > 0 ? 'Current Bid:' : 'Minimum Bid:'}</span>
+                                    <p className="text-[#ffd700] font-bold">
+                                        {auction.bidCount > 0 ? auction.currentBid : auction.minimumBid} coins
+                                    </p>
+                                </div>
+                                <div>
+                                    <span className="text-[#888] text-sm">Bids:</span>
+                                    <p className="text-[#e0e0e0] font-medium">{auction.bidCount}</p>
+                                </div>
+                                <div>
+                                    <span className="text-[#888] text-sm">{auction.status === 'active' ? 'Ends:' : 'Ended:'}</span>
+                                    <p className="text-[#e0e0e0] font-medium">{formatDateTime(auction.endsAt)}</p>
+                                </div>
+                                <div>
+                                    <span className="text-[#888] text-sm">Status:</span>
+                                    <p className="text-[#e0e0e0] font-medium capitalize">
+                                        {auction.isHighestBidder ? 'You lead this auction' : auction.status}
+                                    </p>
+                                </div>
+                            </div>
+
+                            {isRunning && isAuthenticated && !auction.isSeller && (
+                                <form onSubmit={handleBid} className="mt-6 pt-6 border-t border-[#404040]/40 space-y-3">
+                                    {bidError && <p className="text-red-400 text-sm">{bidError}</p>}
+                                    {bidSuccess && <p className="text-green-400 text-sm">{bidSuccess}</p>}
+                                    <div className="flex gap-2 max-w-md">
+                                        <input
+                                            type="number"
+                                            min={nextMinimum}
+                                            value={bidAmount}
+                                            onChange={(e) => setBidAmount(e.target.value)}
+                                            placeholder={`${nextMinimum} coins or more`}
+                                            className="flex-1 bg-[#1a1a1a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+                                        />
+                                        <button
+                                            type="submit"
+                                            disabled={bidding}
+                                            className="px-6 py-2 bg-[#ffd700] text-[#1a1a1a] rounded-lg font-bold hover:bg-[#ffed4e] disabled:opacity-50 transition-colors"
+                                        >
+                                            {bidding ? 'Bidding...' : 'Bid'}
+                                        </button>
+                                    </div>
+                                    <p className="text-[#888] text-xs">
+                                        The coins are held from your account until someone outbids you. If you win, the character moves to your account when the auction ends.
+                                    </p>
+                                </form>
+                            )}
+
+                            {isRunning && !isAuthenticated && (
+                                <p className="mt-6 pt-6 border-t border-[#404040]/40 text-[#d0d0d0] text-sm">
+                                    <Link href="/login" className="text-[#3b82f6] hover:underline">Log in</Link> to bid on this character.
+                                </p>
+                            )}
+                        </div>
+
+                        {details.character && (
+                            <CharacterDetailsSection character={details.character.character} />
+                        )}
+
+                        <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+                            <h2 className="text-[#ffd700] text-xl sm:text-2xl font-bold mb-4 pb-3 border-b border-[#404040]/40">
+                                Bids
+                            </h2>
+                            {details.bids.length === 0 ? (
+                                <p className="text-[#888] text-sm">No bids yet</p>
+                            ) : (
+                                <table className="w-full">
+                                    <thead>
+                                        <tr className="border-b border-[#404040]/60">
+                                            <th className="text-left text-[#ffd700] text-sm font-bold py-3 px-2">Date</th>
+                                            <th className="text-left text-[#ffd700] text-sm font-bold py-3 px-2">Amount</th>
+                                        </tr>
+                                    </thead>
+                                    <tbody>
+                                        {details.bids.map((bid, idx) => (
+                                            <tr key={idx} className="border-b border-[#404040]/30">
+                                                <td className="py-3 px-2 text-[#d0d0d0] text-sm">{formatDateTime(bid.time)}</td>
+                                                <td className="py-3 px-2 text-[#d0d0d0] text-sm">{bid.amount} coins</td>
+                                            </tr>
+                                        ))}
+                                    </tbody>
+                                </table>
+                            )}
+                        </div>
+                    </>
+                )}
+            </div>
+        </div>
+    )
+}
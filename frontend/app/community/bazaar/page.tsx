@@ -0,0 +1,184 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import Link from 'next/link'
+import { api } from '../../services/api'
+import { makeOutfit } from '../../utils/outfit'
+import { formatDateTime } from '../../utils/date'
+import type { ApiResponse } from '../../types/account'
+import type { Auction, AuctionsResponse } from '../../types/bazaar'
+
+const AUCTIONS_PER_PAGE = 20
+
+export default function BazaarPage() {
+    const [auctions, setAuctions] = useState<Auction[]>([])
+    const [loading, setLoading] = useState(true)
+    const [error, setError] = useState('')
+    const [page, setPage] = useState(1)
+    const [finished, setFinished] = useState(false)
+    const [totalPages, setTotalPages] = useState(0)
+
+    const fetchAuctions = useCallback(async () => {
+        setLoading(true)
+        setError('')
+        try {
+            const params = new URLSearchParams({
+                page: page.toString(),
+                limit: AUCTIONS_PER_PAGE.toString(),
+            })
+            if (finished) {
+                params.append('status', 'finished')
+            }
+
+            const response = await api.get<ApiResponse<AuctionsResponse>>(`/bazaar?${params.toString()}`, { public: true })
+            setAuctions(response.data?.auctions || [])
+            setTotalPages(response.data?.pagination?.totalPages || 0)
+        } catch (err: any) {
+            setError(err.message || 'Error loading auctions')
+        } finally {
+            setLoading(false)
+        }
+    }, [page, finished])
+
+    useEffect(() => {
+        fetchAuctions()
+    }, [fetchAuctions])
+
+    const handleFilterChange = useCallback((showFinished: boolean) => {
+        setFinished(showFinished)
+        setPage(1)
+    }, [])
+
+    return (
+        <div className="min-h-screen">
+            <div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+                <div className="mb-8">
+                    <div className="flex items-center gap-4 mb-4">
+                        <div className="w-16 h-16 bg-gradient-to-br from-[#ffd700] to-[#b8860b] rounded-xl flex items-center justify-center shadow-lg">
+                            <span className="text-3xl">⚖️</span>
+                        </div>
+                        <div>
+                            <h1 className="text-4xl font-bold text-[#ffd700] mb-2">Character Bazaar</h1>
+                            <p className="text-[#888]">Bid coins on characters put up for auction by other players</p>
+                        </div>
+                    </div>
+                </div>
+
+                <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 mb-6 shadow-2xl flex flex-wrap items-center justify-between gap-4">
+                    <div className="flex flex-wrap gap-2">
+                        {[false, true].map((showFinished) => (
+                            <button
+                                key={String(showFinished)}
+                                onClick={() => handleFilterChange(showFinished)}
+                                className={`px-4 py-2 rounded-lg font-medium transition-all ${
+                                    finished === showFinished
+                                        ? 'bg-[#ffd700] text-[#1a1a1a] shadow-lg'
+                                        : 'bg-[#1f1f1f] text-[#e0e0e0] hover:bg-[#2a2a2a]'
+                                }`}
+                            >
+                                {showFinished ? 'Finished Auctions' : 'Current Auctions'}
+                            </button>
+                        ))}
+                    </div>
+                    <Link
+                        href="/account/settings?tab=bazaar"
+                        className="px-4 py-2 bg-[#1f1f1f] border-2 border-[#404040] rounded-lg text-[#e0e0e0] hover:border-[#ffd700] transition-colors"
+                    >
+                        Sell a character
+                    </Link>
+                </div>
+
+                {error && (
+                    <div className="bg-red-500/20 border-2 border-red-500/50 rounded-lg p-4 mb-6 text-red-400">
+                        {error}
+                    </div>
+                )}
+
+                {loading && (
+                    <div className="text-center py-12">
+                        <div className="inline-block animate-spin rounded-full h-12 w-12 border-t-2 border-b-2 border-[#ffd700]"></div>
+                        <p className="mt-4 text-[#888]">Loading auctions...</p>
+                    </div>
+                )}
+
+                {!loading && !error && (
+                    <>
+                        {auctions.length === 0 ? (
+                            <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-12 text-center">
+                                <p className="text-[#888] text-lg">No auctions found</p>
+                            </div>
+                        ) : (
+                            <div className="grid grid-cols-1 md:grid-cols-2 xl:grid-cols-3 gap-4">
+                                {auctions.map((auction) => (
+                                    <Link
+                                        key={auction.id}
+                                        href={`/community/bazaar/${auction.id}`}
+                                        className="bg-[#252525]/95 border-2 border-[#505050]/70 rounded-xl p-4 hover:border-[#ffd700]/60 transition-all shadow-lg"
+                                    >
+                                        <div className="flex items-center gap-4">
+                                            <div className="flex-shrink-0 w-16 h-16 bg-[#0a0a0a] rounded border-2 border-[#404040]/60 overflow-hidden flex items-center justify-center">
+                                                <img
+                                                    src={makeOutfit({
+                                                        id: auction.lookType,
+                                                        addons: auction.lookAddons,
+                                                        head: auction.lookHead,
+                                                        body: auction.lookBody,
+                                                        legs: auction.lookLegs,
+                                                        feet: auction.lookFeet,
+                                                    })}
+                                                    alt={`${auction.name} outfit`}
+                                                    className="w-full h-full object-contain object-center"
+                                                    loading="lazy"
+                                                    onError={(e) => {
+                                                        e.currentTarget.style.display = 'none'
+                                                    }}
+                                                />
+                                            </div>
+                                            <div className="flex-1 min-w-0">
+                                                <div className="text-[#3b82f6] font-bold truncate">{auction.name}</div>
+                                                <div className="text-[#888] text-sm">Level {auction.level} {auction.vocation}</div>
+                                                <div className="text-[#888] text-xs mt-1">
+                                                    {auction.status === 'active' ? 'Ends' : 'Ended'} {formatDateTime(auction.endsAt)}
+                                                </div>
+                                            </div>
+                                            <div className="text-right">
+                                                <div className="text-[#ffd700] font-bold">
+                                                    {auction.bidCount > 0 ? auction.currentBid : auction.minimumBid}
+                                                </div>
+                                                <div className="text-[#888] text-xs">
+                                                    {auction.status === 'sold' ? 'Sold' : auction.bidCount > 0 ? `${auction.bidCount} bids` : 'Minimum bid'}
+                                                </div>
+                                            </div>
+                                        </div>
+                                    </Link>
+                                ))}
+                            </div>
+                        )}
+
+                        {totalPages > 1 && (
+                            <div className="flex items-center justify-center gap-2 mt-6">
+                                <button
+                                    onClick={() => setPage(page - 1)}
+                                    disabled={page === 1}
+                                    className="px-4 py-2 bg-[#1f1f1f] border-2 border-[#404040] rounded-lg text-[#e0e0e0] disabled:opacity-50 disabled:cursor-not-allowed hover:bg-[#2a2a2a] transition-colors"
+                                >
+                                    Previous
+                                </button>
+                                <span className="px-4 py-2 text-[#888]">
+                                    Page {page} of {totalPages}
+                                </span>
+                                <button
+                                    onClick={() => setPage(page + 1)}
+                                    disabled={page >= totalPages}
+                                    className="px-4 py-2 bg-[#1f1f1f] border-2 border-[#404040] rounded-lg text-[#e0e0e0] disabled:opacity-50 disabled:cursor-not-allowed hover:bg-[#2a2a2a] transition-colors"
+                                >
+                                    Next
+                                </button>
+                            </div>
+                        )}
+                    </>
+                )}
+            </div>
+        </div>
+    )
+}
@@ -0,0 +1,162 @@
+'use client'
+
+import { useEffect, useState, useCallback } from 'react'
+import Link from 'next/link'
+import { api } from '../../services/api'
+import type { ApiResponse } from '../../types/account'
+import type { FragPeriod, TopFraggersResponse } from '../../types/pvp'
+import { makeOutfit } from '../../utils/outfit'
+
+const PERIODS: { id: FragPeriod; label: string }[] = [
+  { id: 'day', label: 'Last Day' },
+  { id: 'week', label: 'Last Week' },
+  { id: 'month', label: 'Last Month' },
+]
+
+export default function TopFraggersPage() {
+  const [period, setPeriod] = useState<FragPeriod>('day')
+  const [fraggers, setFraggers] = useState<TopFraggersResponse | null>(null)
+  const [loading, setLoading] = useState(true)
+  const [error, setError] = useState('')
+
+  const fetchFraggers = useCallback(async () => {
+    setLoading(true)
+    setError('')
+    try {
+      const response = await api.get<ApiResponse<TopFraggersResponse>>(
+        `/top-fraggers?period=${period}`,
+        { public: true }
+      )
+      if (response?.data) {
+        setFraggers(response.data)
+      }
+    } catch (err: any) {
+      setError(err.message || 'Error loading top fraggers')
+    } finally {
+      setLoading(false)
+    }
+  }, [period])
+
+  useEffect(() => {
+    fetchFraggers()
+  }, [fetchFraggers])
+
+  return (
+    <div className="min-h-screen">
+      <div className="max-w-[1400px] mx-auto px-4 sm:px-6 lg:px-8 py-8">
+        <div className="mb-8">
+          <div className="flex items-center gap-4 mb-4">
+            <div className="w-16 h-16 bg-gradient-to-br from-[#8b0000] to-[#cc0000] rounded-xl flex items-center justify-center shadow-lg">
+              <span className="text-3xl">⚔️</span>
+            </div>
+            <div>
+              <h1 className="text-4xl font-bold text-[#ffd700] mb-2">Top Fraggers</h1>
+              <p className="text-[#888]">Characters with the most player kills</p>
+            </div>
+          </div>
+        </div>
+
+        {error && (
+          <div className="bg-red-900/30 border-2 border-red-600 rounded-lg p-4 mb-6">
+            <p className="text-red-400">{error}</p>
+          </div>
+        )}
+
+        <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#404040]/60 p-6 shadow-2xl mb-6">
+          <div className="flex flex-wrap items-center gap-2">
+            {PERIODS.map((p) => (
+              <button
+                key={p.id}
+                onClick={() => setPeriod(p.id)}
+                className={`px-4 py-2 rounded-lg font-bold transition-all ${
+                  period === p.id
+                    ? 'bg-[#ffd700] text-[#0a0a0a]'
+                    : 'bg-[#1a1a1a] border border-[#404040] text-[#e0e0e0] hover:bg-[#2a2a2a]'
+                }`}
+              >
+                {p.label}
+              </button>
+            ))}
+            {fraggers && fraggers.killsToRedSkull > 0 && (
+              <span className="ml-auto text-[#888] text-sm">
+                Red skull at {fraggers.killsToRedSkull} unjustified kills
+              </span>
+            )}
+          </div>
+        </div>
+
+        {loading ? (
+          <div className="flex items-center justify-center py-20">
+            <div className="text-center">
+              <div className="inline-block animate-spin rounded-full h-12 w-12 border-t-2 border-b-2 border-[#ffd700] mb-4" />
+              <p className="text-[#888]">Loading top fraggers...</p>
+            </div>
+          </div>
+        ) : fraggers?.players && fraggers.players.length > 0 ? (
+          <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#404040]/60 shadow-2xl overflow-hidden">
+            <div className="overflow-x-auto">
+              <table className="w-full">
+                <thead className="bg-[#1a1a1a] border-b-2 border-[#404040]">
+                  <tr>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Rank</th>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Player</th>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Vocation</th>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Level</th>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Kills</th>
+                    <th className="px-6 py-4 text-left text-[#ffd700] font-bold text-sm uppercase tracking-wide">Unjustified</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {fraggers.players.map((player) => (
+                    <tr
+                      key={player.name}
+                      className="border-b border-[#404040]/30 hover:bg-[#1a1a1a]/50 transition-colors"
+                    >
+                      <td className="px-6 py-4 text-[#ffd700] font-bold">{player.rank}</td>
+                      <td className="px-6 py-4">
+                        <Link
+                          href={`/characters/${player.name}`}
+                          className="flex items-center gap-3 hover:text-[#ffd700] transition-colors group"
+                        >
+                          <div className="w-12 h-12 flex items-end justify-start flex-shrink-0 bg-[#0a0a0a]/50 rounded border border-[#404040]/30 overflow-hidden pb-1">
+                            <img
+                              src={makeOutfit({
+                                id: player.lookType,
+                                addons: player.lookAddons,
+                                head: player.lookHead,
+                                body: player.lookBody,
+                                legs: player.lookLegs,
+                                feet: player.lookFeet,
+                              })}
+                              alt={player.name}
+                              className="w-full h-full object-contain object-bottom"
+                              style={{ transform: 'scale(1.5) translateX(-8px) translateY(-6px)' }}
+                              onError={(e) => {
+                                e.currentTarget.style.display = 'none'
+                              }}
+                            />
+                          </div>
+                          <span className="font-semibold text-[#e0e0e0] group-hover:text-[#ffd700]">
+                            {player.name}
+                          </span>
+                        </Link>
+                      </td>
+                      <td className="px-6 py-4 text-[#e0e0e0]">{player.vocation}</td>
+                      <td className="px-6 py-4 text-[#e0e0e0]">{player.level}</td>
+                      <td className="px-6 py-4 text-[#e0e0e0] font-semibold">{player.kills}</td>
+                      <td className="px-6 py-4 text-red-400">{player.unjustifiedKills}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </div>
+          </div>
+        ) : (
+          <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#404040]/60 p-12 text-center">
+            <p className="text-[#888] text-lg">No player kills in this period</p>
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
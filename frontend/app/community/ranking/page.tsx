@@ -18,6 +18,9 @@ const RANKING_TYPES: { type: RankingType; label: string; icon: string }[] = [
   { type: 'distance', label: 'Distance', icon: '🏹' },
   { type: 'fist', label: 'Fist', icon: '👊' },
   { type: 'fishing', label: 'Fishing', icon: '🎣' },
+  { type: 'achievements', label: 'Achievements', icon: '🏅' },
+  { type: 'gainedtoday', label: 'Exp Today', icon: '📈' },
+  { type: 'gainedweek', label: 'Exp This Week', icon: '📅' },
 ]
 
 const VOCATIONS = [
@@ -105,6 +108,9 @@ export default function RankingPage() {
       distance: 'Distance',
       fist: 'Fist',
       fishing: 'Fishing',
+      achievements: 'Points',
+      gainedtoday: 'Experience Gained',
+      gainedweek: 'Experience Gained',
     }
     return typeMap[rankingType] || 'Value'
   }
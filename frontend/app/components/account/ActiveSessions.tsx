@@ -0,0 +1,162 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { api } from '../../services/api'
+import { authService } from '../../services/auth'
+import React from 'react'
+
+interface WebSession {
+    id: string
+    ip: string
+    userAgent: string
+    createdAt: number
+    lastSeenAt: number
+    expiresAt: number
+    current: boolean
+}
+
+interface SessionsResponse {
+    data: WebSession[]
+}
+
+const formatDate = (timestamp: number) =>
+    new Date(timestamp * 1000).toLocaleString('en-US', {
+        month: 'short',
+        day: 'numeric',
+        year: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+    })
+
+const ActiveSessions = React.memo(() => {
+    const [sessions, setSessions] = useState<WebSession[]>([])
+    const [loading, setLoading] = useState(true)
+    const [revokingId, setRevokingId] = useState<string | null>(null)
+    const [revokingAll, setRevokingAll] = useState(false)
+    const [error, setError] = useState<string | null>(null)
+    const [success, setSuccess] = useState<string | null>(null)
+
+    const fetchSessions = useCallback(async () => {
+        try {
+            setLoading(true)
+            setError(null)
+            const response = await api.get<SessionsResponse>('/account/sessions')
+            setSessions(response.data || [])
+        } catch (err: any) {
+            setError(err.message || 'Failed to fetch sessions')
+        } finally {
+            setLoading(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        fetchSessions()
+    }, [fetchSessions])
+
+    const handleRevoke = useCallback(async (session: WebSession) => {
+        try {
+            setRevokingId(session.id)
+            setError(null)
+            await api.delete(`/account/sessions/${session.id}`)
+            if (session.current) {
+                authService.removeToken()
+                window.location.href = '/login'
+                return
+            }
+            setSuccess('Session revoked successfully.')
+            await fetchSessions()
+        } catch (err: any) {
+            setError(err.message || 'Failed to revoke session')
+        } finally {
+            setRevokingId(null)
+        }
+    }, [fetchSessions])
+
+    const handleRevokeAll = useCallback(async () => {
+        try {
+            setRevokingAll(true)
+            setError(null)
+            await api.delete('/account/sessions')
+            authService.removeToken()
+            window.location.href = '/login'
+        } catch (err: any) {
+            setError(err.message || 'Failed to revoke sessions')
+            setRevokingAll(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        if (error || success) {
+            const timer = setTimeout(() => {
+                setError(null)
+                setSuccess(null)
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [error, success])
+
+    return (
+        <div className="space-y-6">
+            <div className="flex items-center justify-between mb-4">
+                <h2 className="text-2xl font-bold text-[#ffd700]">Active Sessions</h2>
+                <button
+                    onClick={handleRevokeAll}
+                    disabled={revokingAll || loading || sessions.length === 0}
+                    className="bg-red-700 hover:bg-red-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                >
+                    {revokingAll ? 'Signing out...' : 'Sign Out Everywhere'}
+                </button>
+            </div>
+
+            {/* Error/Success Messages */}
+            {error && (
+                <div className="bg-red-900/20 border border-red-500/50 rounded-lg p-4">
+                    <p className="text-red-400 text-sm">{error}</p>
+                </div>
+            )}
+            {success && (
+                <div className="bg-green-900/20 border border-green-500/50 rounded-lg p-4">
+                    <p className="text-green-400 text-sm">{success}</p>
+                </div>
+            )}
+
+            {loading ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6">
+                    <p className="text-[#d0d0d0]">Loading...</p>
+                </div>
+            ) : (
+                <div className="space-y-3">
+                    {sessions.map((session) => (
+                        <div
+                            key={session.id}
+                            className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 flex items-center justify-between gap-4"
+                        >
+                            <div className="min-w-0">
+                                <p className="text-[#e0e0e0] text-sm font-medium truncate">
+                                    {session.userAgent || 'Unknown device'}
+                                    {session.current && (
+                                        <span className="ml-2 text-xs text-green-400">(this device)</span>
+                                    )}
+                                </p>
+                                <p className="text-[#888] text-xs mt-1">
+                                    IP {session.ip || 'unknown'} · Last seen {formatDate(session.lastSeenAt)} · Signed in {formatDate(session.createdAt)}
+                                </p>
+                            </div>
+                            <button
+                                onClick={() => handleRevoke(session)}
+                                disabled={revokingId === session.id || revokingAll}
+                                className="bg-[#404040] hover:bg-[#505050] disabled:bg-[#303030] disabled:cursor-not-allowed text-white font-bold py-2 px-4 rounded-lg transition-all whitespace-nowrap"
+                            >
+                                {revokingId === session.id ? 'Revoking...' : 'Revoke'}
+                            </button>
+                        </div>
+                    ))}
+                </div>
+            )}
+        </div>
+    )
+})
+
+ActiveSessions.displayName = 'ActiveSessions'
+
+export default ActiveSessions
@@ -0,0 +1,201 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { api } from '../../services/api'
+import React from 'react'
+
+interface EmailChangeStatus {
+    newEmail: string
+    oldConfirmed: boolean
+    newConfirmed: boolean
+    expiresAt: number
+    applyAfter?: number
+}
+
+interface EmailChangeResponse {
+    data: EmailChangeStatus | null
+}
+
+const formatDate = (timestamp: number) =>
+    new Date(timestamp * 1000).toLocaleString('en-US', {
+        month: 'short',
+        day: 'numeric',
+        year: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+    })
+
+const inputClassName = "w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+
+const ChangeEmail = React.memo(({ currentEmail }: { currentEmail: string }) => {
+    const [pending, setPending] = useState<EmailChangeStatus | null>(null)
+    const [loading, setLoading] = useState(true)
+    const [submitting, setSubmitting] = useState(false)
+    const [cancelling, setCancelling] = useState(false)
+    const [error, setError] = useState<string | null>(null)
+    const [success, setSuccess] = useState<string | null>(null)
+    const [form, setForm] = useState({ newEmail: '', password: '', token: '' })
+
+    const fetchPending = useCallback(async () => {
+        try {
+            setLoading(true)
+            const response = await api.get<EmailChangeResponse>('/account/email')
+            setPending(response.data || null)
+        } catch (err: any) {
+            setError(err.message || 'Failed to fetch email change')
+        } finally {
+            setLoading(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        fetchPending()
+    }, [fetchPending])
+
+    const handleSubmit = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        if (!form.newEmail || !form.password) {
+            setError('New email and password are required')
+            return
+        }
+
+        try {
+            setSubmitting(true)
+            setError(null)
+            await api.post('/account/email', {
+                newEmail: form.newEmail,
+                password: form.password,
+                token: form.token,
+            })
+            setSuccess('Check both inboxes and open the confirmation links to complete the change.')
+            setForm({ newEmail: '', password: '', token: '' })
+            await fetchPending()
+        } catch (err: any) {
+            setError(err.message || 'Failed to request email change')
+        } finally {
+            setSubmitting(false)
+        }
+    }, [form, fetchPending])
+
+    const handleCancel = useCallback(async () => {
+        try {
+            setCancelling(true)
+            setError(null)
+            await api.delete('/account/email')
+            setSuccess('The email change has been cancelled.')
+            setPending(null)
+        } catch (err: any) {
+            setError(err.message || 'Failed to cancel email change')
+        } finally {
+            setCancelling(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        if (error || success) {
+            const timer = setTimeout(() => {
+                setError(null)
+                setSuccess(null)
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [error, success])
+
+    return (
+        <div className="space-y-6">
+            <h2 className="text-2xl font-bold text-[#ffd700] mb-4">Email Address</h2>
+
+            {/* Error/Success Messages */}
+            {error && (
+                <div className="bg-red-900/20 border border-red-500/50 rounded-lg p-4">
+                    <p className="text-red-400 text-sm">{error}</p>
+                </div>
+            )}
+            {success && (
+                <div className="bg-green-900/20 border border-green-500/50 rounded-lg p-4">
+                    <p className="text-green-400 text-sm">{success}</p>
+                </div>
+            )}
+
+            {loading ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6">
+                    <p className="text-[#d0d0d0]">Loading...</p>
+                </div>
+            ) : pending ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-3">
+                    <h3 className="text-[#ffd700] font-bold">Pending change to {pending.newEmail}</h3>
+                    <ul className="text-sm space-y-1">
+                        <li className={pending.oldConfirmed ? 'text-green-400' : 'text-[#d0d0d0]'}>
+                            {pending.oldConfirmed ? '✓' : '•'} Confirmed from {currentEmail}
+                        </li>
+                        <li className={pending.newConfirmed ? 'text-green-400' : 'text-[#d0d0d0]'}>
+                            {pending.newConfirmed ? '✓' : '•'} Confirmed from {pending.newEmail}
+                        </li>
+                    </ul>
+                    <p className="text-[#888] text-sm">
+                        {pending.applyAfter
+                            ? `The change will be applied on ${formatDate(pending.applyAfter)}. You can still cancel it until then.`
+                            : `Both addresses must confirm before ${formatDate(pending.expiresAt)}.`}
+                    </p>
+                    <button
+                        onClick={handleCancel}
+                        disabled={cancelling}
+                        className="bg-red-700 hover:bg-red-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                    >
+                        {cancelling ? 'Cancelling...' : 'Cancel Change'}
+                    </button>
+                </div>
+            ) : (
+                <form onSubmit={handleSubmit} className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-4">
+                    <p className="text-[#d0d0d0] text-sm">
+                        Your current email is <strong>{currentEmail}</strong>. A confirmation link will be sent to both your current and your new address.
+                    </p>
+                    <div className="max-w-md space-y-4">
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">New Email</label>
+                            <input
+                                type="email"
+                                value={form.newEmail}
+                                onChange={(e) => setForm(prev => ({ ...prev, newEmail: e.target.value }))}
+                                placeholder="Enter your new email address"
+                                className={inputClassName}
+                            />
+                        </div>
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Password</label>
+                            <input
+                                type="password"
+                                value={form.password}
+                                onChange={(e) => setForm(prev => ({ ...prev, password: e.target.value }))}
+                                placeholder="Enter your password"
+                                className={inputClassName}
+                            />
+                        </div>
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">2FA Token or Backup Code (if enabled)</label>
+                            <input
+                                type="text"
+                                maxLength={11}
+                                value={form.token}
+                                onChange={(e) => setForm(prev => ({ ...prev, token: e.target.value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase() }))}
+                                placeholder="000000"
+                                className={inputClassName}
+                            />
+                        </div>
+                    </div>
+                    <button
+                        type="submit"
+                        disabled={submitting}
+                        className="bg-green-700 hover:bg-green-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                    >
+                        {submitting ? 'Sending...' : 'Change Email'}
+                    </button>
+                </form>
+            )}
+        </div>
+    )
+})
+
+ChangeEmail.displayName = 'ChangeEmail'
+
+export default ChangeEmail
@@ -0,0 +1,138 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { api } from '../../services/api'
+import React from 'react'
+
+const EMPTY_FORM = { currentPassword: '', newPassword: '', confirmPassword: '', token: '' }
+
+const inputClassName = "w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+
+const ChangePassword = React.memo(() => {
+    const [form, setForm] = useState(EMPTY_FORM)
+    const [saving, setSaving] = useState(false)
+    const [error, setError] = useState<string | null>(null)
+    const [success, setSuccess] = useState<string | null>(null)
+
+    const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+        const { name, value } = e.target
+        setForm(prev => ({ ...prev, [name]: name === 'token' ? value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase() : value }))
+    }
+
+    const handleSubmit = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        if (!form.currentPassword || !form.newPassword) {
+            setError('Current and new password are required')
+            return
+        }
+        if (form.newPassword !== form.confirmPassword) {
+            setError('Passwords do not match')
+            return
+        }
+
+        try {
+            setSaving(true)
+            setError(null)
+            await api.put('/account/password', {
+                currentPassword: form.currentPassword,
+                newPassword: form.newPassword,
+                token: form.token,
+            })
+            setSuccess('Your password has been changed. Your other sessions have been signed out.')
+            setForm(EMPTY_FORM)
+        } catch (err: any) {
+            setError(err.message || 'Failed to change password')
+        } finally {
+            setSaving(false)
+        }
+    }, [form])
+
+    useEffect(() => {
+        if (error || success) {
+            const timer = setTimeout(() => {
+                setError(null)
+                setSuccess(null)
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [error, success])
+
+    return (
+        <div className="space-y-6">
+            <h2 className="text-2xl font-bold text-[#ffd700] mb-4">Change Password</h2>
+
+            {/* Error/Success Messages */}
+            {error && (
+                <div className="bg-red-900/20 border border-red-500/50 rounded-lg p-4">
+                    <p className="text-red-400 text-sm">{error}</p>
+                </div>
+            )}
+            {success && (
+                <div className="bg-green-900/20 border border-green-500/50 rounded-lg p-4">
+                    <p className="text-green-400 text-sm">{success}</p>
+                </div>
+            )}
+
+            <form onSubmit={handleSubmit} className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-4">
+                <div className="max-w-md space-y-4">
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Current Password</label>
+                        <input
+                            name="currentPassword"
+                            type="password"
+                            value={form.currentPassword}
+                            onChange={handleChange}
+                            autoComplete="current-password"
+                            className={inputClassName}
+                        />
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">New Password</label>
+                        <input
+                            name="newPassword"
+                            type="password"
+                            value={form.newPassword}
+                            onChange={handleChange}
+                            autoComplete="new-password"
+                            className={inputClassName}
+                        />
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Confirm New Password</label>
+                        <input
+                            name="confirmPassword"
+                            type="password"
+                            value={form.confirmPassword}
+                            onChange={handleChange}
+                            autoComplete="new-password"
+                            className={inputClassName}
+                        />
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">2FA Token or Backup Code (if enabled)</label>
+                        <input
+                            name="token"
+                            type="text"
+                            maxLength={11}
+                            value={form.token}
+                            onChange={handleChange}
+                            placeholder="000000"
+                            className={inputClassName}
+                        />
+                    </div>
+                </div>
+                <button
+                    type="submit"
+                    disabled={saving}
+                    className="bg-green-700 hover:bg-green-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                >
+                    {saving ? 'Saving...' : 'Change Password'}
+                </button>
+            </form>
+        </div>
+    )
+})
+
+ChangePassword.displayName = 'ChangePassword'
+
+export default ChangePassword
@@ -0,0 +1,250 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import Link from 'next/link'
+import { api } from '../../services/api'
+import type { Character, CharactersApiResponse } from '../../types/character'
+import type { Auction, MyAuctionsResponse } from '../../types/bazaar'
+import React from 'react'
+
+const formatDate = (timestamp: number) =>
+    new Date(timestamp * 1000).toLocaleString('en-US', {
+        month: 'short',
+        day: 'numeric',
+        year: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+    })
+
+const STATUS_LABELS: Record<Auction['status'], string> = {
+    active: 'Running',
+    sold: 'Sold',
+    expired: 'No bids',
+    cancelled: 'Canceled',
+    failed: 'Failed',
+}
+
+const inputClassName = "w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+
+const CharacterBazaar = React.memo(() => {
+    const [auctions, setAuctions] = useState<Auction[]>([])
+    const [characters, setCharacters] = useState<Character[]>([])
+    const [maxAuctionDays, setMaxAuctionDays] = useState(7)
+    const [loading, setLoading] = useState(true)
+    const [submitting, setSubmitting] = useState(false)
+    const [cancelingId, setCancelingId] = useState<number | null>(null)
+    const [error, setError] = useState<string | null>(null)
+    const [success, setSuccess] = useState<string | null>(null)
+    const [form, setForm] = useState({ character: '', minimumBid: '', durationDays: '1', password: '' })
+
+    const fetchData = useCallback(async () => {
+        try {
+            setLoading(true)
+            const [auctionsResponse, charactersResponse] = await Promise.all([
+                api.get<{ data: MyAuctionsResponse }>('/account/bazaar'),
+                api.get<CharactersApiResponse>('/characters'),
+            ])
+            setAuctions(auctionsResponse.data?.auctions || [])
+            setMaxAuctionDays(auctionsResponse.data?.maxAuctionDays || 7)
+            setCharacters(charactersResponse.data || [])
+        } catch (err: any) {
+            setError(err.message || 'Failed to fetch auctions')
+        } finally {
+            setLoading(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        fetchData()
+    }, [fetchData])
+
+    const onAuction = new Set(auctions.filter(a => a.isSeller && a.status === 'active').map(a => a.name))
+    const sellable = characters.filter(c => !c.deletionScheduledAt && !onAuction.has(c.name))
+
+    const handleCreate = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        const minimumBid = parseInt(form.minimumBid, 10)
+        if (!form.character || !minimumBid || minimumBid < 1) {
+            setError('Choose a character and a minimum bid of at least 1 coin')
+            return
+        }
+        if (!form.password) {
+            setError('Password is required')
+            return
+        }
+
+        try {
+            setSubmitting(true)
+            setError(null)
+            await api.post('/bazaar', {
+                character: form.character,
+                minimumBid,
+                durationDays: parseInt(form.durationDays, 10),
+                password: form.password,
+            })
+            setSuccess(`${form.character} is now up for auction.`)
+            setForm({ character: '', minimumBid: '', durationDays: '1', password: '' })
+            await fetchData()
+        } catch (err: any) {
+            setError(err.message || 'Failed to create auction')
+        } finally {
+            setSubmitting(false)
+        }
+    }, [form, fetchData])
+
+    const handleCancel = useCallback(async (auction: Auction) => {
+        try {
+            setCancelingId(auction.id)
+            setError(null)
+            await api.post(`/bazaar/${auction.id}/cancel`, {})
+            setSuccess('Auction canceled successfully.')
+            await fetchData()
+        } catch (err: any) {
+            setError(err.message || 'Failed to cancel auction')
+        } finally {
+            setCancelingId(null)
+        }
+    }, [fetchData])
+
+    useEffect(() => {
+        if (error || success) {
+            const timer = setTimeout(() => {
+                setError(null)
+                setSuccess(null)
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [error, success])
+
+    return (
+        <div className="space-y-6">
+            <h2 className="text-2xl font-bold text-[#ffd700] mb-4">Character Bazaar</h2>
+
+            {error && (
+                <div className="bg-red-900/20 border border-red-500/50 rounded-lg p-4">
+                    <p className="text-red-400 text-sm">{error}</p>
+                </div>
+            )}
+            {success && (
+                <div className="bg-green-900/20 border border-green-500/50 rounded-lg p-4">
+                    <p className="text-green-400 text-sm">{success}</p>
+                </div>
+            )}
+
+            <p className="text-[#d0d0d0] text-sm">
+                Put an offline character up for auction. It can't be played from the client until the auction ends.
+                When the auction ends, the character moves to the highest bidder and the coins are added to your account.
+                Auctions can be canceled only before the first bid.
+                {' '}<Link href="/community/bazaar" className="text-[#3b82f6] hover:underline">Browse the bazaar</Link>
+            </p>
+
+            {loading ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6">
+                    <p className="text-[#d0d0d0]">Loading...</p>
+                </div>
+            ) : auctions.length === 0 ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 text-[#b0b0b0] text-sm">
+                    You have no auctions and you are not leading any.
+                </div>
+            ) : (
+                <div className="space-y-3">
+                    {auctions.map((auction) => (
+                        <div
+                            key={auction.id}
+                            className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 flex items-center justify-between gap-4"
+                        >
+                            <div className="min-w-0">
+                                <Link href={`/community/bazaar/${auction.id}`} className="text-[#3b82f6] hover:underline text-sm font-medium truncate">
+                                    {auction.name}
+                                </Link>
+                                <span className="ml-2 text-xs font-bold px-2 py-0.5 rounded bg-[#404040]/50 text-[#b0b0b0]">
+                                    {auction.isHighestBidder ? 'Leading bid' : STATUS_LABELS[auction.status]}
+                                </span>
+                                <p className="text-[#888] text-xs mt-1">
+                                    {auction.bidCount > 0 ? `Current bid: ${auction.currentBid} coins (${auction.bidCount} bids)` : `Minimum bid: ${auction.minimumBid} coins`}
+                                    {' · '}
+                                    {auction.status === 'active' ? 'Ends' : 'Ended'} {formatDate(auction.endsAt)}
+                                </p>
+                            </div>
+                            {auction.isSeller && auction.status === 'active' && auction.bidCount === 0 && (
+                                <button
+                                    onClick={() => handleCancel(auction)}
+                                    disabled={cancelingId === auction.id}
+                                    className="bg-[#404040] hover:bg-[#505050] disabled:bg-[#303030] disabled:cursor-not-allowed text-white font-bold py-2 px-4 rounded-lg transition-all whitespace-nowrap"
+                                >
+                                    {cancelingId === auction.id ? 'Canceling...' : 'Cancel'}
+                                </button>
+                            )}
+                        </div>
+                    ))}
+                </div>
+            )}
+
+            <form onSubmit={handleCreate} className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-4">
+                <h3 className="text-[#ffd700] font-bold">Sell a Character</h3>
+                <div className="max-w-md space-y-4">
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Character</label>
+                        <select
+                            value={form.character}
+                            onChange={(e) => setForm(prev => ({ ...prev, character: e.target.value }))}
+                            className={inputClassName}
+                        >
+                            <option value="">Choose a character</option>
+                            {sellable.map((char) => (
+                                <option key={char.id} value={char.name} disabled={char.status === 'online'}>
+                                    {char.name} (level {char.level}){char.status === 'online' ? ' - online' : ''}
+                                </option>
+                            ))}
+                        </select>
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Minimum bid (coins)</label>
+                        <input
+                            type="number"
+                            min={1}
+                            value={form.minimumBid}
+                            onChange={(e) => setForm(prev => ({ ...prev, minimumBid: e.target.value }))}
+                            className={inputClassName}
+                        />
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Duration</label>
+                        <select
+                            value={form.durationDays}
+                            onChange={(e) => setForm(prev => ({ ...prev, durationDays: e.target.value }))}
+                            className={inputClassName}
+                        >
+                            {Array.from({ length: maxAuctionDays }, (_, i) => i + 1).map((days) => (
+                                <option key={days} value={days}>
+                                    {days} {days === 1 ? 'day' : 'days'}
+                                </option>
+                            ))}
+                        </select>
+                    </div>
+                    <div>
+                        <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Password</label>
+                        <input
+                            type="password"
+                            value={form.password}
+                            onChange={(e) => setForm(prev => ({ ...prev, password: e.target.value }))}
+                            autoComplete="current-password"
+                            className={inputClassName}
+                        />
+                    </div>
+                </div>
+                <button
+                    type="submit"
+                    disabled={submitting}
+                    className="bg-green-700 hover:bg-green-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                >
+                    {submitting ? 'Creating...' : 'Start Auction'}
+                </button>
+            </form>
+        </div>
+    )
+})
+
+CharacterBazaar.displayName = 'CharacterBazaar'
+
+export default CharacterBazaar
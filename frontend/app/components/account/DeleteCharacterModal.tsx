@@ -0,0 +1,113 @@
+'use client'
+
+import { useState, useCallback, memo } from 'react'
+
+interface DeleteCharacterModalProps {
+    characterName: string | null
+    onClose: () => void
+    onConfirm: (password: string) => Promise<void>
+    isDeleting: boolean
+}
+
+function DeleteCharacterModal({
+    characterName,
+    onClose,
+    onConfirm,
+    isDeleting,
+}: DeleteCharacterModalProps) {
+    const [password, setPassword] = useState('')
+    const [error, setError] = useState('')
+
+    const handleSubmit = useCallback(async () => {
+        if (!password) {
+            setError('Please enter your password to confirm')
+            return
+        }
+
+        setError('')
+        try {
+            await onConfirm(password)
+            setPassword('')
+        } catch (err: any) {
+            setError(err.message || 'Invalid password')
+        }
+    }, [password, onConfirm])
+
+    const handleClose = useCallback(() => {
+        setPassword('')
+        setError('')
+        onClose()
+    }, [onClose])
+
+    if (!characterName) return null
+
+    return (
+        <div className="fixed inset-0 bg-black/70 backdrop-blur-sm flex items-center justify-center z-50 p-4">
+            <div className="bg-[#1a1a1a] border-2 border-red-600 rounded-lg p-6 max-w-md w-full shadow-2xl">
+                <div className="flex items-center gap-3 mb-4">
+                    <span className="text-4xl">⚠️</span>
+                    <h3 className="text-red-400 font-bold text-xl">Delete {characterName}?</h3>
+                </div>
+
+                <div className="space-y-4 mb-6">
+                    <p className="text-[#e0e0e0]">
+                        The character will be scheduled for deletion. You can still play it and cancel the deletion
+                        from this page until the grace period ends. After that, the character and its items are gone for good.
+                    </p>
+
+                    <div>
+                        <label className="text-[#ffd700] text-sm font-bold block mb-2">
+                            Enter your password to confirm:
+                        </label>
+                        <input
+                            type="password"
+                            value={password}
+                            onChange={(e) => {
+                                setPassword(e.target.value)
+                                if (error) setError('')
+                            }}
+                            placeholder="Your password"
+                            className={`w-full bg-[#0a0a0a] border-2 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:ring-2 transition-all ${
+                                error
+                                    ? 'border-red-500 focus:border-red-500 focus:ring-red-500/20'
+                                    : 'border-[#404040] focus:border-red-500 focus:ring-red-500/20'
+                            }`}
+                            autoFocus
+                            disabled={isDeleting}
+                            onKeyDown={(e) => {
+                                if (e.key === 'Enter' && password && !isDeleting) {
+                                    handleSubmit()
+                                }
+                            }}
+                        />
+                        {error && (
+                            <p className="text-red-400 text-sm mt-2 flex items-center gap-1">
+                                <span>⚠️</span>
+                                <span>{error}</span>
+                            </p>
+                        )}
+                    </div>
+                </div>
+
+                <div className="flex gap-3">
+                    <button
+                        onClick={handleSubmit}
+                        disabled={!password || isDeleting}
+                        className="flex-1 bg-red-700 hover:bg-red-600 disabled:bg-gray-600 disabled:cursor-not-allowed text-white font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        {isDeleting ? 'Scheduling...' : 'Delete Character'}
+                    </button>
+                    <button
+                        onClick={handleClose}
+                        disabled={isDeleting}
+                        className="flex-1 bg-[#404040] hover:bg-[#505050] disabled:bg-gray-600 disabled:cursor-not-allowed text-white font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        Cancel
+                    </button>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default memo(DeleteCharacterModal)
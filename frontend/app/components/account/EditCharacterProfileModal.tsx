@@ -0,0 +1,154 @@
+'use client'
+
+import { useState, useEffect, useCallback, memo } from 'react'
+import { api } from '../../services/api'
+import type { CharacterProfile } from '../../types/character'
+
+interface EditCharacterProfileModalProps {
+    characterName: string | null
+    onClose: () => void
+    onSaved: () => void
+}
+
+const MAX_COMMENT_LENGTH = 2000
+const MAX_FIELD_LENGTH = 50
+
+const emptyProfile: CharacterProfile = { comment: '', realName: '', location: '', isMainCharacter: false }
+
+const inputClassName = "w-full bg-[#0a0a0a] border-2 border-[#404040] rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#ffd700] focus:ring-2 focus:ring-[#ffd700]/20 transition-all"
+
+function EditCharacterProfileModal({
+    characterName,
+    onClose,
+    onSaved,
+}: EditCharacterProfileModalProps) {
+    const [profile, setProfile] = useState<CharacterProfile>(emptyProfile)
+    const [loading, setLoading] = useState(false)
+    const [saving, setSaving] = useState(false)
+    const [error, setError] = useState('')
+
+    useEffect(() => {
+        if (!characterName) return
+
+        setLoading(true)
+        setError('')
+        api.get<{ data: CharacterProfile }>(`/characters/${encodeURIComponent(characterName)}/profile`)
+            .then(response => setProfile(response.data || emptyProfile))
+            .catch((err: any) => setError(err.message || 'Failed to load profile'))
+            .finally(() => setLoading(false))
+    }, [characterName])
+
+    const handleSubmit = useCallback(async () => {
+        if (!characterName) return
+
+        setSaving(true)
+        setError('')
+        try {
+            await api.put(`/characters/${encodeURIComponent(characterName)}/profile`, profile)
+            onSaved()
+            onClose()
+        } catch (err: any) {
+            setError(err.message || 'Failed to save profile')
+        } finally {
+            setSaving(false)
+        }
+    }, [characterName, profile, onSaved, onClose])
+
+    const handleClose = useCallback(() => {
+        setProfile(emptyProfile)
+        setError('')
+        onClose()
+    }, [onClose])
+
+    if (!characterName) return null
+
+    return (
+        <div className="fixed inset-0 bg-black/70 backdrop-blur-sm flex items-center justify-center z-50 p-4">
+            <div className="bg-[#1a1a1a] border-2 border-[#ffd700]/60 rounded-lg p-6 max-w-lg w-full shadow-2xl">
+                <h3 className="text-[#ffd700] font-bold text-xl mb-4">Edit profile of {characterName}</h3>
+
+                {loading ? (
+                    <p className="text-[#d0d0d0] mb-6">Loading...</p>
+                ) : (
+                    <div className="space-y-4 mb-6">
+                        <div className="grid grid-cols-1 sm:grid-cols-2 gap-4">
+                            <div>
+                                <label className="text-[#ffd700] text-sm font-bold block mb-2">Real name:</label>
+                                <input
+                                    type="text"
+                                    value={profile.realName}
+                                    maxLength={MAX_FIELD_LENGTH}
+                                    onChange={(e) => setProfile(prev => ({ ...prev, realName: e.target.value }))}
+                                    className={inputClassName}
+                                    disabled={saving}
+                                />
+                            </div>
+                            <div>
+                                <label className="text-[#ffd700] text-sm font-bold block mb-2">Location:</label>
+                                <input
+                                    type="text"
+                                    value={profile.location}
+                                    maxLength={MAX_FIELD_LENGTH}
+                                    onChange={(e) => setProfile(prev => ({ ...prev, location: e.target.value }))}
+                                    className={inputClassName}
+                                    disabled={saving}
+                                />
+                            </div>
+                        </div>
+
+                        <div>
+                            <label className="text-[#ffd700] text-sm font-bold block mb-2">Comment:</label>
+                            <textarea
+                                value={profile.comment}
+                                maxLength={MAX_COMMENT_LENGTH}
+                                rows={6}
+                                onChange={(e) => setProfile(prev => ({ ...prev, comment: e.target.value }))}
+                                className={`${inputClassName} resize-y`}
+                                disabled={saving}
+                            />
+                            <p className="text-[#888] text-xs mt-1 text-right">
+                                {profile.comment.length}/{MAX_COMMENT_LENGTH}
+                            </p>
+                        </div>
+
+                        <label className="flex items-center gap-2 text-[#e0e0e0] text-sm">
+                            <input
+                                type="checkbox"
+                                checked={profile.isMainCharacter}
+                                onChange={(e) => setProfile(prev => ({ ...prev, isMainCharacter: e.target.checked }))}
+                                disabled={saving}
+                            />
+                            This is my main character
+                        </label>
+
+                        {error && (
+                            <p className="text-red-400 text-sm flex items-center gap-1">
+                                <span>⚠️</span>
+                                <span>{error}</span>
+                            </p>
+                        )}
+                    </div>
+                )}
+
+                <div className="flex gap-3">
+                    <button
+                        onClick={handleSubmit}
+                        disabled={loading || saving}
+                        className="flex-1 bg-[#ffd700] hover:bg-[#ffed4e] disabled:bg-gray-600 disabled:cursor-not-allowed text-[#1a1a1a] font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        {saving ? 'Saving...' : 'Save'}
+                    </button>
+                    <button
+                        onClick={handleClose}
+                        disabled={saving}
+                        className="flex-1 bg-[#404040] hover:bg-[#505050] disabled:bg-gray-600 disabled:cursor-not-allowed text-white font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        Cancel
+                    </button>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default memo(EditCharacterProfileModal)
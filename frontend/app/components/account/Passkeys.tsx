@@ -0,0 +1,288 @@
+'use client'
+
+import { useState, useEffect, useCallback } from 'react'
+import { api } from '../../services/api'
+import { isPasskeySupported, createPasskey, type PasskeyCeremony } from '../../utils/passkey'
+import React from 'react'
+
+interface Passkey {
+    id: number
+    name: string
+    createdAt: number
+    lastUsedAt: number | null
+}
+
+interface PasskeysResponse {
+    data: Passkey[]
+}
+
+const formatDate = (timestamp: number) =>
+    new Date(timestamp * 1000).toLocaleString('en-US', {
+        month: 'short',
+        day: 'numeric',
+        year: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+    })
+
+const inputClassName = "w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+
+const Passkeys = React.memo(() => {
+    const [passkeys, setPasskeys] = useState<Passkey[]>([])
+    const [loading, setLoading] = useState(true)
+    const [adding, setAdding] = useState(false)
+    const [removingId, setRemovingId] = useState<number | null>(null)
+    const [supported, setSupported] = useState(true)
+    const [error, setError] = useState<string | null>(null)
+    const [success, setSuccess] = useState<string | null>(null)
+    const [form, setForm] = useState({ name: '', password: '' })
+    const [confirming, setConfirming] = useState<Passkey | null>(null)
+    const [removeForm, setRemoveForm] = useState({ password: '', token: '' })
+    const [twoFactorEnabled, setTwoFactorEnabled] = useState(false)
+
+    const fetchPasskeys = useCallback(async () => {
+        try {
+            setLoading(true)
+            const response = await api.get<PasskeysResponse>('/account/passkeys')
+            setPasskeys(response.data || [])
+        } catch (err: any) {
+            setError(err.message || 'Failed to fetch passkeys')
+        } finally {
+            setLoading(false)
+        }
+    }, [])
+
+    useEffect(() => {
+        setSupported(isPasskeySupported())
+        fetchPasskeys()
+        // Removing a passkey asks for the 2FA code too when 2FA is enabled
+        api.get<{ enabled: boolean }>('/account/2fa/status')
+            .then(response => setTwoFactorEnabled(!!response.enabled))
+            .catch(() => setTwoFactorEnabled(false))
+    }, [fetchPasskeys])
+
+    const handleAdd = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        if (!form.password) {
+            setError('Password is required')
+            return
+        }
+
+        try {
+            setAdding(true)
+            setError(null)
+            const begin = await api.post<{ data: PasskeyCeremony }>('/account/passkeys/register/begin', {
+                password: form.password,
+            })
+            const credential = await createPasskey(begin.data.options)
+            await api.post('/account/passkeys/register/finish', {
+                ceremony: begin.data.ceremony,
+                name: form.name,
+                credential,
+            })
+            setSuccess('Passkey added successfully.')
+            setForm({ name: '', password: '' })
+            await fetchPasskeys()
+        } catch (err: any) {
+            setError(err.name === 'NotAllowedError' ? 'Passkey creation was cancelled' : (err.message || 'Failed to add passkey'))
+        } finally {
+            setAdding(false)
+        }
+    }, [form, fetchPasskeys])
+
+    const handleRemove = useCallback(async (e: React.FormEvent) => {
+        e.preventDefault()
+        if (!confirming) {
+            return
+        }
+        if (!removeForm.password) {
+            setError('Password is required')
+            return
+        }
+        if (twoFactorEnabled && !removeForm.token) {
+            setError('2FA code is required')
+            return
+        }
+
+        try {
+            setRemovingId(confirming.id)
+            setError(null)
+            await api.delete(`/account/passkeys/${confirming.id}`, {
+                password: removeForm.password,
+                token: twoFactorEnabled ? removeForm.token : undefined,
+            })
+            setSuccess('Passkey removed successfully. Your other sessions were signed out.')
+            setPasskeys(prev => prev.filter(p => p.id !== confirming.id))
+            setConfirming(null)
+            setRemoveForm({ password: '', token: '' })
+        } catch (err: any) {
+            setError(err.message || 'Failed to remove passkey')
+        } finally {
+            setRemovingId(null)
+        }
+    }, [confirming, removeForm, twoFactorEnabled])
+
+    useEffect(() => {
+        if (error || success) {
+            const timer = setTimeout(() => {
+                setError(null)
+                setSuccess(null)
+            }, 5000)
+            return () => clearTimeout(timer)
+        }
+    }, [error, success])
+
+    return (
+        <div className="space-y-6">
+            <h2 className="text-2xl font-bold text-[#ffd700] mb-4">Passkeys</h2>
+
+            {/* Error/Success Messages */}
+            {error && (
+                <div className="bg-red-900/20 border border-red-500/50 rounded-lg p-4">
+                    <p className="text-red-400 text-sm">{error}</p>
+                </div>
+            )}
+            {success && (
+                <div className="bg-green-900/20 border border-green-500/50 rounded-lg p-4">
+                    <p className="text-green-400 text-sm">{success}</p>
+                </div>
+            )}
+
+            <p className="text-[#d0d0d0] text-sm">
+                A passkey lets you sign in with your fingerprint, face or device PIN instead of your password.
+                If two-factor authentication is enabled, it can also be used instead of the 2FA code.
+            </p>
+
+            {loading ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6">
+                    <p className="text-[#d0d0d0]">Loading...</p>
+                </div>
+            ) : passkeys.length === 0 ? (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 text-[#b0b0b0] text-sm">
+                    You have no passkeys yet.
+                </div>
+            ) : (
+                <div className="space-y-3">
+                    {passkeys.map((passkey) => (
+                        <div
+                            key={passkey.id}
+                            className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 flex items-center justify-between gap-4"
+                        >
+                            <div className="min-w-0">
+                                <p className="text-[#e0e0e0] text-sm font-medium truncate">{passkey.name}</p>
+                                <p className="text-[#888] text-xs mt-1">
+                                    Added {formatDate(passkey.createdAt)} · {passkey.lastUsedAt ? `Last used ${formatDate(passkey.lastUsedAt)}` : 'Never used'}
+                                </p>
+                            </div>
+                            <button
+                                onClick={() => {
+                                    setConfirming(passkey)
+                                    setRemoveForm({ password: '', token: '' })
+                                }}
+                                disabled={removingId === passkey.id}
+                                className="bg-[#404040] hover:bg-[#505050] disabled:bg-[#303030] disabled:cursor-not-allowed text-white font-bold py-2 px-4 rounded-lg transition-all whitespace-nowrap"
+                            >
+                                {removingId === passkey.id ? 'Removing...' : 'Remove'}
+                            </button>
+                        </div>
+                    ))}
+                </div>
+            )}
+
+            {confirming && (
+                <form onSubmit={handleRemove} className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-4">
+                    <h3 className="text-[#ffd700] font-bold">Remove &quot;{confirming.name}&quot;</h3>
+                    <p className="text-[#d0d0d0] text-sm">
+                        Confirm with your password{twoFactorEnabled ? ' and a 2FA code' : ''}. Your other sessions will be signed out.
+                    </p>
+                    <div className="max-w-md space-y-4">
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Password</label>
+                            <input
+                                type="password"
+                                value={removeForm.password}
+                                onChange={(e) => setRemoveForm(prev => ({ ...prev, password: e.target.value }))}
+                                placeholder="Enter your password"
+                                className={inputClassName}
+                            />
+                        </div>
+                        {twoFactorEnabled && (
+                            <div>
+                                <label className="block text-[#d0d0d0] text-sm font-medium mb-2">2FA Code</label>
+                                <input
+                                    type="text"
+                                    maxLength={11}
+                                    value={removeForm.token}
+                                    onChange={(e) => setRemoveForm(prev => ({ ...prev, token: e.target.value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase() }))}
+                                    placeholder="Code from your app or a backup code"
+                                    className={inputClassName}
+                                />
+                            </div>
+                        )}
+                    </div>
+                    <div className="flex gap-3">
+                        <button
+                            type="submit"
+                            disabled={removingId !== null}
+                            className="bg-red-700 hover:bg-red-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                        >
+                            {removingId !== null ? 'Removing...' : 'Remove Passkey'}
+                        </button>
+                        <button
+                            type="button"
+                            onClick={() => setConfirming(null)}
+                            disabled={removingId !== null}
+                            className="bg-[#404040] hover:bg-[#505050] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                        >
+                            Cancel
+                        </button>
+                    </div>
+                </form>
+            )}
+
+            {supported ? (
+                <form onSubmit={handleAdd} className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6 space-y-4">
+                    <h3 className="text-[#ffd700] font-bold">Add a Passkey</h3>
+                    <div className="max-w-md space-y-4">
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Name</label>
+                            <input
+                                type="text"
+                                maxLength={64}
+                                value={form.name}
+                                onChange={(e) => setForm(prev => ({ ...prev, name: e.target.value }))}
+                                placeholder="e.g. My phone"
+                                className={inputClassName}
+                            />
+                        </div>
+                        <div>
+                            <label className="block text-[#d0d0d0] text-sm font-medium mb-2">Password</label>
+                            <input
+                                type="password"
+                                value={form.password}
+                                onChange={(e) => setForm(prev => ({ ...prev, password: e.target.value }))}
+                                placeholder="Enter your password"
+                                className={inputClassName}
+                            />
+                        </div>
+                    </div>
+                    <button
+                        type="submit"
+                        disabled={adding}
+                        className="bg-green-700 hover:bg-green-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                    >
+                        {adding ? 'Waiting for your device...' : 'Add Passkey'}
+                    </button>
+                </form>
+            ) : (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-4 text-[#b0b0b0] text-sm">
+                    This browser doesn't support passkeys.
+                </div>
+            )}
+        </div>
+    )
+})
+
+Passkeys.displayName = 'Passkeys'
+
+export default Passkeys
@@ -0,0 +1,114 @@
+'use client'
+
+import { useState, useCallback, memo } from 'react'
+
+interface RenameCharacterModalProps {
+    characterName: string | null
+    price: number
+    onClose: () => void
+    onConfirm: (newName: string) => Promise<void>
+    isRenaming: boolean
+}
+
+function RenameCharacterModal({
+    characterName,
+    price,
+    onClose,
+    onConfirm,
+    isRenaming,
+}: RenameCharacterModalProps) {
+    const [newName, setNewName] = useState('')
+    const [error, setError] = useState('')
+
+    const handleSubmit = useCallback(async () => {
+        const name = newName.trim()
+        if (name.length < 3 || name.length > 20) {
+            setError('Character name must be between 3 and 20 characters')
+            return
+        }
+
+        setError('')
+        try {
+            await onConfirm(name)
+            setNewName('')
+        } catch (err: any) {
+            setError(err.message || 'Failed to rename character')
+        }
+    }, [newName, onConfirm])
+
+    const handleClose = useCallback(() => {
+        setNewName('')
+        setError('')
+        onClose()
+    }, [onClose])
+
+    if (!characterName) return null
+
+    return (
+        <div className="fixed inset-0 bg-black/70 backdrop-blur-sm flex items-center justify-center z-50 p-4">
+            <div className="bg-[#1a1a1a] border-2 border-[#ffd700]/60 rounded-lg p-6 max-w-md w-full shadow-2xl">
+                <h3 className="text-[#ffd700] font-bold text-xl mb-4">Rename {characterName}</h3>
+
+                <div className="space-y-4 mb-6">
+                    <p className="text-[#e0e0e0] text-sm">
+                        The character must be logged out. Its current name stays listed as a former name on the character page.
+                        {price > 0 ? ` A rename costs ${price} coins.` : ''}
+                    </p>
+
+                    <div>
+                        <label className="text-[#ffd700] text-sm font-bold block mb-2">
+                            New name:
+                        </label>
+                        <input
+                            type="text"
+                            value={newName}
+                            onChange={(e) => {
+                                setNewName(e.target.value)
+                                if (error) setError('')
+                            }}
+                            placeholder="New character name"
+                            maxLength={20}
+                            className={`w-full bg-[#0a0a0a] border-2 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:ring-2 transition-all ${
+                                error
+                                    ? 'border-red-500 focus:border-red-500 focus:ring-red-500/20'
+                                    : 'border-[#404040] focus:border-[#ffd700] focus:ring-[#ffd700]/20'
+                            }`}
+                            autoFocus
+                            disabled={isRenaming}
+                            onKeyDown={(e) => {
+                                if (e.key === 'Enter' && newName && !isRenaming) {
+                                    handleSubmit()
+                                }
+                            }}
+                        />
+                        {error && (
+                            <p className="text-red-400 text-sm mt-2 flex items-center gap-1">
+                                <span>⚠️</span>
+                                <span>{error}</span>
+                            </p>
+                        )}
+                    </div>
+                </div>
+
+                <div className="flex gap-3">
+                    <button
+                        onClick={handleSubmit}
+                        disabled={!newName.trim() || isRenaming}
+                        className="flex-1 bg-[#ffd700] hover:bg-[#ffed4e] disabled:bg-gray-600 disabled:cursor-not-allowed text-[#1a1a1a] font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        {isRenaming ? 'Renaming...' : 'Rename'}
+                    </button>
+                    <button
+                        onClick={handleClose}
+                        disabled={isRenaming}
+                        className="flex-1 bg-[#404040] hover:bg-[#505050] disabled:bg-gray-600 disabled:cursor-not-allowed text-white font-bold py-3 px-6 rounded-lg transition-all"
+                    >
+                        Cancel
+                    </button>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default memo(RenameCharacterModal)
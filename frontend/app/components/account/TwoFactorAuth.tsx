@@ -6,17 +6,39 @@ import React from 'react'
 
 interface TwoFactorStatus {
     enabled: boolean
+    backupCodesRemaining: number
 }
 
 interface Enable2FAResponse {
     secret: string
     qrCode: string
     otpauthUrl: string
+    backupCodes: string[]
     message: string
 }
 
+interface BackupCodesResponse {
+    data: {
+        backupCodes: string[]
+    }
+}
+
+const BackupCodesList = ({ codes }: { codes: string[] }) => (
+    <div className="bg-[#0a0a0a] border border-[#ffd700]/40 rounded-lg p-4 mb-4">
+        <p className="text-[#ffd700] text-sm font-bold mb-2">Backup codes</p>
+        <p className="text-[#888] text-xs mb-3">
+            Each code can be used once instead of a code from your app. Store them somewhere safe, they will not be shown again.
+        </p>
+        <div className="grid grid-cols-2 gap-2">
+            {codes.map(code => (
+                <code key={code} className="text-[#e0e0e0] text-sm font-mono">{code}</code>
+            ))}
+        </div>
+    </div>
+)
+
 const TwoFactorAuth = React.memo(() => {
-    const [status, setStatus] = useState<TwoFactorStatus>({ enabled: false })
+    const [status, setStatus] = useState<TwoFactorStatus>({ enabled: false, backupCodesRemaining: 0 })
     const [loading, setLoading] = useState(true)
     const [enabling, setEnabling] = useState(false)
     const [verifying, setVerifying] = useState(false)
@@ -30,6 +52,10 @@ const TwoFactorAuth = React.memo(() => {
     const [verifyToken, setVerifyToken] = useState('')
     const [disablePassword, setDisablePassword] = useState('')
     const [disableToken, setDisableToken] = useState('')
+    const [regeneratePassword, setRegeneratePassword] = useState('')
+    const [regenerateToken, setRegenerateToken] = useState('')
+    const [regenerating, setRegenerating] = useState(false)
+    const [newBackupCodes, setNewBackupCodes] = useState<string[] | null>(null)
 
     const fetchStatus = useCallback(async () => {
         try {
@@ -96,8 +122,8 @@ const TwoFactorAuth = React.memo(() => {
             setError('Password is required')
             return
         }
-        if (!disableToken || disableToken.length !== 6) {
-            setError('Please enter a valid 6-digit token')
+        if (!disableToken || disableToken.length < 6) {
+            setError('Please enter a valid 6-digit token or backup code')
             return
         }
 
@@ -119,6 +145,30 @@ const TwoFactorAuth = React.memo(() => {
         }
     }, [disablePassword, disableToken, fetchStatus])
 
+    const handleRegenerate = useCallback(async () => {
+        if (!regeneratePassword || regenerateToken.length < 6) {
+            setError('Password and a 6-digit token or backup code are required')
+            return
+        }
+
+        try {
+            setRegenerating(true)
+            setError(null)
+            const response = await api.post<BackupCodesResponse>('/account/2fa/backup-codes', {
+                password: regeneratePassword,
+                token: regenerateToken,
+            })
+            setNewBackupCodes(response.data.backupCodes)
+            setRegeneratePassword('')
+            setRegenerateToken('')
+            await fetchStatus()
+        } catch (err: any) {
+            setError(err.message || 'Failed to generate backup codes')
+        } finally {
+            setRegenerating(false)
+        }
+    }, [regeneratePassword, regenerateToken, fetchStatus])
+
     useEffect(() => {
         if (error || success) {
             const timer = setTimeout(() => {
@@ -240,6 +290,10 @@ const TwoFactorAuth = React.memo(() => {
                         <code className="text-[#3b82f6] text-sm break-all">{qrCodeData.secret}</code>
                     </div>
 
+                    {qrCodeData.backupCodes && qrCodeData.backupCodes.length > 0 && (
+                        <BackupCodesList codes={qrCodeData.backupCodes} />
+                    )}
+
                     {/* Verify Token Input */}
                     <div className="max-w-md">
                         <label className="block text-[#d0d0d0] text-sm font-medium mb-2">
@@ -291,7 +345,7 @@ const TwoFactorAuth = React.memo(() => {
                                 </p>
                             </div>
                             <p className="text-[#d0d0d0] text-sm mb-4">
-                                To disable two-factor authentication, enter your password and a 6-digit code from your authenticator app.
+                                To disable two-factor authentication, enter your password and a 6-digit code from your authenticator app or a backup code.
                             </p>
                             <div className="space-y-4 max-w-md">
                                 <div>
@@ -313,9 +367,9 @@ const TwoFactorAuth = React.memo(() => {
                                     <input
                                         type="text"
                                         value={disableToken}
-                                        onChange={(e) => setDisableToken(e.target.value.replace(/\D/g, '').slice(0, 6))}
+                                        onChange={(e) => setDisableToken(e.target.value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase().slice(0, 11))}
                                         placeholder="000000"
-                                        maxLength={6}
+                                        maxLength={11}
                                         className="w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666] text-center text-xl tracking-widest"
                                     />
                                 </div>
@@ -323,7 +377,7 @@ const TwoFactorAuth = React.memo(() => {
                         </div>
                         <button
                             onClick={handleDisable}
-                            disabled={disabling || !disablePassword || disableToken.length !== 6}
+                            disabled={disabling || !disablePassword || disableToken.length < 6}
                             className="bg-red-700 hover:bg-red-600 disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap ml-4"
                         >
                             {disabling ? 'Disabling...' : 'Disable 2FA'}
@@ -331,6 +385,46 @@ const TwoFactorAuth = React.memo(() => {
                     </div>
                 </div>
             )}
+
+            {/* 2FA Enabled - Backup Codes Section */}
+            {status.enabled && !showQRCode && (
+                <div className="bg-[#1a1a1a] border border-[#404040]/60 rounded-lg p-6">
+                    <h3 className="text-[#ffd700] font-bold mb-3">Backup Codes</h3>
+                    <p className="text-[#d0d0d0] text-sm mb-4">
+                        You have <strong className={status.backupCodesRemaining > 2 ? 'text-green-400' : 'text-red-400'}>{status.backupCodesRemaining}</strong> unused backup codes.
+                        Generating new codes invalidates the old ones.
+                    </p>
+
+                    {newBackupCodes && <BackupCodesList codes={newBackupCodes} />}
+
+                    <div className="flex items-end gap-4 flex-wrap">
+                        <div className="space-y-4 max-w-md flex-1">
+                            <input
+                                type="password"
+                                value={regeneratePassword}
+                                onChange={(e) => setRegeneratePassword(e.target.value)}
+                                placeholder="Enter your password"
+                                className="w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+                            />
+                            <input
+                                type="text"
+                                value={regenerateToken}
+                                onChange={(e) => setRegenerateToken(e.target.value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase().slice(0, 11))}
+                                placeholder="2FA token or backup code"
+                                maxLength={11}
+                                className="w-full bg-[#0a0a0a] border-2 border-[#404040]/60 rounded-lg px-4 py-2 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666]"
+                            />
+                        </div>
+                        <button
+                            onClick={handleRegenerate}
+                            disabled={regenerating || !regeneratePassword || regenerateToken.length < 6}
+                            className="bg-[#3b82f6] hover:bg-[#2563eb] disabled:bg-[#404040] disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap"
+                        >
+                            {regenerating ? 'Generating...' : 'Generate New Codes'}
+                        </button>
+                    </div>
+                </div>
+            )}
         </div>
     )
 })
@@ -0,0 +1,55 @@
+'use client'
+
+import { memo, useState, useCallback } from 'react'
+import { api } from '../../services/api'
+
+function VerificationBanner({ email }: { email: string }) {
+    const [isSending, setIsSending] = useState(false)
+    const [message, setMessage] = useState<string | null>(null)
+    const [error, setError] = useState<string | null>(null)
+
+    const handleResend = useCallback(async () => {
+        try {
+            setIsSending(true)
+            setError(null)
+            const response = await api.post<{ message: string }>('/account/verify/resend', {})
+            setMessage(response.message || 'A new verification link has been sent')
+        } catch (err: any) {
+            setError(err.message || 'Failed to send verification email')
+        } finally {
+            setIsSending(false)
+        }
+    }, [])
+
+    return (
+        <div className="bg-yellow-900/30 border-2 border-yellow-600 rounded-lg p-4 mb-6">
+            <div className="flex items-start justify-between">
+                <div className="flex-1">
+                    <h3 className="text-yellow-400 font-bold mb-2 flex items-center gap-2">
+                        <span>✉️</span>
+                        <span>Verify Your Email Address</span>
+                    </h3>
+                    <p className="text-[#e0e0e0] text-sm mb-2">
+                        Open the activation link sent to <strong className="text-yellow-400">{email}</strong> to create characters and log in to the game.
+                    </p>
+                    {message && <p className="text-green-400 text-xs">{message}</p>}
+                    {error && <p className="text-red-400 text-xs">{error}</p>}
+                    {!message && !error && (
+                        <p className="text-[#888] text-xs">
+                            Accounts that are not activated in time are deleted.
+                        </p>
+                    )}
+                </div>
+                <button
+                    onClick={handleResend}
+                    disabled={isSending}
+                    className="bg-[#3b82f6] hover:bg-[#2563eb] disabled:bg-gray-600 disabled:cursor-not-allowed text-white font-bold py-2 px-6 rounded-lg transition-all whitespace-nowrap ml-4"
+                >
+                    {isSending ? 'Sending...' : 'Resend Email'}
+                </button>
+            </div>
+        </div>
+    )
+}
+
+export default memo(VerificationBanner)
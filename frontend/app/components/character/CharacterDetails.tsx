@@ -1,6 +1,6 @@
 'use client'
 
-import type { CharacterDetails, EquipmentItem } from '../../types/character'
+import type { CharacterDetails, EquipmentItem, EquipmentSlots } from '../../types/character'
 import { getItemImageUrl } from '../../utils/item'
 import { makeOutfit } from '../../utils/outfit'
 
@@ -24,8 +24,9 @@ function EquipmentSlot({ item, placeholder, placeholderSize = 'base' }: Equipmen
     <div className="w-8 h-8 bg-[#3a3a3a] border border-[#1a1a1a] rounded flex items-center justify-center shadow-inner relative">
       {item ? (
         <img
-          src={getItemImageUrl(item.itemId)}
-          alt={`Item ${item.itemId}`}
+          src={getItemImageUrl(item.clientId || item.itemId)}
+          alt={item.name || `Item ${item.itemId}`}
+          title={item.name ? (item.count > 1 ? `${item.count} ${item.name}` : item.name) : undefined}
           className="w-full h-full object-contain"
           onError={handleImageError}
         />
@@ -49,16 +50,8 @@ export default function CharacterDetailsSection({ character }: { character: Char
   const experiencePercent =
     experienceForNextLevel > 0 ? ((character.experience || 0) / experienceForNextLevel) * 100 : 0
 
-  const equipmentMap = (() => {
-    if (!character.equipment) return new Map<number, EquipmentItem>()
-    const map = new Map<number, EquipmentItem>()
-    character.equipment.forEach(item => {
-      map.set(item.slot, item)
-    })
-    return map
-  })()
-
-  const getItemForSlot = (slot: number): EquipmentItem | null => equipmentMap.get(slot) || null
+  const getItemForSlot = (slot: keyof EquipmentSlots): EquipmentItem | null =>
+    character.equipmentSlots?.[slot] || null
 
   return (
     <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
@@ -193,22 +186,22 @@ export default function CharacterDetailsSection({ character }: { character: Char
               <h3 className="text-[#ffd700] text-xs font-bold mb-1.5 text-center">Inventory:</h3>
               <div className="flex gap-1 justify-center mb-1.5">
                 <div className="flex flex-col gap-1 mt-[0.75rem]">
-                  <EquipmentSlot item={getItemForSlot(2)} placeholder="💎" placeholderSize="lg" />
-                  <EquipmentSlot item={null} placeholder="🧤" />
-                  <EquipmentSlot item={getItemForSlot(9)} placeholder="💍" />
+                  <EquipmentSlot item={getItemForSlot('necklace')} placeholder="💎" placeholderSize="lg" />
+                  <EquipmentSlot item={getItemForSlot('leftHand')} placeholder="🗡️" />
+                  <EquipmentSlot item={getItemForSlot('ring')} placeholder="💍" />
                 </div>
 
                 <div className="flex flex-col gap-1">
-                  <EquipmentSlot item={getItemForSlot(1)} placeholder="🪖" />
-                  <EquipmentSlot item={getItemForSlot(4)} placeholder="🦺" />
-                  <EquipmentSlot item={getItemForSlot(7)} placeholder="👖" />
-                  <EquipmentSlot item={getItemForSlot(8)} placeholder="🥾" />
+                  <EquipmentSlot item={getItemForSlot('head')} placeholder="🪖" />
+                  <EquipmentSlot item={getItemForSlot('armor')} placeholder="🦺" />
+                  <EquipmentSlot item={getItemForSlot('legs')} placeholder="👖" />
+                  <EquipmentSlot item={getItemForSlot('feet')} placeholder="🥾" />
                 </div>
 
                 <div className="flex flex-col gap-1 mt-[0.75rem]">
-                  <EquipmentSlot item={getItemForSlot(3)} placeholder="🎒" />
-                  <EquipmentSlot item={null} placeholder="🛡️" />
-                  <EquipmentSlot item={getItemForSlot(10)} placeholder="➡️" />
+                  <EquipmentSlot item={getItemForSlot('backpack')} placeholder="🎒" />
+                  <EquipmentSlot item={getItemForSlot('rightHand')} placeholder="🛡️" />
+                  <EquipmentSlot item={getItemForSlot('ammo')} placeholder="➡️" />
                 </div>
               </div>
 
@@ -0,0 +1,60 @@
+'use client'
+
+import type { CharacterDetails } from '../../types/character'
+
+export default function CharacterProgress({ character }: { character: CharacterDetails }) {
+  const { achievements, bestiary, charms } = character
+
+  if (!achievements && !bestiary && !charms) return null
+
+  return (
+    <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+      <h2 className="text-[#ffd700] text-xl sm:text-2xl font-bold mb-4 pb-3 border-b border-[#404040]/40">
+        Achievements &amp; Bestiary
+      </h2>
+
+      <div className="grid grid-cols-1 md:grid-cols-3 gap-4 mb-4">
+        {achievements && (
+          <div className="bg-[#1a1a1a] rounded-lg border border-[#404040]/60 p-3">
+            <div className="text-[#d0d0d0] text-sm mb-1">Achievement Points</div>
+            <div className="text-[#ffd700] font-bold text-xl">{achievements.points}</div>
+            <div className="text-[#888] text-xs">{achievements.completed} of {achievements.total} achievements</div>
+          </div>
+        )}
+        {bestiary && (
+          <div className="bg-[#1a1a1a] rounded-lg border border-[#404040]/60 p-3">
+            <div className="text-[#d0d0d0] text-sm mb-1">Bestiary</div>
+            <div className="text-[#ffd700] font-bold text-xl">{bestiary.completed} / {bestiary.total}</div>
+            <div className="text-[#888] text-xs">
+              {bestiary.known} creatures known, {bestiary.kills.toLocaleString('pt-BR')} kills
+            </div>
+          </div>
+        )}
+        {charms && (
+          <div className="bg-[#1a1a1a] rounded-lg border border-[#404040]/60 p-3">
+            <div className="text-[#d0d0d0] text-sm mb-1">Charms</div>
+            <div className="text-[#ffd700] font-bold text-xl">{charms.unlocked.length} / {charms.total}</div>
+            {charms.unlocked.length > 0 && (
+              <div className="text-[#888] text-xs">{charms.unlocked.join(', ')}</div>
+            )}
+          </div>
+        )}
+      </div>
+
+      {achievements && achievements.achievements.length > 0 && (
+        <ul className="space-y-1">
+          {achievements.achievements.map((achievement) => (
+            <li key={achievement.name} className="flex items-start gap-2 text-sm">
+              <span className="text-[#ffd700] font-bold w-6 text-right flex-shrink-0">{achievement.points}</span>
+              <span className="text-[#e0e0e0]">
+                {achievement.name}
+                {achievement.secret && <span className="ml-1 text-xs text-[#a855f7]">(secret)</span>}
+                {achievement.description && <span className="text-[#888]"> – {achievement.description}</span>}
+              </span>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  )
+}
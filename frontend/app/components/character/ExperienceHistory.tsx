@@ -0,0 +1,75 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { api } from '../../services/api'
+import type { ExperienceHistoryResponse } from '../../types/character'
+
+const CHART_WIDTH = 600
+const CHART_HEIGHT = 160
+
+const formatNumber = (num: number): string => num.toLocaleString('pt-BR')
+
+export default function ExperienceHistory({ characterName }: { characterName: string }) {
+  const [history, setHistory] = useState<ExperienceHistoryResponse | null>(null)
+
+  useEffect(() => {
+    api.get<{ data: ExperienceHistoryResponse }>(`/characters/${characterName}/history?days=30`, { public: true })
+      .then(response => setHistory(response.data))
+      .catch(() => setHistory(null))
+  }, [characterName])
+
+  if (!history || history.history.length < 2) return null
+
+  const points = history.history
+  const first = points[0]
+  const last = points[points.length - 1]
+  const minExp = Math.min(...points.map(p => p.experience))
+  const maxExp = Math.max(...points.map(p => p.experience))
+  const range = maxExp - minExp || 1
+
+  const firstTime = new Date(first.day).getTime()
+  const timeRange = new Date(last.day).getTime() - firstTime || 1
+
+  // Days without a snapshot kept the previous value, so the line is drawn as steps
+  const path = points
+    .map((point, idx) => {
+      const x = ((new Date(point.day).getTime() - firstTime) / timeRange) * CHART_WIDTH
+      const y = CHART_HEIGHT - ((point.experience - minExp) / range) * CHART_HEIGHT
+      if (idx === 0) return `M ${x} ${y}`
+      return `H ${x} V ${y}`
+    })
+    .join(' ')
+
+  return (
+    <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+      <h2 className="text-[#ffd700] text-xl sm:text-2xl font-bold mb-4 pb-3 border-b border-[#404040]/40">
+        Progression
+      </h2>
+      <div className="flex flex-wrap gap-6 mb-4 text-sm">
+        <div>
+          <span className="text-[#888]">Levels gained:</span>{' '}
+          <span className="text-[#e0e0e0] font-semibold">{last.level - first.level}</span>
+        </div>
+        <div>
+          <span className="text-[#888]">Experience gained:</span>{' '}
+          <span className="text-[#e0e0e0] font-semibold">{formatNumber(last.experience - first.experience)}</span>
+        </div>
+        <div>
+          <span className="text-[#888]">Since:</span>{' '}
+          <span className="text-[#e0e0e0] font-semibold">{first.day}</span>
+        </div>
+      </div>
+      <svg
+        viewBox={`-4 -4 ${CHART_WIDTH + 8} ${CHART_HEIGHT + 8}`}
+        className="w-full h-40 bg-[#1a1a1a] rounded-lg border border-[#404040]/60"
+        preserveAspectRatio="none"
+      >
+        <path d={path} fill="none" stroke="#ffd700" strokeWidth={2} vectorEffect="non-scaling-stroke" />
+      </svg>
+      <div className="flex justify-between text-xs text-[#888] mt-1">
+        <span>{first.day} · Level {first.level}</span>
+        <span>{last.day} · Level {last.level}</span>
+      </div>
+    </div>
+  )
+}
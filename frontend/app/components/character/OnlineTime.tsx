@@ -0,0 +1,66 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { api } from '../../services/api'
+import { formatDateTime } from '../../utils/date'
+import type { CharacterOnlineTime } from '../../types/character'
+
+const formatHours = (hours: number): string => {
+  const h = Math.floor(hours)
+  const m = Math.round((hours - h) * 60)
+  return h > 0 ? `${h}h ${m}m` : `${m}m`
+}
+
+export default function OnlineTime({ characterName }: { characterName: string }) {
+  const [onlineTime, setOnlineTime] = useState<CharacterOnlineTime | null>(null)
+
+  useEffect(() => {
+    api.get<{ data: CharacterOnlineTime }>(`/characters/${characterName}/online-time?days=14`, { public: true })
+      .then(response => setOnlineTime(response.data))
+      .catch(() => setOnlineTime(null))
+  }, [characterName])
+
+  if (!onlineTime || (onlineTime.history.length === 0 && onlineTime.sessions.length === 0)) return null
+
+  const maxHours = Math.max(...onlineTime.history.map(d => d.hours), 1)
+
+  return (
+    <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl">
+      <h2 className="text-[#ffd700] text-xl sm:text-2xl font-bold mb-4 pb-3 border-b border-[#404040]/40">
+        Online Time
+      </h2>
+      <p className="text-[#d0d0d0] text-sm mb-4">
+        <span className="text-[#888]">Last {onlineTime.days} days:</span>{' '}
+        <span className="text-[#e0e0e0] font-semibold">{formatHours(onlineTime.totalHours)}</span>
+      </p>
+
+      {onlineTime.history.length > 0 && (
+        <div className="space-y-1 mb-4">
+          {onlineTime.history.map((day) => (
+            <div key={day.day} className="flex items-center gap-3 text-xs">
+              <span className="text-[#888] w-20 flex-shrink-0">{day.day}</span>
+              <div className="flex-1 bg-[#1a1a1a] rounded h-3 overflow-hidden">
+                <div className="bg-[#3b82f6] h-full" style={{ width: `${(day.hours / maxHours) * 100}%` }} />
+              </div>
+              <span className="text-[#e0e0e0] w-16 text-right">{formatHours(day.hours)}</span>
+            </div>
+          ))}
+        </div>
+      )}
+
+      {onlineTime.sessions.length > 0 && (
+        <div className="pt-4 border-t border-[#404040]/40">
+          <span className="text-[#888] text-sm">Latest Sessions:</span>
+          <ul className="mt-1 space-y-1">
+            {onlineTime.sessions.map((session) => (
+              <li key={session.loginAt} className="text-[#d0d0d0] text-sm">
+                {formatDateTime(session.loginAt)} –{' '}
+                {session.logoutAt ? formatDateTime(session.logoutAt) : <span className="text-green-400">online</span>}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+    </div>
+  )
+}
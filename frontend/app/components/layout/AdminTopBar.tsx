@@ -2,18 +2,21 @@
 
 import Link from 'next/link'
 import { useRouter } from 'next/navigation'
-import { useState, useRef, useEffect, useCallback, memo } from 'react'
+import { useState, useRef, useEffect, useCallback, useMemo, memo } from 'react'
+import { usePermissions } from '../../hooks/usePermissions'
 
 interface DropdownItem {
 	label: string
 	href: string
 	icon?: string
+	permission?: string
 }
 
 interface NavItem {
 	label: string
 	href?: string
 	icon: string
+	permission?: string
 	dropdown?: DropdownItem[]
 }
 
@@ -22,46 +25,50 @@ const adminNavItems: NavItem[] = [
 		label: 'Dashboard',
 		href: '/admin',
 		icon: '⚡',
+		permission: 'dashboard.view',
 	},
 	{
 		label: 'User Management',
 		icon: '👥',
 		dropdown: [
-			{ label: 'Manage User Accounts', href: '/admin/accounts', icon: '📋' },
-			{ label: 'Manage Player Characters', href: '/admin/players', icon: '📊' },
+			{ label: 'Manage User Accounts', href: '/admin/accounts', icon: '📋', permission: 'accounts.view' },
+			{ label: 'Manage Player Characters', href: '/admin/players', icon: '📊', permission: 'players.view' },
+			{ label: 'Bulk Edit', href: '/admin/bulk-edit', icon: '🛠️', permission: 'players.edit' },
+			{ label: 'Login Lockouts', href: '/admin/lockouts', icon: '🔒', permission: 'lockouts.manage' },
 		],
 	},
 	{
 		label: 'Game Management',
 		icon: '🎮',
 		dropdown: [
-			{ label: 'View All Characters', href: '/admin/characters', icon: '👤' },
-			{ label: 'Manage Bans & Punishments', href: '/admin/bans', icon: '🚫' },
+			{ label: 'View All Characters', href: '/admin/characters', icon: '👤', permission: 'players.view' },
+			{ label: 'Manage Bans & Punishments', href: '/admin/bans', icon: '🚫', permission: 'accounts.edit' },
 		],
 	},
 	{
 		label: 'Server Configuration',
 		icon: '🖥️',
 		dropdown: [
-			{ label: 'Server Settings', href: '/admin/server', icon: '⚙️' },
-			{ label: 'View Server Logs', href: '/admin/logs', icon: '📝' },
+			{ label: 'Server Settings', href: '/admin/server', icon: '⚙️', permission: 'maintenance.manage' },
+			{ label: 'View Server Logs', href: '/admin/logs', icon: '📝', permission: 'logs.view' },
 		],
 	},
 	{
 		label: 'System Operations',
 		icon: '🔧',
 		dropdown: [
-			{ label: 'Maintenance Mode', href: '/admin/maintenance', icon: '🔧' },
-			{ label: 'Database Backup', href: '/admin/backup', icon: '💾' },
+			{ label: 'Maintenance Mode', href: '/admin/maintenance', icon: '🔧', permission: 'maintenance.manage' },
+			{ label: 'Database Backup', href: '/admin/backup', icon: '💾', permission: 'sql.execute' },
+			{ label: 'Audit Log', href: '/admin/audit', icon: '🧾', permission: 'audit.view' },
 		],
 	},
 	{
 		label: 'Content Management',
 		icon: '📄',
 		dropdown: [
-			{ label: 'Edit Server Rules', href: '/admin/rules', icon: '📜' },
-			{ label: 'Manage News', href: '/admin/news', icon: '📰' },
-			{ label: 'Manage Comments', href: '/admin/comments', icon: '💬' },
+			{ label: 'Edit Server Rules', href: '/admin/rules', icon: '📜', permission: 'pages.manage' },
+			{ label: 'Manage News', href: '/admin/news', icon: '📰', permission: 'news.manage' },
+			{ label: 'Manage Comments', href: '/admin/comments', icon: '💬', permission: 'comments.moderate' },
 		],
 	},
 	{
@@ -155,13 +162,22 @@ export default function AdminTopBar() {
 		router.push(href)
 	}, [router])
 
+	// Only show the sections the account's roles give access to
+	const permissions = usePermissions()
+	const navItems = useMemo(() => adminNavItems
+		.filter(item => !item.permission || permissions.includes(item.permission))
+		.map(item => item.dropdown
+			? { ...item, dropdown: item.dropdown.filter(d => !d.permission || permissions.includes(d.permission)) }
+			: item)
+		.filter(item => !item.dropdown || item.dropdown.length > 0), [permissions])
+
 	return (
 		<div className="fixed top-0 left-0 w-full z-50" ref={containerRef}>
 			<div className="w-full">
 				{/* Admin Navigation Menu */}
 				<div className="bg-[#252525]/95 backdrop-blur-md border-b-2 border-[#ffd700]/30 shadow-xl">
 					<nav className="flex items-center justify-center gap-4 sm:gap-6 lg:gap-8 py-3 flex-wrap">
-						{adminNavItems.map((item) => (
+						{navItems.map((item) => (
 							<NavItemComponent
 								key={item.label}
 								item={item}
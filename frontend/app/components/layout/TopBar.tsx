@@ -58,8 +58,10 @@ const navItems: NavItem[] = [
       { label: 'Online Players', href: '/players-online', icon: '👤' },
       { label: 'Highscores', href: '/community/ranking', icon: '🏆' },
       { label: 'Guilds', href: '/guilds', icon: '🛡️' },
+      { label: 'Char Bazaar', href: '/community/bazaar', icon: '⚖️' },
       { label: 'Houses', href: '/community/houses', icon: '🏠' },
       { label: 'Latest Deaths', href: '/community/deaths', icon: '💀' },
+      { label: 'Top Fraggers', href: '/community/fraggers', icon: '⚔️' },
       { label: 'Banishments', href: '/community/banishments', icon: '🚫' },
     ],
   },
@@ -4,6 +4,7 @@ import { useState, useEffect } from 'react'
 import Link from 'next/link'
 import { api } from '../../services/api'
 import { useAuth } from '../../contexts/AuthContext'
+import { usePermissions } from '../../hooks/usePermissions'
 import { formatRelativeTime } from '../../utils/date'
 import type { News, NewsResponse } from '../../types/news'
 import type { ApiResponse } from '../../types/account'
@@ -13,7 +14,7 @@ export default function NewsSection() {
   const { isAuthenticated } = useAuth()
   const [newsItems, setNewsItems] = useState<News[]>([])
   const [loading, setLoading] = useState(true)
-  const [isAdmin, setIsAdmin] = useState(false)
+  const isAdmin = usePermissions(isAuthenticated).includes('news.manage')
   const [editingId, setEditingId] = useState<number | null>(null)
   const [editTitle, setEditTitle] = useState('')
   const [editContent, setEditContent] = useState('')
@@ -21,19 +22,7 @@ export default function NewsSection() {
 
   useEffect(() => {
     fetchNews()
-    if (isAuthenticated) {
-      checkAdmin()
-    }
-  }, [isAuthenticated])
-
-  const checkAdmin = async () => {
-    try {
-      await api.get('/admin/stats')
-      setIsAdmin(true)
-    } catch {
-      setIsAdmin(false)
-    }
-  }
+  }, [])
 
   const fetchNews = async () => {
     try {
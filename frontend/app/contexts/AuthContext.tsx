@@ -1,7 +1,7 @@
 'use client'
 
 import { createContext, useContext, useState, useEffect, useMemo, useCallback, useRef, ReactNode } from 'react'
-import { authService, isDevelopment } from '../services/auth'
+import { authService } from '../services/auth'
 
 interface AuthContextType {
   isAuthenticated: boolean
@@ -59,19 +59,13 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   const checkAuth = useCallback(async () => {
     setIsLoading(true)
     
-    if (isDevelopment) {
-      const authenticated = authService.isAuthenticated()
+    try {
+      const authenticated = await authService.checkAuthAsync()
       setIsAuthenticated(authenticated)
+    } catch {
+      setIsAuthenticated(false)
+    } finally {
       setIsLoading(false)
-    } else {
-      try {
-        const authenticated = await authService.checkAuthAsync()
-        setIsAuthenticated(authenticated)
-      } catch {
-        setIsAuthenticated(false)
-      } finally {
-        setIsLoading(false)
-      }
     }
   }, [])
 
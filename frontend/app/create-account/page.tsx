@@ -123,7 +123,7 @@ export default function CreateAccountPage() {
         }
         setAuthenticated(true)
         setIsSuccess(true)
-        setSuccessMessage('Account created successfully! Redirecting...')
+        setSuccessMessage(response.message || 'Account created successfully! Redirecting...')
         
         setTimeout(() => {
           router.replace('/account')
@@ -0,0 +1,27 @@
+'use client'
+
+import { useState, useEffect } from 'react'
+import { api } from '../services/api'
+import type { AccountApiResponse } from '../types/account'
+
+/**
+ * Custom hook to get the admin permissions of the logged in account
+ * @param {boolean} enabled - Set to false to skip the request (e.g. while logged out)
+ * @returns {string[]} Permission names such as 'news.manage' (empty while loading or on error)
+ */
+export function usePermissions(enabled: boolean = true): string[] {
+  const [permissions, setPermissions] = useState<string[]>([])
+
+  useEffect(() => {
+    if (!enabled) {
+      setPermissions([])
+      return
+    }
+
+    api.get<AccountApiResponse>('/account')
+      .then((response) => setPermissions(response?.data?.permissions || []))
+      .catch(() => setPermissions([]))
+  }, [enabled])
+
+  return permissions
+}
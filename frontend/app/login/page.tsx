@@ -6,10 +6,13 @@ import { useRouter, useSearchParams } from 'next/navigation'
 import { api } from '../services/api'
 import { authService } from '../services/auth'
 import { useAuth } from '../contexts/AuthContext'
+import { isPasskeySupported, getPasskeyAssertion, type PasskeyCeremony } from '../utils/passkey'
 
 interface LoginResponse {
   token?: string
   requires2FA?: boolean
+  passkeyAvailable?: boolean
+  passkeyRequired?: boolean
   message?: string
 }
 
@@ -24,6 +27,9 @@ function LoginForm() {
   })
   const [twoFactorToken, setTwoFactorToken] = useState('')
   const [requires2FA, setRequires2FA] = useState(false)
+  const [passkeyAvailable, setPasskeyAvailable] = useState(false)
+  const [passkeyRequired, setPasskeyRequired] = useState(false)
+  const [passkeySupported, setPasskeySupported] = useState(false)
   const [error, setError] = useState('')
   const [loading, setLoading] = useState(false)
 
@@ -40,6 +46,10 @@ function LoginForm() {
     }
   }, [searchParams, router, isAuthenticated, isLoading])
 
+  useEffect(() => {
+    setPasskeySupported(isPasskeySupported())
+  }, [])
+
   const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
     const { name, value, type, checked } = e.target
     setFormData(prev => ({
@@ -62,16 +72,13 @@ function LoginForm() {
 
       if (data.requires2FA) {
         setRequires2FA(true)
+        setPasskeyAvailable(!!data.passkeyAvailable)
+        setPasskeyRequired(!!data.passkeyRequired)
         setError('')
         return
       }
 
-      // Save token (dev only) and update context
-      if (data.token) {
-        authService.saveToken(data.token)
-      }
-      setAuthenticated(true)
-      router.push('/account')
+      completeLogin(data)
     } catch (err: any) {
       setError(err.message || 'Invalid email or password. Please try again.')
       if (err.message && !err.message.includes('2FA')) {
@@ -83,6 +90,43 @@ function LoginForm() {
     }
   }
 
+  const completeLogin = (data: LoginResponse) => {
+    // Save token (dev only) and update context
+    if (data.token) {
+      authService.saveToken(data.token)
+    }
+    setAuthenticated(true)
+    router.push('/account')
+  }
+
+  // Without a password the passkey is the whole login; after the password it replaces the 2FA code
+  const handlePasskeyLogin = async () => {
+    setError('')
+    setLoading(true)
+
+    try {
+      const begin = await api.post<{ data: PasskeyCeremony }>('/login/passkey/begin', {}, { public: true })
+      const passkey = {
+        ceremony: begin.data.ceremony,
+        credential: await getPasskeyAssertion(begin.data.options),
+      }
+
+      const data = requires2FA
+        ? await api.post<LoginResponse>('/login', {
+            email: formData.email,
+            password: formData.password,
+            passkey,
+          }, { public: true })
+        : await api.post<LoginResponse>('/login/passkey', passkey, { public: true })
+
+      completeLogin(data)
+    } catch (err: any) {
+      setError(err.name === 'NotAllowedError' ? 'Passkey login was cancelled' : (err.message || 'Passkey login failed'))
+    } finally {
+      setLoading(false)
+    }
+  }
+
   if (isLoading) {
     return (
       <div>
@@ -160,7 +204,15 @@ function LoginForm() {
               </div>
 
               {/* 2FA Token */}
-              {requires2FA && (
+              {requires2FA && passkeyRequired && (
+                <p className="text-sm text-[#888]">
+                  {passkeySupported
+                    ? 'Confirm the login with one of the passkeys registered on your account'
+                    : 'This account requires a passkey, but your browser does not support passkeys'}
+                </p>
+              )}
+
+              {requires2FA && !passkeyRequired && (
                 <div>
                   <label htmlFor="twoFactorToken" className="block text-[#e0e0e0] text-sm font-medium mb-2">
                     Two-Factor Authentication Code *
@@ -170,16 +222,16 @@ function LoginForm() {
                     name="twoFactorToken"
                     type="text"
                     value={twoFactorToken}
-                    onChange={(e) => setTwoFactorToken(e.target.value.replace(/\D/g, '').slice(0, 6))}
+                    onChange={(e) => setTwoFactorToken(e.target.value.replace(/[^0-9A-Za-z-]/g, '').toUpperCase().slice(0, 11))}
                     className="w-full bg-[#1a1a1a] border-2 border-[#404040]/60 rounded-lg px-4 py-3 text-[#e0e0e0] focus:outline-none focus:border-[#3b82f6] focus:ring-2 focus:ring-[#3b82f6]/20 transition-all placeholder:text-[#666] text-center text-2xl tracking-widest"
                     placeholder="000000"
-                    maxLength={6}
+                    maxLength={11}
                     disabled={loading}
                     required
                     autoFocus
                   />
                   <p className="mt-2 text-sm text-[#888]">
-                    Enter the 6-digit code from your authenticator app
+                    Enter the 6-digit code from your authenticator app or one of your backup codes
                   </p>
                 </div>
               )}
@@ -209,13 +261,26 @@ function LoginForm() {
               </div>
 
               {/* Submit Button */}
-              <button
-                type="submit"
-                disabled={loading}
-                className="w-full bg-[#3b82f6] hover:bg-[#2563eb] text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg hover:shadow-xl transform hover:scale-[1.02] disabled:opacity-50 disabled:cursor-not-allowed"
-              >
-                {loading ? 'Logging in...' : 'Login'}
-              </button>
+              {!passkeyRequired && (
+                <button
+                  type="submit"
+                  disabled={loading}
+                  className="w-full bg-[#3b82f6] hover:bg-[#2563eb] text-white font-bold py-3 px-4 rounded-lg transition-all shadow-lg hover:shadow-xl transform hover:scale-[1.02] disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  {loading ? 'Logging in...' : 'Login'}
+                </button>
+              )}
+
+              {passkeySupported && (!requires2FA || passkeyAvailable) && (
+                <button
+                  type="button"
+                  onClick={handlePasskeyLogin}
+                  disabled={loading}
+                  className="w-full bg-[#1a1a1a] hover:bg-[#2a2a2a] border-2 border-[#404040]/60 text-[#e0e0e0] font-bold py-3 px-4 rounded-lg transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  {passkeyRequired ? 'Confirm with a passkey' : requires2FA ? 'Use a passkey instead' : 'Sign in with a passkey'}
+                </button>
+              )}
             </form>
 
             <div className="mt-6 text-center">
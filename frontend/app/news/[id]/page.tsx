@@ -5,6 +5,7 @@ import { useParams, useRouter } from 'next/navigation'
 import Link from 'next/link'
 import { api } from '../../services/api'
 import { useAuth } from '../../contexts/AuthContext'
+import { usePermissions } from '../../hooks/usePermissions'
 import { formatRelativeTime, formatDate } from '../../utils/date'
 import type { News, SingleNewsApiResponse } from '../../types/news'
 import type { ApiResponse } from '../../types/account'
@@ -19,7 +20,8 @@ export default function NewsPage() {
 
 	const [news, setNews] = useState<News | null>(null)
 	const [loading, setLoading] = useState(true)
-	const [isAdmin, setIsAdmin] = useState(false)
+	const permissions = usePermissions(isAuthenticated)
+	const isAdmin = permissions.includes('news.manage')
 	const [isEditing, setIsEditing] = useState(false)
 	const [editTitle, setEditTitle] = useState('')
 	const [editContent, setEditContent] = useState('')
@@ -31,19 +33,7 @@ export default function NewsPage() {
 			return
 		}
 		fetchNews()
-		if (isAuthenticated) {
-			checkAdmin()
-		}
-	}, [newsId, isAuthenticated, router])
-
-	const checkAdmin = async () => {
-		try {
-			await api.get('/admin/stats')
-			setIsAdmin(true)
-		} catch {
-			setIsAdmin(false)
-		}
-	}
+	}, [newsId, router])
 
 	const fetchNews = async () => {
 		if (!newsId) return
@@ -235,7 +225,7 @@ export default function NewsPage() {
 
 				{/* Comments Section */}
 				<div className="mt-8 pt-8 border-t border-[#404040]/50">
-					<NewsComments newsId={news.id} isAdmin={isAdmin} />
+					<NewsComments newsId={news.id} isAdmin={permissions.includes('comments.moderate')} />
 				</div>
 			</div>
 		</main>
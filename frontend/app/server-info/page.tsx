@@ -4,6 +4,7 @@ import { useState, useEffect } from 'react'
 import { serverService, ServerConfig, stagesService, StagesConfig } from '../services/server'
 import { useServerName } from '../hooks/useServerName'
 import StagesModal from '../components/server/StagesModal'
+import { formatDateTime } from '../utils/date'
 
 export default function ServerInfoPage() {
   const serverName = useServerName()
@@ -70,6 +71,20 @@ export default function ServerInfoPage() {
           <p className="text-[#d0d0d0] text-sm">Complete server configuration and rates</p>
         </div>
 
+        {/* Online Record */}
+        {config.onlineRecord && (
+          <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl ring-2 ring-[#ffd700]/10 mb-6">
+            <h2 className="text-[#ffd700] text-2xl font-bold mb-6 pb-3 border-b border-[#404040]/40">
+              👥 Online Record
+            </h2>
+            <p className="text-[#d0d0d0]">
+              The record of{' '}
+              <span className="text-[#ffd700] font-bold">{config.onlineRecord.players}</span>{' '}
+              players online at once was reached on {formatDateTime(config.onlineRecord.recordedAt)}.
+            </p>
+          </div>
+        )}
+
         {/* Server Rates Section - Only show if rateUseStages is false */}
         {!config.rateUseStages && (
           <div className="bg-[#252525]/95 backdrop-blur-sm rounded-xl border-2 border-[#505050]/70 p-6 shadow-2xl ring-2 ring-[#ffd700]/10 mb-6">
@@ -1,11 +1,14 @@
 import { authService, isDevelopment } from './auth';
 import { authStateManager } from '../contexts/AuthContext';
+import { solveChallenge } from '../utils/challenge';
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api';
 
 interface RequestOptions extends RequestInit {
     headers?: Record<string, string>;
     public?: boolean;
+    retried?: boolean;
+    challenged?: boolean;
 }
 
 class ApiService {
@@ -19,7 +22,7 @@ class ApiService {
 
 
         if (!isPublic && isDevelopment) {
-          if (authService.isTokenExpired() && authService.isAuthenticated()) {
+          if (authService.getToken() && authService.isTokenExpired() && !(await authService.refresh())) {
             authService.removeToken();
             if (typeof window !== 'undefined') {
               window.location.href = '/login?expired=true';
@@ -33,7 +36,7 @@ class ApiService {
             }
         }
 
-        const { public: _, ...fetchOptions } = options;
+        const { public: _, retried, challenged, ...fetchOptions } = options;
 
         const response = await fetch(url, {
             ...fetchOptions,
@@ -62,7 +65,24 @@ class ApiService {
                 throw error;
             }
 
+            if (response.status === 428 && data.data?.challenge && !challenged) {
+                // Too many attempts from this client: solve the challenge and send the request again
+                const challengeHeaders = await solveChallenge(data.data.challenge);
+                if (challengeHeaders) {
+                    return this.request<T>(endpoint, {
+                        ...options,
+                        challenged: true,
+                        headers: { ...options.headers, ...challengeHeaders },
+                    });
+                }
+            }
+
             if (response.status === 401 && !isPublic) {
+                // Access tokens are short-lived; try once with a fresh one before giving up
+                if (!retried && await authService.refresh()) {
+                    return this.request<T>(endpoint, { ...options, retried: true });
+                }
+
                 authStateManager.notifyUnauthorized()
                 if (typeof window !== 'undefined') {
                     const currentPath = window.location.pathname;
@@ -1,6 +1,8 @@
 const TOKEN_KEY = 'token';
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api';
 
+let refreshPromise: Promise<boolean> | null = null;
+
 export const isDevelopment = typeof window !== 'undefined' && 
   (window.location.hostname === 'localhost' || window.location.hostname === '127.0.0.1');
 
@@ -51,9 +53,44 @@ export const authService = {
     return false;
   },
 
+  // Exchanges the refresh token cookie for a new access token. Concurrent callers share one request
+  // because the refresh token is rotated on every use.
+  refresh(): Promise<boolean> {
+    if (refreshPromise) {
+      return refreshPromise;
+    }
+
+    refreshPromise = (async () => {
+      try {
+        const response = await fetch(`${API_URL}/auth/refresh`, {
+          method: 'POST',
+          credentials: 'include',
+        });
+        if (!response.ok) {
+          this.removeToken();
+          return false;
+        }
+        const data = await response.json();
+        if (data.token) {
+          this.saveToken(data.token);
+        }
+        return true;
+      } catch {
+        return false;
+      } finally {
+        refreshPromise = null;
+      }
+    })();
+
+    return refreshPromise;
+  },
+
   async checkAuthAsync(): Promise<boolean> {
     if (isDevelopment) {
-      return this.isAuthenticated();
+      if (this.isAuthenticated()) {
+        return true;
+      }
+      return this.getToken() ? this.refresh() : false;
     }
 
     try {
@@ -61,6 +98,9 @@ export const authService = {
         method: 'GET',
         credentials: 'include',
       });
+      if (response.status === 401 && await this.refresh()) {
+        return true;
+      }
       return response.ok;
     } catch {
       return false;
@@ -32,6 +32,12 @@ export interface ServerConfig {
   weekKillsToRedSkull: number
   monthKillsToRedSkull: number
   minLevelToCreateGuild?: number
+  onlineRecord?: OnlineRecord | null
+}
+
+export interface OnlineRecord {
+  players: number
+  recordedAt: number
 }
 
 let cachedConfig: ServerConfig | null = null
@@ -19,6 +19,7 @@ export interface AccountInfo {
     deletionScheduledAt?: number
     status: string
     pageAccess?: number
+    permissions?: string[]
 }
 
 export interface ApiResponse<T> {
@@ -77,3 +77,79 @@ export interface AdminPlayersResponse {
     }
 }
 
+
+export interface LoginLockout {
+    key: string
+    scope?: 'ip' | 'account'
+    identifier?: string
+    failures: number
+    lockouts: number
+    lockedUntil: string
+    lastFailure: string
+}
+
+export interface AdminRole {
+    name: string
+    label: string
+    permissions: string[]
+}
+
+export interface AccountRoles {
+    accountId: number
+    roles: string[]
+}
+
+export interface AuditChange {
+    before?: unknown
+    after?: unknown
+}
+
+export interface AuditLogEntry {
+    id: number
+    actorId: number
+    actorEmail: string
+    action: string
+    targetType: string
+    targetId: string
+    changes: Record<string, AuditChange> | null
+    ip: string
+    createdAt: string
+}
+
+export interface AuditLogResponse {
+    entries: AuditLogEntry[]
+    pagination: {
+        page: number
+        limit: number
+        total: number
+        totalPages: number
+    }
+}
+
+export type BulkEditTarget = 'accounts' | 'players'
+
+export interface BulkAssignment {
+    field: string
+    op: 'set' | 'add'
+    value: number | string
+}
+
+export interface BulkCondition {
+    field: string
+    op: 'eq' | 'ne' | 'lt' | 'lte' | 'gt' | 'gte' | 'in' | 'like'
+    value: number | string | Array<number | string>
+}
+
+export interface BulkEditPreviewRow {
+    id: number
+    label: string
+    before: Record<string, string>
+    after: Record<string, string>
+}
+
+export interface BulkEditResponse {
+    dryRun: boolean
+    matched: number
+    rowsAffected: number
+    preview?: BulkEditPreviewRow[]
+}
@@ -0,0 +1,45 @@
+import type { CharacterDetailsResponse, PaginationInfo } from './character'
+
+export type AuctionStatus = 'active' | 'sold' | 'expired' | 'cancelled' | 'failed'
+
+export interface Auction {
+    id: number
+    name: string
+    level: number
+    vocation: string
+    lookType: number
+    lookHead: number
+    lookBody: number
+    lookLegs: number
+    lookFeet: number
+    lookAddons: number
+    minimumBid: number
+    currentBid: number
+    bidCount: number
+    status: AuctionStatus
+    createdAt: number
+    endsAt: number
+    isSeller?: boolean
+    isHighestBidder?: boolean
+}
+
+export interface AuctionBid {
+    amount: number
+    time: number
+}
+
+export interface AuctionsResponse {
+    auctions: Auction[]
+    pagination: PaginationInfo
+}
+
+export interface AuctionDetailsResponse {
+    auction: Auction
+    character: CharacterDetailsResponse | null
+    bids: AuctionBid[]
+}
+
+export interface MyAuctionsResponse {
+    auctions: Auction[]
+    maxAuctionDays: number
+}
@@ -14,6 +14,8 @@ export interface Character extends Outfit {
   level: number
   world: string
   status: 'online' | 'offline'
+  hidden: boolean
+  deletionScheduledAt?: number
 }
 
 export interface CharacterDetails extends Outfit {
@@ -45,14 +47,104 @@ export interface CharacterDetails extends Outfit {
   experience?: number
   experienceToNextLevel?: number
   equipment?: EquipmentItem[]
+  equipmentSlots?: EquipmentSlots
+  formerNames?: string[]
+  comment?: string
+  realName?: string
+  location?: string
+  isMainCharacter?: boolean
+  achievements?: CharacterAchievements
+  bestiary?: CharacterBestiary
+  charms?: CharacterCharms
+}
+
+export interface ExperienceHistoryPoint {
+  day: string
+  level: number
+  experience: number
+}
+
+export interface ExperienceHistoryResponse {
+  name: string
+  days: number
+  history: ExperienceHistoryPoint[]
+}
+
+export interface OnlineDay {
+  day: string
+  seconds: number
+  hours: number
+}
+
+export interface OnlineSession {
+  loginAt: number
+  logoutAt: number | null
+}
+
+export interface CharacterOnlineTime {
+  name: string
+  days: number
+  totalSeconds: number
+  totalHours: number
+  history: OnlineDay[]
+  sessions: OnlineSession[]
+}
+
+export interface CompletedAchievement {
+  name: string
+  description?: string
+  points: number
+  secret: boolean
+}
+
+export interface CharacterAchievements {
+  points: number
+  completed: number
+  total: number
+  achievements: CompletedAchievement[]
+}
+
+export interface CharacterBestiary {
+  known: number
+  completed: number
+  total: number
+  kills: number
+}
+
+export interface CharacterCharms {
+  unlocked: string[]
+  total: number
+}
+
+export interface CharacterProfile {
+  comment: string
+  realName: string
+  location: string
+  isMainCharacter: boolean
 }
 
 export interface EquipmentItem {
   slot: number
+  slotName: string
   itemId: number
+  clientId: number
+  name?: string
   count: number
 }
 
+export interface EquipmentSlots {
+  head: EquipmentItem | null
+  necklace: EquipmentItem | null
+  backpack: EquipmentItem | null
+  armor: EquipmentItem | null
+  rightHand: EquipmentItem | null
+  leftHand: EquipmentItem | null
+  legs: EquipmentItem | null
+  feet: EquipmentItem | null
+  ring: EquipmentItem | null
+  ammo: EquipmentItem | null
+}
+
 export interface OnlinePlayer extends Outfit {
   name: string
   level: number
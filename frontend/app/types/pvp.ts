@@ -0,0 +1,43 @@
+import { Outfit } from './character'
+
+export type FragPeriod = 'day' | 'week' | 'month'
+
+export interface FragCount {
+  frags: number
+  killsToRedSkull: number
+}
+
+export interface PvPVictim {
+  name: string
+  kills: number
+}
+
+export interface CharacterPvPStats {
+  name: string
+  kills: number
+  unjustifiedKills: number
+  deaths: number
+  pvpDeaths: number
+  activeFrags: number
+  fragDuration: number
+  day: FragCount
+  week: FragCount
+  month: FragCount
+  mostKilled: PvPVictim[]
+}
+
+export interface TopFragger extends Outfit {
+  rank: number
+  name: string
+  vocation: string
+  level: number
+  kills: number
+  unjustifiedKills: number
+}
+
+export interface TopFraggersResponse {
+  period: FragPeriod
+  since: number
+  killsToRedSkull: number
+  players: TopFragger[]
+}
@@ -25,3 +25,6 @@ export type RankingType =
   | 'distance'
   | 'fist'
   | 'fishing'
+  | 'achievements'
+  | 'gainedtoday'
+  | 'gainedweek'
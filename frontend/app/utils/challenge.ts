@@ -0,0 +1,41 @@
+export interface Challenge {
+  type: string
+  token: string
+  params?: { algorithm?: string; difficulty?: number }
+}
+
+const leadingZeroBits = (hash: Uint8Array): number => {
+  let count = 0
+  for (const byte of hash) {
+    if (byte !== 0) {
+      return count + Math.clz32(byte) - 24
+    }
+    count += 8
+  }
+  return count
+}
+
+// Finds a counter whose SHA-256 of "<token>:<counter>" starts with `difficulty` zero bits
+export const solveProofOfWork = async (token: string, difficulty: number): Promise<string> => {
+  const encoder = new TextEncoder()
+  for (let counter = 0; ; counter++) {
+    const solution = counter.toString(36)
+    const hash = await crypto.subtle.digest('SHA-256', encoder.encode(`${token}:${solution}`))
+    if (leadingZeroBits(new Uint8Array(hash)) >= difficulty) {
+      return solution
+    }
+  }
+}
+
+// Returns the headers that answer a challenge, or null when the challenge type isn't supported
+export const solveChallenge = async (challenge: Challenge): Promise<Record<string, string> | null> => {
+  if (challenge.type !== 'pow') {
+    return null
+  }
+
+  const solution = await solveProofOfWork(challenge.token, challenge.params?.difficulty ?? 0)
+  return {
+    'X-Challenge-Token': challenge.token,
+    'X-Challenge-Solution': solution,
+  }
+}
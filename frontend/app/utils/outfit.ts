@@ -7,26 +7,28 @@ export interface OutfitParams {
   feet: number
 }
 
-export const makeOutfit = (params: OutfitParams): string => {
-  const baseUrl = process.env.NEXT_PUBLIC_OUTFIT_IMAGE_BASE_URL?.trim()
-
-  if (!baseUrl) {
-    throw new Error('NEXT_PUBLIC_OUTFIT_IMAGE_BASE_URL is not defined in environment variables.')
-  }
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api'
 
+// Outfit images are rendered and cached by the backend from the server's own sprites (OUTFIT_SPRITES_PATH).
+// While NEXT_PUBLIC_OUTFIT_IMAGE_BASE_URL is set, they are loaded from that external renderer instead.
+export const makeOutfit = (params: OutfitParams): string => {
   const { id, addons, head, body, legs, feet } = params
+  const externalBaseUrl = process.env.NEXT_PUBLIC_OUTFIT_IMAGE_BASE_URL?.trim()
 
-  const url = new URL(baseUrl)
   const searchParams = new URLSearchParams()
 
-  searchParams.set('id', String(id))
+  searchParams.set(externalBaseUrl ? 'id' : 'looktype', String(id))
   searchParams.set('addons', String(addons))
   searchParams.set('head', String(head))
   searchParams.set('body', String(body))
   searchParams.set('legs', String(legs))
   searchParams.set('feet', String(feet))
 
-  url.search = searchParams.toString()
+  if (externalBaseUrl) {
+    const url = new URL(externalBaseUrl)
+    url.search = searchParams.toString()
+    return url.toString()
+  }
 
-  return url.toString()
+  return `${API_URL}/outfit.png?${searchParams.toString()}`
 }
@@ -0,0 +1,93 @@
+// Helpers to pass WebAuthn options and credentials between the API (base64url JSON) and the browser (ArrayBuffers)
+
+export interface PasskeyCeremony {
+  ceremony: string
+  options: { publicKey: any }
+}
+
+const toBuffer = (value: string): ArrayBuffer => {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+  const padded = base64 + '='.repeat((4 - (base64.length % 4)) % 4)
+  const binary = atob(padded)
+  const bytes = new Uint8Array(binary.length)
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i)
+  }
+  return bytes.buffer
+}
+
+const toBase64URL = (buffer: ArrayBuffer | null): string | undefined => {
+  if (!buffer) {
+    return undefined
+  }
+  let binary = ''
+  for (const byte of new Uint8Array(buffer)) {
+    binary += String.fromCharCode(byte)
+  }
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+}
+
+export const isPasskeySupported = (): boolean =>
+  typeof window !== 'undefined' && typeof window.PublicKeyCredential !== 'undefined'
+
+// Runs navigator.credentials.create() and returns the new credential in the JSON form the API expects
+export const createPasskey = async (options: { publicKey: any }) => {
+  const publicKey = options.publicKey
+  const credential = await navigator.credentials.create({
+    publicKey: {
+      ...publicKey,
+      challenge: toBuffer(publicKey.challenge),
+      user: { ...publicKey.user, id: toBuffer(publicKey.user.id) },
+      excludeCredentials: (publicKey.excludeCredentials || []).map((c: any) => ({ ...c, id: toBuffer(c.id) })),
+    },
+  }) as PublicKeyCredential | null
+
+  if (!credential) {
+    throw new Error('Passkey creation was cancelled')
+  }
+
+  const response = credential.response as AuthenticatorAttestationResponse
+  return {
+    id: credential.id,
+    rawId: toBase64URL(credential.rawId),
+    type: credential.type,
+    authenticatorAttachment: credential.authenticatorAttachment ?? undefined,
+    clientExtensionResults: credential.getClientExtensionResults(),
+    response: {
+      clientDataJSON: toBase64URL(response.clientDataJSON),
+      attestationObject: toBase64URL(response.attestationObject),
+      transports: typeof response.getTransports === 'function' ? response.getTransports() : undefined,
+    },
+  }
+}
+
+// Runs navigator.credentials.get() and returns the assertion in the JSON form the API expects
+export const getPasskeyAssertion = async (options: { publicKey: any }) => {
+  const publicKey = options.publicKey
+  const credential = await navigator.credentials.get({
+    publicKey: {
+      ...publicKey,
+      challenge: toBuffer(publicKey.challenge),
+      allowCredentials: (publicKey.allowCredentials || []).map((c: any) => ({ ...c, id: toBuffer(c.id) })),
+    },
+  }) as PublicKeyCredential | null
+
+  if (!credential) {
+    throw new Error('Passkey login was cancelled')
+  }
+
+  const response = credential.response as AuthenticatorAssertionResponse
+  return {
+    id: credential.id,
+    rawId: toBase64URL(credential.rawId),
+    type: credential.type,
+    authenticatorAttachment: credential.authenticatorAttachment ?? undefined,
+    clientExtensionResults: credential.getClientExtensionResults(),
+    response: {
+      clientDataJSON: toBase64URL(response.clientDataJSON),
+      authenticatorData: toBase64URL(response.authenticatorData),
+      signature: toBase64URL(response.signature),
+      userHandle: toBase64URL(response.userHandle),
+    },
+  }
+}